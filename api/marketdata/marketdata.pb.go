@@ -0,0 +1,1715 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: api/marketdata/marketdata.proto
+
+package marketdata
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+import (
+	context "golang.org/x/net/context"
+	grpc "google.golang.org/grpc"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type WithdrawRequest_Status int32
+
+const (
+	WithdrawRequest_REQUESTED WithdrawRequest_Status = 0
+	WithdrawRequest_SIGNED    WithdrawRequest_Status = 1
+	WithdrawRequest_PROCESSED WithdrawRequest_Status = 2
+)
+
+var WithdrawRequest_Status_name = map[int32]string{
+	0: "REQUESTED",
+	1: "SIGNED",
+	2: "PROCESSED",
+}
+var WithdrawRequest_Status_value = map[string]int32{
+	"REQUESTED": 0,
+	"SIGNED":    1,
+	"PROCESSED": 2,
+}
+
+func (x WithdrawRequest_Status) String() string {
+	return proto.EnumName(WithdrawRequest_Status_name, int32(x))
+}
+func (WithdrawRequest_Status) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_marketdata_5ca7d973b81e45ea, []int{15, 0}
+}
+
+type Market struct {
+	Token                string   `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	Base                 string   `protobuf:"bytes,2,opt,name=base,proto3" json:"base,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Market) Reset()         { *m = Market{} }
+func (m *Market) String() string { return proto.CompactTextString(m) }
+func (*Market) ProtoMessage()    {}
+func (*Market) Descriptor() ([]byte, []int) {
+	return fileDescriptor_marketdata_5ca7d973b81e45ea, []int{0}
+}
+func (m *Market) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Market.Unmarshal(m, b)
+}
+func (m *Market) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Market.Marshal(b, m, deterministic)
+}
+func (dst *Market) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Market.Merge(dst, src)
+}
+func (m *Market) XXX_Size() int {
+	return xxx_messageInfo_Market.Size(m)
+}
+func (m *Market) XXX_DiscardUnknown() {
+	xxx_messageInfo_Market.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Market proto.InternalMessageInfo
+
+func (m *Market) GetToken() string {
+	if m != nil {
+		return m.Token
+	}
+	return ""
+}
+
+func (m *Market) GetBase() string {
+	if m != nil {
+		return m.Base
+	}
+	return ""
+}
+
+type OrderbookRequest struct {
+	Market *Market `protobuf:"bytes,1,opt,name=market,proto3" json:"market,omitempty"`
+	// Number of price levels on each side, 0 for the default
+	Depth                uint32   `protobuf:"varint,2,opt,name=depth,proto3" json:"depth,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *OrderbookRequest) Reset()         { *m = OrderbookRequest{} }
+func (m *OrderbookRequest) String() string { return proto.CompactTextString(m) }
+func (*OrderbookRequest) ProtoMessage()    {}
+func (*OrderbookRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_marketdata_5ca7d973b81e45ea, []int{1}
+}
+func (m *OrderbookRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_OrderbookRequest.Unmarshal(m, b)
+}
+func (m *OrderbookRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_OrderbookRequest.Marshal(b, m, deterministic)
+}
+func (dst *OrderbookRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OrderbookRequest.Merge(dst, src)
+}
+func (m *OrderbookRequest) XXX_Size() int {
+	return xxx_messageInfo_OrderbookRequest.Size(m)
+}
+func (m *OrderbookRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_OrderbookRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_OrderbookRequest proto.InternalMessageInfo
+
+func (m *OrderbookRequest) GetMarket() *Market {
+	if m != nil {
+		return m.Market
+	}
+	return nil
+}
+
+func (m *OrderbookRequest) GetDepth() uint32 {
+	if m != nil {
+		return m.Depth
+	}
+	return 0
+}
+
+type OrderbookLevel struct {
+	Price                string   `protobuf:"bytes,1,opt,name=price,proto3" json:"price,omitempty"`
+	Quantity             string   `protobuf:"bytes,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Volume               string   `protobuf:"bytes,3,opt,name=volume,proto3" json:"volume,omitempty"`
+	CumulativeQuantity   string   `protobuf:"bytes,4,opt,name=cumulative_quantity,json=cumulativeQuantity,proto3" json:"cumulative_quantity,omitempty"`
+	CumulativeVolume     string   `protobuf:"bytes,5,opt,name=cumulative_volume,json=cumulativeVolume,proto3" json:"cumulative_volume,omitempty"`
+	Orders               int64    `protobuf:"varint,6,opt,name=orders,proto3" json:"orders,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *OrderbookLevel) Reset()         { *m = OrderbookLevel{} }
+func (m *OrderbookLevel) String() string { return proto.CompactTextString(m) }
+func (*OrderbookLevel) ProtoMessage()    {}
+func (*OrderbookLevel) Descriptor() ([]byte, []int) {
+	return fileDescriptor_marketdata_5ca7d973b81e45ea, []int{2}
+}
+func (m *OrderbookLevel) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_OrderbookLevel.Unmarshal(m, b)
+}
+func (m *OrderbookLevel) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_OrderbookLevel.Marshal(b, m, deterministic)
+}
+func (dst *OrderbookLevel) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OrderbookLevel.Merge(dst, src)
+}
+func (m *OrderbookLevel) XXX_Size() int {
+	return xxx_messageInfo_OrderbookLevel.Size(m)
+}
+func (m *OrderbookLevel) XXX_DiscardUnknown() {
+	xxx_messageInfo_OrderbookLevel.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_OrderbookLevel proto.InternalMessageInfo
+
+func (m *OrderbookLevel) GetPrice() string {
+	if m != nil {
+		return m.Price
+	}
+	return ""
+}
+
+func (m *OrderbookLevel) GetQuantity() string {
+	if m != nil {
+		return m.Quantity
+	}
+	return ""
+}
+
+func (m *OrderbookLevel) GetVolume() string {
+	if m != nil {
+		return m.Volume
+	}
+	return ""
+}
+
+func (m *OrderbookLevel) GetCumulativeQuantity() string {
+	if m != nil {
+		return m.CumulativeQuantity
+	}
+	return ""
+}
+
+func (m *OrderbookLevel) GetCumulativeVolume() string {
+	if m != nil {
+		return m.CumulativeVolume
+	}
+	return ""
+}
+
+func (m *OrderbookLevel) GetOrders() int64 {
+	if m != nil {
+		return m.Orders
+	}
+	return 0
+}
+
+type Orderbook struct {
+	Market *Market           `protobuf:"bytes,1,opt,name=market,proto3" json:"market,omitempty"`
+	Bids   []*OrderbookLevel `protobuf:"bytes,2,rep,name=bids,proto3" json:"bids,omitempty"`
+	Asks   []*OrderbookLevel `protobuf:"bytes,3,rep,name=asks,proto3" json:"asks,omitempty"`
+	// Empty if the market has no trades
+	LastPrice            string   `protobuf:"bytes,4,opt,name=last_price,json=lastPrice,proto3" json:"last_price,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Orderbook) Reset()         { *m = Orderbook{} }
+func (m *Orderbook) String() string { return proto.CompactTextString(m) }
+func (*Orderbook) ProtoMessage()    {}
+func (*Orderbook) Descriptor() ([]byte, []int) {
+	return fileDescriptor_marketdata_5ca7d973b81e45ea, []int{3}
+}
+func (m *Orderbook) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Orderbook.Unmarshal(m, b)
+}
+func (m *Orderbook) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Orderbook.Marshal(b, m, deterministic)
+}
+func (dst *Orderbook) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Orderbook.Merge(dst, src)
+}
+func (m *Orderbook) XXX_Size() int {
+	return xxx_messageInfo_Orderbook.Size(m)
+}
+func (m *Orderbook) XXX_DiscardUnknown() {
+	xxx_messageInfo_Orderbook.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Orderbook proto.InternalMessageInfo
+
+func (m *Orderbook) GetMarket() *Market {
+	if m != nil {
+		return m.Market
+	}
+	return nil
+}
+
+func (m *Orderbook) GetBids() []*OrderbookLevel {
+	if m != nil {
+		return m.Bids
+	}
+	return nil
+}
+
+func (m *Orderbook) GetAsks() []*OrderbookLevel {
+	if m != nil {
+		return m.Asks
+	}
+	return nil
+}
+
+func (m *Orderbook) GetLastPrice() string {
+	if m != nil {
+		return m.LastPrice
+	}
+	return ""
+}
+
+type TradesRequest struct {
+	Market *Market `protobuf:"bytes,1,opt,name=market,proto3" json:"market,omitempty"`
+	// Number of trades, 0 for the default page size
+	Count uint32 `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	// next_cursor of the previous page
+	Cursor               string   `protobuf:"bytes,3,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TradesRequest) Reset()         { *m = TradesRequest{} }
+func (m *TradesRequest) String() string { return proto.CompactTextString(m) }
+func (*TradesRequest) ProtoMessage()    {}
+func (*TradesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_marketdata_5ca7d973b81e45ea, []int{4}
+}
+func (m *TradesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TradesRequest.Unmarshal(m, b)
+}
+func (m *TradesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TradesRequest.Marshal(b, m, deterministic)
+}
+func (dst *TradesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TradesRequest.Merge(dst, src)
+}
+func (m *TradesRequest) XXX_Size() int {
+	return xxx_messageInfo_TradesRequest.Size(m)
+}
+func (m *TradesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_TradesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TradesRequest proto.InternalMessageInfo
+
+func (m *TradesRequest) GetMarket() *Market {
+	if m != nil {
+		return m.Market
+	}
+	return nil
+}
+
+func (m *TradesRequest) GetCount() uint32 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+func (m *TradesRequest) GetCursor() string {
+	if m != nil {
+		return m.Cursor
+	}
+	return ""
+}
+
+type Trade struct {
+	Market   *Market `protobuf:"bytes,1,opt,name=market,proto3" json:"market,omitempty"`
+	Price    string  `protobuf:"bytes,2,opt,name=price,proto3" json:"price,omitempty"`
+	Volume   string  `protobuf:"bytes,3,opt,name=volume,proto3" json:"volume,omitempty"`
+	TradedAt int64   `protobuf:"varint,4,opt,name=traded_at,json=tradedAt,proto3" json:"traded_at,omitempty"`
+	// Set on streamed trades only
+	BuyOrderHash         string   `protobuf:"bytes,5,opt,name=buy_order_hash,json=buyOrderHash,proto3" json:"buy_order_hash,omitempty"`
+	SellOrderHash        string   `protobuf:"bytes,6,opt,name=sell_order_hash,json=sellOrderHash,proto3" json:"sell_order_hash,omitempty"`
+	TxHash               string   `protobuf:"bytes,7,opt,name=tx_hash,json=txHash,proto3" json:"tx_hash,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Trade) Reset()         { *m = Trade{} }
+func (m *Trade) String() string { return proto.CompactTextString(m) }
+func (*Trade) ProtoMessage()    {}
+func (*Trade) Descriptor() ([]byte, []int) {
+	return fileDescriptor_marketdata_5ca7d973b81e45ea, []int{5}
+}
+func (m *Trade) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Trade.Unmarshal(m, b)
+}
+func (m *Trade) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Trade.Marshal(b, m, deterministic)
+}
+func (dst *Trade) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Trade.Merge(dst, src)
+}
+func (m *Trade) XXX_Size() int {
+	return xxx_messageInfo_Trade.Size(m)
+}
+func (m *Trade) XXX_DiscardUnknown() {
+	xxx_messageInfo_Trade.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Trade proto.InternalMessageInfo
+
+func (m *Trade) GetMarket() *Market {
+	if m != nil {
+		return m.Market
+	}
+	return nil
+}
+
+func (m *Trade) GetPrice() string {
+	if m != nil {
+		return m.Price
+	}
+	return ""
+}
+
+func (m *Trade) GetVolume() string {
+	if m != nil {
+		return m.Volume
+	}
+	return ""
+}
+
+func (m *Trade) GetTradedAt() int64 {
+	if m != nil {
+		return m.TradedAt
+	}
+	return 0
+}
+
+func (m *Trade) GetBuyOrderHash() string {
+	if m != nil {
+		return m.BuyOrderHash
+	}
+	return ""
+}
+
+func (m *Trade) GetSellOrderHash() string {
+	if m != nil {
+		return m.SellOrderHash
+	}
+	return ""
+}
+
+func (m *Trade) GetTxHash() string {
+	if m != nil {
+		return m.TxHash
+	}
+	return ""
+}
+
+type TradesPage struct {
+	Trades []*Trade `protobuf:"bytes,1,rep,name=trades,proto3" json:"trades,omitempty"`
+	// Empty on the last page
+	NextCursor           string   `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TradesPage) Reset()         { *m = TradesPage{} }
+func (m *TradesPage) String() string { return proto.CompactTextString(m) }
+func (*TradesPage) ProtoMessage()    {}
+func (*TradesPage) Descriptor() ([]byte, []int) {
+	return fileDescriptor_marketdata_5ca7d973b81e45ea, []int{6}
+}
+func (m *TradesPage) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TradesPage.Unmarshal(m, b)
+}
+func (m *TradesPage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TradesPage.Marshal(b, m, deterministic)
+}
+func (dst *TradesPage) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TradesPage.Merge(dst, src)
+}
+func (m *TradesPage) XXX_Size() int {
+	return xxx_messageInfo_TradesPage.Size(m)
+}
+func (m *TradesPage) XXX_DiscardUnknown() {
+	xxx_messageInfo_TradesPage.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TradesPage proto.InternalMessageInfo
+
+func (m *TradesPage) GetTrades() []*Trade {
+	if m != nil {
+		return m.Trades
+	}
+	return nil
+}
+
+func (m *TradesPage) GetNextCursor() string {
+	if m != nil {
+		return m.NextCursor
+	}
+	return ""
+}
+
+type CandlesRequest struct {
+	Market *Market `protobuf:"bytes,1,opt,name=market,proto3" json:"market,omitempty"`
+	// One of 1m, 5m, 15m, 1h, 4h, 1d and 1w
+	Resolution string `protobuf:"bytes,2,opt,name=resolution,proto3" json:"resolution,omitempty"`
+	From       int64  `protobuf:"varint,3,opt,name=from,proto3" json:"from,omitempty"`
+	// 0 for now
+	To                   int64    `protobuf:"varint,4,opt,name=to,proto3" json:"to,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CandlesRequest) Reset()         { *m = CandlesRequest{} }
+func (m *CandlesRequest) String() string { return proto.CompactTextString(m) }
+func (*CandlesRequest) ProtoMessage()    {}
+func (*CandlesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_marketdata_5ca7d973b81e45ea, []int{7}
+}
+func (m *CandlesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CandlesRequest.Unmarshal(m, b)
+}
+func (m *CandlesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CandlesRequest.Marshal(b, m, deterministic)
+}
+func (dst *CandlesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CandlesRequest.Merge(dst, src)
+}
+func (m *CandlesRequest) XXX_Size() int {
+	return xxx_messageInfo_CandlesRequest.Size(m)
+}
+func (m *CandlesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CandlesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CandlesRequest proto.InternalMessageInfo
+
+func (m *CandlesRequest) GetMarket() *Market {
+	if m != nil {
+		return m.Market
+	}
+	return nil
+}
+
+func (m *CandlesRequest) GetResolution() string {
+	if m != nil {
+		return m.Resolution
+	}
+	return ""
+}
+
+func (m *CandlesRequest) GetFrom() int64 {
+	if m != nil {
+		return m.From
+	}
+	return 0
+}
+
+func (m *CandlesRequest) GetTo() int64 {
+	if m != nil {
+		return m.To
+	}
+	return 0
+}
+
+type Candle struct {
+	Time                 int64    `protobuf:"varint,1,opt,name=time,proto3" json:"time,omitempty"`
+	Open                 string   `protobuf:"bytes,2,opt,name=open,proto3" json:"open,omitempty"`
+	High                 string   `protobuf:"bytes,3,opt,name=high,proto3" json:"high,omitempty"`
+	Low                  string   `protobuf:"bytes,4,opt,name=low,proto3" json:"low,omitempty"`
+	Close                string   `protobuf:"bytes,5,opt,name=close,proto3" json:"close,omitempty"`
+	Volume               string   `protobuf:"bytes,6,opt,name=volume,proto3" json:"volume,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Candle) Reset()         { *m = Candle{} }
+func (m *Candle) String() string { return proto.CompactTextString(m) }
+func (*Candle) ProtoMessage()    {}
+func (*Candle) Descriptor() ([]byte, []int) {
+	return fileDescriptor_marketdata_5ca7d973b81e45ea, []int{8}
+}
+func (m *Candle) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Candle.Unmarshal(m, b)
+}
+func (m *Candle) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Candle.Marshal(b, m, deterministic)
+}
+func (dst *Candle) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Candle.Merge(dst, src)
+}
+func (m *Candle) XXX_Size() int {
+	return xxx_messageInfo_Candle.Size(m)
+}
+func (m *Candle) XXX_DiscardUnknown() {
+	xxx_messageInfo_Candle.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Candle proto.InternalMessageInfo
+
+func (m *Candle) GetTime() int64 {
+	if m != nil {
+		return m.Time
+	}
+	return 0
+}
+
+func (m *Candle) GetOpen() string {
+	if m != nil {
+		return m.Open
+	}
+	return ""
+}
+
+func (m *Candle) GetHigh() string {
+	if m != nil {
+		return m.High
+	}
+	return ""
+}
+
+func (m *Candle) GetLow() string {
+	if m != nil {
+		return m.Low
+	}
+	return ""
+}
+
+func (m *Candle) GetClose() string {
+	if m != nil {
+		return m.Close
+	}
+	return ""
+}
+
+func (m *Candle) GetVolume() string {
+	if m != nil {
+		return m.Volume
+	}
+	return ""
+}
+
+type Candles struct {
+	Candles              []*Candle `protobuf:"bytes,1,rep,name=candles,proto3" json:"candles,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *Candles) Reset()         { *m = Candles{} }
+func (m *Candles) String() string { return proto.CompactTextString(m) }
+func (*Candles) ProtoMessage()    {}
+func (*Candles) Descriptor() ([]byte, []int) {
+	return fileDescriptor_marketdata_5ca7d973b81e45ea, []int{9}
+}
+func (m *Candles) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Candles.Unmarshal(m, b)
+}
+func (m *Candles) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Candles.Marshal(b, m, deterministic)
+}
+func (dst *Candles) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Candles.Merge(dst, src)
+}
+func (m *Candles) XXX_Size() int {
+	return xxx_messageInfo_Candles.Size(m)
+}
+func (m *Candles) XXX_DiscardUnknown() {
+	xxx_messageInfo_Candles.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Candles proto.InternalMessageInfo
+
+func (m *Candles) GetCandles() []*Candle {
+	if m != nil {
+		return m.Candles
+	}
+	return nil
+}
+
+type BalancesRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BalancesRequest) Reset()         { *m = BalancesRequest{} }
+func (m *BalancesRequest) String() string { return proto.CompactTextString(m) }
+func (*BalancesRequest) ProtoMessage()    {}
+func (*BalancesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_marketdata_5ca7d973b81e45ea, []int{10}
+}
+func (m *BalancesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BalancesRequest.Unmarshal(m, b)
+}
+func (m *BalancesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BalancesRequest.Marshal(b, m, deterministic)
+}
+func (dst *BalancesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BalancesRequest.Merge(dst, src)
+}
+func (m *BalancesRequest) XXX_Size() int {
+	return xxx_messageInfo_BalancesRequest.Size(m)
+}
+func (m *BalancesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BalancesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BalancesRequest proto.InternalMessageInfo
+
+type Balance struct {
+	Token                string   `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	Balance              string   `protobuf:"bytes,2,opt,name=balance,proto3" json:"balance,omitempty"`
+	Escrow               string   `protobuf:"bytes,3,opt,name=escrow,proto3" json:"escrow,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Balance) Reset()         { *m = Balance{} }
+func (m *Balance) String() string { return proto.CompactTextString(m) }
+func (*Balance) ProtoMessage()    {}
+func (*Balance) Descriptor() ([]byte, []int) {
+	return fileDescriptor_marketdata_5ca7d973b81e45ea, []int{11}
+}
+func (m *Balance) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Balance.Unmarshal(m, b)
+}
+func (m *Balance) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Balance.Marshal(b, m, deterministic)
+}
+func (dst *Balance) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Balance.Merge(dst, src)
+}
+func (m *Balance) XXX_Size() int {
+	return xxx_messageInfo_Balance.Size(m)
+}
+func (m *Balance) XXX_DiscardUnknown() {
+	xxx_messageInfo_Balance.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Balance proto.InternalMessageInfo
+
+func (m *Balance) GetToken() string {
+	if m != nil {
+		return m.Token
+	}
+	return ""
+}
+
+func (m *Balance) GetBalance() string {
+	if m != nil {
+		return m.Balance
+	}
+	return ""
+}
+
+func (m *Balance) GetEscrow() string {
+	if m != nil {
+		return m.Escrow
+	}
+	return ""
+}
+
+type Balances struct {
+	Wallet               string     `protobuf:"bytes,1,opt,name=wallet,proto3" json:"wallet,omitempty"`
+	Balances             []*Balance `protobuf:"bytes,2,rep,name=balances,proto3" json:"balances,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *Balances) Reset()         { *m = Balances{} }
+func (m *Balances) String() string { return proto.CompactTextString(m) }
+func (*Balances) ProtoMessage()    {}
+func (*Balances) Descriptor() ([]byte, []int) {
+	return fileDescriptor_marketdata_5ca7d973b81e45ea, []int{12}
+}
+func (m *Balances) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Balances.Unmarshal(m, b)
+}
+func (m *Balances) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Balances.Marshal(b, m, deterministic)
+}
+func (dst *Balances) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Balances.Merge(dst, src)
+}
+func (m *Balances) XXX_Size() int {
+	return xxx_messageInfo_Balances.Size(m)
+}
+func (m *Balances) XXX_DiscardUnknown() {
+	xxx_messageInfo_Balances.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Balances proto.InternalMessageInfo
+
+func (m *Balances) GetWallet() string {
+	if m != nil {
+		return m.Wallet
+	}
+	return ""
+}
+
+func (m *Balances) GetBalances() []*Balance {
+	if m != nil {
+		return m.Balances
+	}
+	return nil
+}
+
+type WithdrawRequestsRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WithdrawRequestsRequest) Reset()         { *m = WithdrawRequestsRequest{} }
+func (m *WithdrawRequestsRequest) String() string { return proto.CompactTextString(m) }
+func (*WithdrawRequestsRequest) ProtoMessage()    {}
+func (*WithdrawRequestsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_marketdata_5ca7d973b81e45ea, []int{13}
+}
+func (m *WithdrawRequestsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WithdrawRequestsRequest.Unmarshal(m, b)
+}
+func (m *WithdrawRequestsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WithdrawRequestsRequest.Marshal(b, m, deterministic)
+}
+func (dst *WithdrawRequestsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WithdrawRequestsRequest.Merge(dst, src)
+}
+func (m *WithdrawRequestsRequest) XXX_Size() int {
+	return xxx_messageInfo_WithdrawRequestsRequest.Size(m)
+}
+func (m *WithdrawRequestsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_WithdrawRequestsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WithdrawRequestsRequest proto.InternalMessageInfo
+
+type WithdrawStatusRequest struct {
+	TxHash               string   `protobuf:"bytes,1,opt,name=tx_hash,json=txHash,proto3" json:"tx_hash,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WithdrawStatusRequest) Reset()         { *m = WithdrawStatusRequest{} }
+func (m *WithdrawStatusRequest) String() string { return proto.CompactTextString(m) }
+func (*WithdrawStatusRequest) ProtoMessage()    {}
+func (*WithdrawStatusRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_marketdata_5ca7d973b81e45ea, []int{14}
+}
+func (m *WithdrawStatusRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WithdrawStatusRequest.Unmarshal(m, b)
+}
+func (m *WithdrawStatusRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WithdrawStatusRequest.Marshal(b, m, deterministic)
+}
+func (dst *WithdrawStatusRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WithdrawStatusRequest.Merge(dst, src)
+}
+func (m *WithdrawStatusRequest) XXX_Size() int {
+	return xxx_messageInfo_WithdrawStatusRequest.Size(m)
+}
+func (m *WithdrawStatusRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_WithdrawStatusRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WithdrawStatusRequest proto.InternalMessageInfo
+
+func (m *WithdrawStatusRequest) GetTxHash() string {
+	if m != nil {
+		return m.TxHash
+	}
+	return ""
+}
+
+type WithdrawRequest struct {
+	TxHash    string                 `protobuf:"bytes,1,opt,name=tx_hash,json=txHash,proto3" json:"tx_hash,omitempty"`
+	Token     string                 `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"`
+	Recipient string                 `protobuf:"bytes,3,opt,name=recipient,proto3" json:"recipient,omitempty"`
+	Amount    string                 `protobuf:"bytes,4,opt,name=amount,proto3" json:"amount,omitempty"`
+	Status    WithdrawRequest_Status `protobuf:"varint,5,opt,name=status,proto3,enum=marketdata.WithdrawRequest_Status" json:"status,omitempty"`
+	// 0 if unknown
+	RequestedAt          int64    `protobuf:"varint,6,opt,name=requested_at,json=requestedAt,proto3" json:"requested_at,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WithdrawRequest) Reset()         { *m = WithdrawRequest{} }
+func (m *WithdrawRequest) String() string { return proto.CompactTextString(m) }
+func (*WithdrawRequest) ProtoMessage()    {}
+func (*WithdrawRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_marketdata_5ca7d973b81e45ea, []int{15}
+}
+func (m *WithdrawRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WithdrawRequest.Unmarshal(m, b)
+}
+func (m *WithdrawRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WithdrawRequest.Marshal(b, m, deterministic)
+}
+func (dst *WithdrawRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WithdrawRequest.Merge(dst, src)
+}
+func (m *WithdrawRequest) XXX_Size() int {
+	return xxx_messageInfo_WithdrawRequest.Size(m)
+}
+func (m *WithdrawRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_WithdrawRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WithdrawRequest proto.InternalMessageInfo
+
+func (m *WithdrawRequest) GetTxHash() string {
+	if m != nil {
+		return m.TxHash
+	}
+	return ""
+}
+
+func (m *WithdrawRequest) GetToken() string {
+	if m != nil {
+		return m.Token
+	}
+	return ""
+}
+
+func (m *WithdrawRequest) GetRecipient() string {
+	if m != nil {
+		return m.Recipient
+	}
+	return ""
+}
+
+func (m *WithdrawRequest) GetAmount() string {
+	if m != nil {
+		return m.Amount
+	}
+	return ""
+}
+
+func (m *WithdrawRequest) GetStatus() WithdrawRequest_Status {
+	if m != nil {
+		return m.Status
+	}
+	return WithdrawRequest_REQUESTED
+}
+
+func (m *WithdrawRequest) GetRequestedAt() int64 {
+	if m != nil {
+		return m.RequestedAt
+	}
+	return 0
+}
+
+type WithdrawRequests struct {
+	Requests             []*WithdrawRequest `protobuf:"bytes,1,rep,name=requests,proto3" json:"requests,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *WithdrawRequests) Reset()         { *m = WithdrawRequests{} }
+func (m *WithdrawRequests) String() string { return proto.CompactTextString(m) }
+func (*WithdrawRequests) ProtoMessage()    {}
+func (*WithdrawRequests) Descriptor() ([]byte, []int) {
+	return fileDescriptor_marketdata_5ca7d973b81e45ea, []int{16}
+}
+func (m *WithdrawRequests) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WithdrawRequests.Unmarshal(m, b)
+}
+func (m *WithdrawRequests) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WithdrawRequests.Marshal(b, m, deterministic)
+}
+func (dst *WithdrawRequests) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WithdrawRequests.Merge(dst, src)
+}
+func (m *WithdrawRequests) XXX_Size() int {
+	return xxx_messageInfo_WithdrawRequests.Size(m)
+}
+func (m *WithdrawRequests) XXX_DiscardUnknown() {
+	xxx_messageInfo_WithdrawRequests.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WithdrawRequests proto.InternalMessageInfo
+
+func (m *WithdrawRequests) GetRequests() []*WithdrawRequest {
+	if m != nil {
+		return m.Requests
+	}
+	return nil
+}
+
+type AccountStreamRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AccountStreamRequest) Reset()         { *m = AccountStreamRequest{} }
+func (m *AccountStreamRequest) String() string { return proto.CompactTextString(m) }
+func (*AccountStreamRequest) ProtoMessage()    {}
+func (*AccountStreamRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_marketdata_5ca7d973b81e45ea, []int{17}
+}
+func (m *AccountStreamRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AccountStreamRequest.Unmarshal(m, b)
+}
+func (m *AccountStreamRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AccountStreamRequest.Marshal(b, m, deterministic)
+}
+func (dst *AccountStreamRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AccountStreamRequest.Merge(dst, src)
+}
+func (m *AccountStreamRequest) XXX_Size() int {
+	return xxx_messageInfo_AccountStreamRequest.Size(m)
+}
+func (m *AccountStreamRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AccountStreamRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AccountStreamRequest proto.InternalMessageInfo
+
+type AccountEvent struct {
+	// Types that are valid to be assigned to Event:
+	//	*AccountEvent_Balance
+	//	*AccountEvent_WithdrawRequest
+	Event                isAccountEvent_Event `protobuf_oneof:"event"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *AccountEvent) Reset()         { *m = AccountEvent{} }
+func (m *AccountEvent) String() string { return proto.CompactTextString(m) }
+func (*AccountEvent) ProtoMessage()    {}
+func (*AccountEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_marketdata_5ca7d973b81e45ea, []int{18}
+}
+func (m *AccountEvent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AccountEvent.Unmarshal(m, b)
+}
+func (m *AccountEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AccountEvent.Marshal(b, m, deterministic)
+}
+func (dst *AccountEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AccountEvent.Merge(dst, src)
+}
+func (m *AccountEvent) XXX_Size() int {
+	return xxx_messageInfo_AccountEvent.Size(m)
+}
+func (m *AccountEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_AccountEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AccountEvent proto.InternalMessageInfo
+
+type isAccountEvent_Event interface {
+	isAccountEvent_Event()
+}
+
+type AccountEvent_Balance struct {
+	Balance *Balance `protobuf:"bytes,1,opt,name=balance,proto3,oneof"`
+}
+
+type AccountEvent_WithdrawRequest struct {
+	WithdrawRequest *WithdrawRequest `protobuf:"bytes,2,opt,name=withdraw_request,json=withdrawRequest,proto3,oneof"`
+}
+
+func (*AccountEvent_Balance) isAccountEvent_Event() {}
+
+func (*AccountEvent_WithdrawRequest) isAccountEvent_Event() {}
+
+func (m *AccountEvent) GetEvent() isAccountEvent_Event {
+	if m != nil {
+		return m.Event
+	}
+	return nil
+}
+
+func (m *AccountEvent) GetBalance() *Balance {
+	if x, ok := m.GetEvent().(*AccountEvent_Balance); ok {
+		return x.Balance
+	}
+	return nil
+}
+
+func (m *AccountEvent) GetWithdrawRequest() *WithdrawRequest {
+	if x, ok := m.GetEvent().(*AccountEvent_WithdrawRequest); ok {
+		return x.WithdrawRequest
+	}
+	return nil
+}
+
+// XXX_OneofFuncs is for the internal use of the proto package.
+func (*AccountEvent) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
+	return _AccountEvent_OneofMarshaler, _AccountEvent_OneofUnmarshaler, _AccountEvent_OneofSizer, []interface{}{
+		(*AccountEvent_Balance)(nil),
+		(*AccountEvent_WithdrawRequest)(nil),
+	}
+}
+
+func _AccountEvent_OneofMarshaler(msg proto.Message, b *proto.Buffer) error {
+	m := msg.(*AccountEvent)
+	// event
+	switch x := m.Event.(type) {
+	case *AccountEvent_Balance:
+		b.EncodeVarint(1<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.Balance); err != nil {
+			return err
+		}
+	case *AccountEvent_WithdrawRequest:
+		b.EncodeVarint(2<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.WithdrawRequest); err != nil {
+			return err
+		}
+	case nil:
+	default:
+		return fmt.Errorf("AccountEvent.Event has unexpected type %T", x)
+	}
+	return nil
+}
+
+func _AccountEvent_OneofUnmarshaler(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error) {
+	m := msg.(*AccountEvent)
+	switch tag {
+	case 1: // event.balance
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(Balance)
+		err := b.DecodeMessage(msg)
+		m.Event = &AccountEvent_Balance{msg}
+		return true, err
+	case 2: // event.withdraw_request
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(WithdrawRequest)
+		err := b.DecodeMessage(msg)
+		m.Event = &AccountEvent_WithdrawRequest{msg}
+		return true, err
+	default:
+		return false, nil
+	}
+}
+
+func _AccountEvent_OneofSizer(msg proto.Message) (n int) {
+	m := msg.(*AccountEvent)
+	// event
+	switch x := m.Event.(type) {
+	case *AccountEvent_Balance:
+		s := proto.Size(x.Balance)
+		n += 1 // tag and wire
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *AccountEvent_WithdrawRequest:
+		s := proto.Size(x.WithdrawRequest)
+		n += 1 // tag and wire
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case nil:
+	default:
+		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
+	}
+	return n
+}
+
+func init() {
+	proto.RegisterType((*Market)(nil), "marketdata.Market")
+	proto.RegisterType((*OrderbookRequest)(nil), "marketdata.OrderbookRequest")
+	proto.RegisterType((*OrderbookLevel)(nil), "marketdata.OrderbookLevel")
+	proto.RegisterType((*Orderbook)(nil), "marketdata.Orderbook")
+	proto.RegisterType((*TradesRequest)(nil), "marketdata.TradesRequest")
+	proto.RegisterType((*Trade)(nil), "marketdata.Trade")
+	proto.RegisterType((*TradesPage)(nil), "marketdata.TradesPage")
+	proto.RegisterType((*CandlesRequest)(nil), "marketdata.CandlesRequest")
+	proto.RegisterType((*Candle)(nil), "marketdata.Candle")
+	proto.RegisterType((*Candles)(nil), "marketdata.Candles")
+	proto.RegisterType((*BalancesRequest)(nil), "marketdata.BalancesRequest")
+	proto.RegisterType((*Balance)(nil), "marketdata.Balance")
+	proto.RegisterType((*Balances)(nil), "marketdata.Balances")
+	proto.RegisterType((*WithdrawRequestsRequest)(nil), "marketdata.WithdrawRequestsRequest")
+	proto.RegisterType((*WithdrawStatusRequest)(nil), "marketdata.WithdrawStatusRequest")
+	proto.RegisterType((*WithdrawRequest)(nil), "marketdata.WithdrawRequest")
+	proto.RegisterType((*WithdrawRequests)(nil), "marketdata.WithdrawRequests")
+	proto.RegisterType((*AccountStreamRequest)(nil), "marketdata.AccountStreamRequest")
+	proto.RegisterType((*AccountEvent)(nil), "marketdata.AccountEvent")
+	proto.RegisterEnum("marketdata.WithdrawRequest_Status", WithdrawRequest_Status_name, WithdrawRequest_Status_value)
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// MarketDataClient is the client API for MarketData service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type MarketDataClient interface {
+	// GetOrderbook returns aggregated price levels of a market
+	GetOrderbook(ctx context.Context, in *OrderbookRequest, opts ...grpc.CallOption) (*Orderbook, error)
+	// StreamOrderbook sends a snapshot of the orderbook, then a new one each
+	// time orders of the market are placed, cancelled or filled
+	StreamOrderbook(ctx context.Context, in *OrderbookRequest, opts ...grpc.CallOption) (MarketData_StreamOrderbookClient, error)
+	// GetTrades returns a page of trades of a market, newest first
+	GetTrades(ctx context.Context, in *TradesRequest, opts ...grpc.CallOption) (*TradesPage, error)
+	// StreamTrades sends trades of a market as they happen
+	StreamTrades(ctx context.Context, in *Market, opts ...grpc.CallOption) (MarketData_StreamTradesClient, error)
+	// GetCandles returns candles of a market over [from, to), oldest first
+	GetCandles(ctx context.Context, in *CandlesRequest, opts ...grpc.CallOption) (*Candles, error)
+}
+
+type marketDataClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewMarketDataClient(cc *grpc.ClientConn) MarketDataClient {
+	return &marketDataClient{cc}
+}
+
+func (c *marketDataClient) GetOrderbook(ctx context.Context, in *OrderbookRequest, opts ...grpc.CallOption) (*Orderbook, error) {
+	out := new(Orderbook)
+	err := c.cc.Invoke(ctx, "/marketdata.MarketData/GetOrderbook", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketDataClient) StreamOrderbook(ctx context.Context, in *OrderbookRequest, opts ...grpc.CallOption) (MarketData_StreamOrderbookClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_MarketData_serviceDesc.Streams[0], "/marketdata.MarketData/StreamOrderbook", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &marketDataStreamOrderbookClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type MarketData_StreamOrderbookClient interface {
+	Recv() (*Orderbook, error)
+	grpc.ClientStream
+}
+
+type marketDataStreamOrderbookClient struct {
+	grpc.ClientStream
+}
+
+func (x *marketDataStreamOrderbookClient) Recv() (*Orderbook, error) {
+	m := new(Orderbook)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *marketDataClient) GetTrades(ctx context.Context, in *TradesRequest, opts ...grpc.CallOption) (*TradesPage, error) {
+	out := new(TradesPage)
+	err := c.cc.Invoke(ctx, "/marketdata.MarketData/GetTrades", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketDataClient) StreamTrades(ctx context.Context, in *Market, opts ...grpc.CallOption) (MarketData_StreamTradesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_MarketData_serviceDesc.Streams[1], "/marketdata.MarketData/StreamTrades", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &marketDataStreamTradesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type MarketData_StreamTradesClient interface {
+	Recv() (*Trade, error)
+	grpc.ClientStream
+}
+
+type marketDataStreamTradesClient struct {
+	grpc.ClientStream
+}
+
+func (x *marketDataStreamTradesClient) Recv() (*Trade, error) {
+	m := new(Trade)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *marketDataClient) GetCandles(ctx context.Context, in *CandlesRequest, opts ...grpc.CallOption) (*Candles, error) {
+	out := new(Candles)
+	err := c.cc.Invoke(ctx, "/marketdata.MarketData/GetCandles", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// MarketDataServer is the server API for MarketData service.
+type MarketDataServer interface {
+	// GetOrderbook returns aggregated price levels of a market
+	GetOrderbook(context.Context, *OrderbookRequest) (*Orderbook, error)
+	// StreamOrderbook sends a snapshot of the orderbook, then a new one each
+	// time orders of the market are placed, cancelled or filled
+	StreamOrderbook(*OrderbookRequest, MarketData_StreamOrderbookServer) error
+	// GetTrades returns a page of trades of a market, newest first
+	GetTrades(context.Context, *TradesRequest) (*TradesPage, error)
+	// StreamTrades sends trades of a market as they happen
+	StreamTrades(*Market, MarketData_StreamTradesServer) error
+	// GetCandles returns candles of a market over [from, to), oldest first
+	GetCandles(context.Context, *CandlesRequest) (*Candles, error)
+}
+
+func RegisterMarketDataServer(s *grpc.Server, srv MarketDataServer) {
+	s.RegisterService(&_MarketData_serviceDesc, srv)
+}
+
+func _MarketData_GetOrderbook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OrderbookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketDataServer).GetOrderbook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/marketdata.MarketData/GetOrderbook",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketDataServer).GetOrderbook(ctx, req.(*OrderbookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketData_StreamOrderbook_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(OrderbookRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MarketDataServer).StreamOrderbook(m, &marketDataStreamOrderbookServer{stream})
+}
+
+type MarketData_StreamOrderbookServer interface {
+	Send(*Orderbook) error
+	grpc.ServerStream
+}
+
+type marketDataStreamOrderbookServer struct {
+	grpc.ServerStream
+}
+
+func (x *marketDataStreamOrderbookServer) Send(m *Orderbook) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _MarketData_GetTrades_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TradesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketDataServer).GetTrades(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/marketdata.MarketData/GetTrades",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketDataServer).GetTrades(ctx, req.(*TradesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketData_StreamTrades_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(Market)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MarketDataServer).StreamTrades(m, &marketDataStreamTradesServer{stream})
+}
+
+type MarketData_StreamTradesServer interface {
+	Send(*Trade) error
+	grpc.ServerStream
+}
+
+type marketDataStreamTradesServer struct {
+	grpc.ServerStream
+}
+
+func (x *marketDataStreamTradesServer) Send(m *Trade) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _MarketData_GetCandles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CandlesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketDataServer).GetCandles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/marketdata.MarketData/GetCandles",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketDataServer).GetCandles(ctx, req.(*CandlesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _MarketData_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "marketdata.MarketData",
+	HandlerType: (*MarketDataServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetOrderbook",
+			Handler:    _MarketData_GetOrderbook_Handler,
+		},
+		{
+			MethodName: "GetTrades",
+			Handler:    _MarketData_GetTrades_Handler,
+		},
+		{
+			MethodName: "GetCandles",
+			Handler:    _MarketData_GetCandles_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamOrderbook",
+			Handler:       _MarketData_StreamOrderbook_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamTrades",
+			Handler:       _MarketData_StreamTrades_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "api/marketdata/marketdata.proto",
+}
+
+// AccountClient is the client API for Account service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type AccountClient interface {
+	// GetBalances returns token balances of the wallet
+	GetBalances(ctx context.Context, in *BalancesRequest, opts ...grpc.CallOption) (*Balances, error)
+	// GetWithdrawRequests returns withdraw requests of the wallet not
+	// processed yet
+	GetWithdrawRequests(ctx context.Context, in *WithdrawRequestsRequest, opts ...grpc.CallOption) (*WithdrawRequests, error)
+	// GetWithdrawStatus returns a withdraw request of the wallet
+	GetWithdrawStatus(ctx context.Context, in *WithdrawStatusRequest, opts ...grpc.CallOption) (*WithdrawRequest, error)
+	// StreamAccount sends balance updates and withdraw requests of the wallet
+	// as they change
+	StreamAccount(ctx context.Context, in *AccountStreamRequest, opts ...grpc.CallOption) (Account_StreamAccountClient, error)
+}
+
+type accountClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewAccountClient(cc *grpc.ClientConn) AccountClient {
+	return &accountClient{cc}
+}
+
+func (c *accountClient) GetBalances(ctx context.Context, in *BalancesRequest, opts ...grpc.CallOption) (*Balances, error) {
+	out := new(Balances)
+	err := c.cc.Invoke(ctx, "/marketdata.Account/GetBalances", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountClient) GetWithdrawRequests(ctx context.Context, in *WithdrawRequestsRequest, opts ...grpc.CallOption) (*WithdrawRequests, error) {
+	out := new(WithdrawRequests)
+	err := c.cc.Invoke(ctx, "/marketdata.Account/GetWithdrawRequests", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountClient) GetWithdrawStatus(ctx context.Context, in *WithdrawStatusRequest, opts ...grpc.CallOption) (*WithdrawRequest, error) {
+	out := new(WithdrawRequest)
+	err := c.cc.Invoke(ctx, "/marketdata.Account/GetWithdrawStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountClient) StreamAccount(ctx context.Context, in *AccountStreamRequest, opts ...grpc.CallOption) (Account_StreamAccountClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Account_serviceDesc.Streams[0], "/marketdata.Account/StreamAccount", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &accountStreamAccountClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Account_StreamAccountClient interface {
+	Recv() (*AccountEvent, error)
+	grpc.ClientStream
+}
+
+type accountStreamAccountClient struct {
+	grpc.ClientStream
+}
+
+func (x *accountStreamAccountClient) Recv() (*AccountEvent, error) {
+	m := new(AccountEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// AccountServer is the server API for Account service.
+type AccountServer interface {
+	// GetBalances returns token balances of the wallet
+	GetBalances(context.Context, *BalancesRequest) (*Balances, error)
+	// GetWithdrawRequests returns withdraw requests of the wallet not
+	// processed yet
+	GetWithdrawRequests(context.Context, *WithdrawRequestsRequest) (*WithdrawRequests, error)
+	// GetWithdrawStatus returns a withdraw request of the wallet
+	GetWithdrawStatus(context.Context, *WithdrawStatusRequest) (*WithdrawRequest, error)
+	// StreamAccount sends balance updates and withdraw requests of the wallet
+	// as they change
+	StreamAccount(*AccountStreamRequest, Account_StreamAccountServer) error
+}
+
+func RegisterAccountServer(s *grpc.Server, srv AccountServer) {
+	s.RegisterService(&_Account_serviceDesc, srv)
+}
+
+func _Account_GetBalances_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BalancesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServer).GetBalances(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/marketdata.Account/GetBalances",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServer).GetBalances(ctx, req.(*BalancesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Account_GetWithdrawRequests_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WithdrawRequestsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServer).GetWithdrawRequests(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/marketdata.Account/GetWithdrawRequests",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServer).GetWithdrawRequests(ctx, req.(*WithdrawRequestsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Account_GetWithdrawStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WithdrawStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServer).GetWithdrawStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/marketdata.Account/GetWithdrawStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServer).GetWithdrawStatus(ctx, req.(*WithdrawStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Account_StreamAccount_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(AccountStreamRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AccountServer).StreamAccount(m, &accountStreamAccountServer{stream})
+}
+
+type Account_StreamAccountServer interface {
+	Send(*AccountEvent) error
+	grpc.ServerStream
+}
+
+type accountStreamAccountServer struct {
+	grpc.ServerStream
+}
+
+func (x *accountStreamAccountServer) Send(m *AccountEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _Account_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "marketdata.Account",
+	HandlerType: (*AccountServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetBalances",
+			Handler:    _Account_GetBalances_Handler,
+		},
+		{
+			MethodName: "GetWithdrawRequests",
+			Handler:    _Account_GetWithdrawRequests_Handler,
+		},
+		{
+			MethodName: "GetWithdrawStatus",
+			Handler:    _Account_GetWithdrawStatus_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamAccount",
+			Handler:       _Account_StreamAccount_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "api/marketdata/marketdata.proto",
+}
+
+func init() {
+	proto.RegisterFile("api/marketdata/marketdata.proto", fileDescriptor_marketdata_5ca7d973b81e45ea)
+}
+
+var fileDescriptor_marketdata_5ca7d973b81e45ea = []byte{
+	// 1048 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x56, 0x5b, 0x6f, 0xe3, 0x44,
+	0x14, 0xae, 0x9d, 0xd6, 0x69, 0x4e, 0xae, 0x9d, 0x76, 0xbb, 0xde, 0xb4, 0xb0, 0x5d, 0x83, 0x50,
+	0xb9, 0xa8, 0xad, 0xc2, 0x43, 0x25, 0x24, 0x10, 0xbd, 0x44, 0x09, 0x82, 0x65, 0x5b, 0xa7, 0xc0,
+	0x8a, 0x97, 0x68, 0xe2, 0x0c, 0x1b, 0xab, 0x4e, 0x26, 0x6b, 0x8f, 0x9b, 0xf6, 0x8d, 0x07, 0x5e,
+	0x78, 0xe5, 0xc7, 0xf0, 0x1f, 0x78, 0x43, 0xe2, 0x37, 0xf0, 0x3f, 0xd0, 0xdc, 0x6c, 0xa7, 0x71,
+	0x83, 0x76, 0xf7, 0x6d, 0xce, 0x39, 0xdf, 0x9c, 0xcb, 0x77, 0xce, 0x1c, 0x1b, 0x9e, 0xe2, 0xa9,
+	0x7f, 0x38, 0xc6, 0xe1, 0x35, 0x61, 0x43, 0xcc, 0x70, 0xe6, 0x78, 0x30, 0x0d, 0x29, 0xa3, 0x08,
+	0x52, 0x8d, 0xd3, 0x02, 0xeb, 0xb9, 0x90, 0xd0, 0x16, 0xac, 0x31, 0x7a, 0x4d, 0x26, 0xb6, 0xb1,
+	0x67, 0xec, 0x97, 0x5c, 0x29, 0x20, 0x04, 0xab, 0x03, 0x1c, 0x11, 0xdb, 0x14, 0x4a, 0x71, 0x76,
+	0xae, 0xa0, 0xf1, 0x22, 0x1c, 0x92, 0x70, 0x40, 0xe9, 0xb5, 0x4b, 0x5e, 0xc7, 0x24, 0x62, 0xe8,
+	0x13, 0xb0, 0xa4, 0x57, 0x71, 0xbd, 0xdc, 0x42, 0x07, 0x99, 0xb0, 0x32, 0x82, 0x6b, 0x8d, 0x93,
+	0x48, 0x43, 0x32, 0x65, 0x23, 0xe1, 0xb4, 0xea, 0x4a, 0xc1, 0xf9, 0xdb, 0x80, 0x5a, 0xe2, 0xf6,
+	0x3b, 0x72, 0x43, 0x02, 0x0e, 0x9c, 0x86, 0xbe, 0x47, 0x74, 0x4a, 0x42, 0x40, 0x4d, 0x58, 0x7f,
+	0x1d, 0xe3, 0x09, 0xf3, 0xd9, 0x9d, 0x4a, 0x2b, 0x91, 0xd1, 0x36, 0x58, 0x37, 0x34, 0x88, 0xc7,
+	0xc4, 0x2e, 0x08, 0x8b, 0x92, 0xd0, 0x21, 0x6c, 0x7a, 0xf1, 0x38, 0x0e, 0x30, 0xf3, 0x6f, 0x48,
+	0x3f, 0xb9, 0xbe, 0x2a, 0x40, 0x28, 0x35, 0x5d, 0x6a, 0x47, 0x9f, 0xc2, 0x46, 0xe6, 0x82, 0xf2,
+	0xb9, 0x26, 0xe0, 0x8d, 0xd4, 0xf0, 0xa3, 0xf4, 0xbe, 0x0d, 0x16, 0xe5, 0x99, 0x47, 0xb6, 0xb5,
+	0x67, 0xec, 0x17, 0x5c, 0x25, 0x39, 0x7f, 0x1a, 0x50, 0x4a, 0x4a, 0x7a, 0x23, 0x8a, 0x0e, 0x60,
+	0x75, 0xe0, 0x0f, 0x23, 0xdb, 0xdc, 0x2b, 0xec, 0x97, 0x5b, 0xcd, 0x2c, 0x72, 0x9e, 0x23, 0x57,
+	0xe0, 0x38, 0x1e, 0x47, 0xd7, 0x91, 0x5d, 0xf8, 0x7f, 0x3c, 0xc7, 0xa1, 0xf7, 0x00, 0x02, 0x1c,
+	0xb1, 0xbe, 0xa4, 0x57, 0xd2, 0x50, 0xe2, 0x9a, 0x0b, 0xae, 0x70, 0x7c, 0xa8, 0x5e, 0x85, 0x78,
+	0x48, 0xa2, 0xb7, 0x6c, 0xaf, 0x47, 0xe3, 0x09, 0xd3, 0xed, 0x15, 0x02, 0xe7, 0xc8, 0x8b, 0xc3,
+	0x88, 0x86, 0xba, 0x33, 0x52, 0x72, 0xfe, 0x35, 0x60, 0x4d, 0xc4, 0x7a, 0xd3, 0x18, 0x32, 0x75,
+	0x33, 0x3b, 0x19, 0x0f, 0x75, 0x7f, 0x07, 0x4a, 0x8c, 0x87, 0x18, 0xf6, 0x31, 0x13, 0xc5, 0x16,
+	0xdc, 0x75, 0xa9, 0x38, 0x61, 0xe8, 0x43, 0xa8, 0x0d, 0xe2, 0xbb, 0xbe, 0x68, 0x59, 0x7f, 0x84,
+	0xa3, 0x91, 0x6a, 0x73, 0x65, 0x10, 0xdf, 0x09, 0xee, 0xba, 0x38, 0x1a, 0xa1, 0x8f, 0xa0, 0x1e,
+	0x91, 0x20, 0xc8, 0xc2, 0x2c, 0x01, 0xab, 0x72, 0x75, 0x8a, 0x7b, 0x0c, 0x45, 0x76, 0x2b, 0xed,
+	0x45, 0x99, 0x03, 0xbb, 0xe5, 0x06, 0xe7, 0x25, 0x80, 0xa4, 0xf4, 0x02, 0xbf, 0x22, 0xe8, 0x63,
+	0xb0, 0x44, 0x02, 0x91, 0x6d, 0x88, 0x8e, 0x6d, 0x64, 0x6b, 0x15, 0x38, 0x57, 0x01, 0xd0, 0x53,
+	0x28, 0x4f, 0xc8, 0x2d, 0xeb, 0x2b, 0xf6, 0x64, 0xc1, 0xc0, 0x55, 0x67, 0x92, 0xc1, 0x5f, 0x0d,
+	0xa8, 0x9d, 0xe1, 0xc9, 0x30, 0x78, 0xbb, 0x76, 0xbd, 0x0f, 0x10, 0x92, 0x88, 0x06, 0x31, 0xf3,
+	0xe9, 0x44, 0xbb, 0x4f, 0x35, 0x7c, 0x03, 0xfc, 0x12, 0xd2, 0xb1, 0xa0, 0xb4, 0xe0, 0x8a, 0x33,
+	0xaa, 0x81, 0xc9, 0xa8, 0x62, 0xd2, 0x64, 0xd4, 0xf9, 0xcd, 0x00, 0x4b, 0xa6, 0xc0, 0xe1, 0xcc,
+	0x1f, 0xcb, 0x27, 0x5b, 0x70, 0xc5, 0x99, 0xeb, 0xe8, 0x94, 0x68, 0xe7, 0xe2, 0xcc, 0x75, 0x23,
+	0xff, 0xd5, 0x48, 0x75, 0x4a, 0x9c, 0x51, 0x03, 0x0a, 0x01, 0x9d, 0xa9, 0x71, 0xe4, 0x47, 0x31,
+	0x4b, 0x01, 0x8d, 0xf4, 0xd3, 0x93, 0x42, 0xa6, 0xcf, 0x56, 0xb6, 0xcf, 0xce, 0x31, 0x14, 0x15,
+	0x11, 0xe8, 0x33, 0x28, 0x7a, 0xf2, 0xa8, 0x18, 0x9e, 0xa3, 0x40, 0xa2, 0x5c, 0x0d, 0x71, 0x36,
+	0xa0, 0x7e, 0x8a, 0x03, 0x3c, 0xf1, 0x12, 0x0a, 0x9d, 0x4b, 0x28, 0x2a, 0xd5, 0x03, 0x9b, 0xd1,
+	0x86, 0xe2, 0x40, 0x02, 0x54, 0x5d, 0x5a, 0xe4, 0xe9, 0x91, 0xc8, 0x0b, 0xe9, 0x4c, 0x8f, 0xa1,
+	0x94, 0x9c, 0x1e, 0xac, 0xeb, 0x28, 0x1c, 0x33, 0xc3, 0x41, 0xa0, 0x3a, 0x54, 0x72, 0x95, 0x84,
+	0x0e, 0x61, 0x5d, 0xb9, 0xd1, 0x8f, 0x7f, 0x33, 0x9b, 0xb8, 0xba, 0xef, 0x26, 0x20, 0xe7, 0x09,
+	0x3c, 0xfe, 0xc9, 0x67, 0xa3, 0x61, 0x88, 0x67, 0x2a, 0xf5, 0xa4, 0x84, 0x23, 0x78, 0xa4, 0x4d,
+	0x3d, 0x86, 0x59, 0xac, 0x0d, 0xd9, 0x21, 0x35, 0xe6, 0x86, 0xf4, 0x77, 0x13, 0xea, 0xf7, 0xbc,
+	0x3d, 0x08, 0x4e, 0x69, 0x31, 0xb3, 0xb4, 0xec, 0x42, 0x29, 0x24, 0x9e, 0x3f, 0xf5, 0xc9, 0x84,
+	0xa9, 0xfa, 0x53, 0x05, 0x2f, 0x1b, 0x8f, 0xc5, 0x72, 0x90, 0x4d, 0x56, 0x12, 0xfa, 0x02, 0xac,
+	0x48, 0xa4, 0x28, 0x1a, 0x5d, 0x6b, 0x39, 0xd9, 0xa2, 0xef, 0x65, 0x74, 0xa0, 0x8a, 0x51, 0x37,
+	0xd0, 0x33, 0xa8, 0x84, 0xd2, 0x22, 0x1f, 0xb8, 0xdc, 0xc1, 0xe5, 0x44, 0x77, 0xc2, 0xf8, 0x57,
+	0x4e, 0x5e, 0x42, 0x55, 0x28, 0xb9, 0xed, 0xcb, 0x1f, 0xda, 0xbd, 0xab, 0xf6, 0x79, 0x63, 0x05,
+	0x01, 0x58, 0xbd, 0x6f, 0x3a, 0xdf, 0xb7, 0xcf, 0x1b, 0x06, 0x37, 0x5d, 0xb8, 0x2f, 0xce, 0xda,
+	0xbd, 0x5e, 0xfb, 0xbc, 0x61, 0x3a, 0xdf, 0x42, 0xe3, 0x3e, 0xb1, 0xe8, 0x18, 0xd6, 0x95, 0x5b,
+	0x3d, 0x56, 0x3b, 0x4b, 0x12, 0x75, 0x13, 0xb0, 0xb3, 0x0d, 0x5b, 0x27, 0x9e, 0x58, 0x84, 0x3d,
+	0x16, 0x12, 0x3c, 0xd6, 0x2d, 0xfa, 0xc3, 0x80, 0x8a, 0x32, 0xb4, 0x6f, 0x38, 0x41, 0x87, 0xe9,
+	0x54, 0xc9, 0xa7, 0x9b, 0xd7, 0xfe, 0xee, 0x4a, 0x3a, 0x6c, 0x5d, 0x68, 0xcc, 0x54, 0xd8, 0xbe,
+	0x0a, 0x27, 0x1a, 0xb2, 0x3c, 0xb5, 0xee, 0x8a, 0x5b, 0x9f, 0xcd, 0xab, 0x4e, 0x8b, 0xb0, 0x46,
+	0x78, 0x0e, 0xad, 0x7f, 0x4c, 0x00, 0xb9, 0x24, 0xce, 0x31, 0xc3, 0xe8, 0x0c, 0x2a, 0x1d, 0xc2,
+	0xd2, 0xef, 0xd8, 0x6e, 0xee, 0xd7, 0x45, 0x79, 0x69, 0x3e, 0xca, 0xb5, 0xa2, 0x2e, 0xd4, 0x65,
+	0xe5, 0xef, 0xe6, 0xe7, 0xc8, 0x40, 0x5f, 0x41, 0xa9, 0x43, 0x98, 0xdc, 0xa5, 0xe8, 0xc9, 0xc2,
+	0xde, 0xd4, 0x43, 0xde, 0xdc, 0x5e, 0x34, 0x89, 0xd5, 0x7b, 0x0c, 0x15, 0x99, 0x89, 0x72, 0x91,
+	0xb3, 0x1b, 0x9b, 0x8b, 0xeb, 0xf8, 0xc8, 0x40, 0x5f, 0x02, 0x74, 0x08, 0xd3, 0x0b, 0xa6, 0xb9,
+	0xb8, 0x4f, 0x92, 0xd0, 0x9b, 0x39, 0xb6, 0xd6, 0x5f, 0x26, 0x14, 0x55, 0xab, 0xd1, 0xd7, 0x50,
+	0xee, 0x10, 0x96, 0x2c, 0x83, 0x9d, 0x9c, 0x1e, 0x27, 0xce, 0xb6, 0xf2, 0x8c, 0xe8, 0x25, 0x6c,
+	0x76, 0x08, 0x5b, 0x18, 0xd0, 0x0f, 0x96, 0xf4, 0x3c, 0xf1, 0xb8, 0xbb, 0x0c, 0x84, 0x7a, 0xb0,
+	0x91, 0xf1, 0xac, 0x9e, 0xcd, 0xb3, 0xbc, 0x2b, 0x73, 0x4b, 0xa5, 0xb9, 0x6c, 0xdc, 0xd0, 0x73,
+	0xa8, 0x4a, 0xd2, 0x35, 0x03, 0x7b, 0x59, 0x74, 0xde, 0xd3, 0x68, 0xda, 0x39, 0x08, 0xf1, 0x46,
+	0x8e, 0x8c, 0xd3, 0xca, 0xcf, 0x99, 0x7f, 0xd8, 0x81, 0x25, 0x7e, 0x6b, 0x3f, 0xff, 0x6f, 0x00,
+	0x39, 0x03, 0x9d, 0x21, 0xf9, 0x0a, 0x00, 0x00,
+}
@@ -2,37 +2,96 @@ package main
 
 import (
 	"fmt"
+	"log"
 	"os"
-	"os/signal"
 	"strconv"
+	"time"
 
 	"hameid.net/cdex/dex/internal/app"
+	"hameid.net/cdex/dex/internal/config"
+	"hameid.net/cdex/dex/internal/lifecycle"
+	"hameid.net/cdex/dex/internal/models"
+	"hameid.net/cdex/dex/internal/store"
 )
 
 func main() {
-	port, err := strconv.ParseUint(os.Getenv("DEX_APP_LAYER_PORT"), 10, 64)
+	cfg, args, err := config.Load(config.ServiceApp, os.Args[1:])
 	if err != nil {
-		fmt.Println("Cannot find `DEX_APP_LAYER_PORT` env variable. Starting on port 6454...")
-		port = 6454
-	}
-	app := app.NewApp(
-		uint(port),
-		os.Getenv("CDEX_DB_CONNECTION_STRING"),
-	)
-
-	c := make(chan os.Signal, 1)
-	signal.Notify(c, os.Interrupt)
-	go func() {
-		for sig := range c {
-			fmt.Printf("\nReceived signal %s", sig)
-			app.Shutdown()
-			os.Exit(0)
+		log.Fatal(err)
+	}
+
+	if len(args) > 0 {
+		runCommand(cfg, args)
+		return
+	}
+
+	cfg.Print(os.Stdout)
+
+	app := app.NewApp(cfg)
+
+	reloader := config.NewReloader(config.ServiceApp, os.Args[1:], cfg)
+	reloader.OnReload(app.Reload)
+	reloader.Watch()
+
+	if err := app.Run(lifecycle.SignalContext()); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("\nBye bye...\n")
+}
+
+// runCommand runs one-off management commands
+func runCommand(cfg *config.Config, args []string) {
+	switch args[0] {
+	case "refresh-candles":
+		refreshCandles(cfg, args[1:])
+
+	default:
+		log.Fatalf("Unknown command `%s`", args[0])
+	}
+}
+
+// refreshCandles materializes candles of a time range, e.g. after trades
+// were replayed or to backfill history after creating the aggregates
+func refreshCandles(cfg *config.Config, args []string) {
+	if len(args) < 1 {
+		log.Fatal("Usage: app refresh-candles <from> [to] [resolution]\n" +
+			"  from and to are RFC 3339 times or Unix seconds, to defaults to now,\n" +
+			"  all resolutions are refreshed if resolution is not given")
+	}
+
+	from, err := parseTime(args[0])
+	if err != nil {
+		log.Fatalf("Invalid from: %s", err)
+	}
+
+	to := time.Now()
+	if len(args) > 1 {
+		if to, err = parseTime(args[1]); err != nil {
+			log.Fatalf("Invalid to: %s", err)
 		}
-	}()
+	}
 
-	done := make(chan bool)
+	var resolution time.Duration
+	if len(args) > 2 {
+		var ok bool
+		if resolution, ok = models.OHLCResolutions[args[2]]; !ok {
+			log.Fatalf("Unknown resolution `%s`", args[2])
+		}
+	}
+
+	dataStore := store.NewDataStore(cfg.Database.ConnectionString)
+	dataStore.Initialize()
+	defer dataStore.Close()
+
+	if err := models.RefreshCandles(dataStore, resolution, from, to); err != nil {
+		log.Fatal(err)
+	}
+}
 
-	app.Start()
+func parseTime(value string) (time.Time, error) {
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(seconds, 0), nil
+	}
 
-	<-done
+	return time.Parse(time.RFC3339, value)
 }
@@ -2,39 +2,64 @@ package main
 
 import (
 	"fmt"
+	"log"
 	"os"
-	"os/signal"
+	"time"
 
+	"hameid.net/cdex/dex/internal/config"
+	"hameid.net/cdex/dex/internal/lifecycle"
 	"hameid.net/cdex/dex/internal/relayer"
+	"hameid.net/cdex/dex/internal/signer"
 )
 
 func main() {
-	app := relayer.NewRelayer(
-		os.Getenv("DEX_VALIDATOR_CONTRACTS_FILE"),
-		os.Getenv("DEX_VALIDATOR_NETWORKS_FILE"),
-		os.Getenv("CDEX_DB_CONNECTION_STRING"),
-		os.Getenv("CDEX_REDIS_HOST"),
-		os.Getenv("CDEX_REDIS_PASSWORD"),
-		os.Getenv("DEX_ORDER_MATCHER_KEYSTORE_FILE"),
-		os.Getenv("DEX_ORDER_MATCHER_PASSWORD_FILE"),
+	cfg, _, err := config.Load(config.ServiceRelayer, os.Args[1:])
+	if err != nil {
+		log.Fatal(err)
+	}
+	cfg.Print(os.Stdout)
+
+	fmt.Printf("Loading order matcher signer...\n")
+	matcher, err := signer.NewSigner(
+		cfg.Relayer.Matcher.KeystoreFile,
+		cfg.Relayer.Matcher.PasswordFile,
+		cfg.Relayer.Matcher.SignerURL,
+		cfg.Relayer.Matcher.SignerAddress,
 	)
+	if err != nil {
+		log.Panic(err)
+	}
+
+	app := relayer.NewRelayer(cfg, matcher)
 
-	c := make(chan os.Signal, 1)
-	signal.Notify(c, os.Interrupt)
-	go func() {
-		for sig := range c {
-			fmt.Printf("\nReceived signal %s", sig)
-			app.Quit()
-			os.Exit(0)
+	if cfg.Relayer.WithdrawRelay.IsSet() {
+		fmt.Printf("Loading withdraw relay signer...\n")
+		operator, err := signer.NewSigner(
+			cfg.Relayer.WithdrawRelay.KeystoreFile,
+			cfg.Relayer.WithdrawRelay.PasswordFile,
+			cfg.Relayer.WithdrawRelay.SignerURL,
+			cfg.Relayer.WithdrawRelay.SignerAddress,
+		)
+		if err != nil {
+			log.Panic(err)
 		}
-	}()
+		app.EnableWithdrawRelay(operator)
+	}
 
-	app.Initialize()
+	// Nothing in relayer can be reloaded, but changes are still checked and
+	// SIGHUP does not terminate the process
+	config.NewReloader(config.ServiceRelayer, os.Args[1:], cfg).Watch()
 
-	done := make(chan bool)
+	ctx := lifecycle.SignalContext()
+
+	app.Initialize()
+	app.ServeHealth(cfg.Relayer.HealthPort, time.Duration(cfg.Health.MaxBlockAge)*time.Second)
 
-	app.RunOnBridgeNetwork()
-	app.RunOnExchangeNetwork()
+	app.RunOnBridgeNetwork(ctx)
+	app.RunOnExchangeNetwork(ctx)
+	app.RunWithdrawRelay(ctx)
+	app.RunTickerPublisher(ctx)
 
-	<-done
+	<-ctx.Done()
+	app.Quit()
 }
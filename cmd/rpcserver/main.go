@@ -0,0 +1,30 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"hameid.net/cdex/dex/internal/config"
+	"hameid.net/cdex/dex/internal/lifecycle"
+	"hameid.net/cdex/dex/internal/rpcserver"
+)
+
+func main() {
+	cfg, _, err := config.Load(config.ServiceRPCServer, os.Args[1:])
+	if err != nil {
+		log.Fatal(err)
+	}
+	cfg.Print(os.Stdout)
+
+	rpcServer := rpcserver.NewRPCServer(cfg)
+
+	reloader := config.NewReloader(config.ServiceRPCServer, os.Args[1:], cfg)
+	reloader.OnReload(rpcServer.Reload)
+	reloader.Watch()
+
+	if err := rpcServer.Run(lifecycle.SignalContext()); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("\nBye bye...\n")
+}
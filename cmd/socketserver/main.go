@@ -2,42 +2,31 @@ package main
 
 import (
 	"fmt"
+	"log"
 	"os"
-	"os/signal"
-	"strconv"
 
+	"hameid.net/cdex/dex/internal/config"
+	"hameid.net/cdex/dex/internal/lifecycle"
 	"hameid.net/cdex/dex/internal/socketserver"
 )
 
 func main() {
-	port, err := strconv.ParseUint(os.Getenv("DEX_WS_LAYER_PORT"), 10, 64)
+	cfg, _, err := config.Load(config.ServiceSocketServer, os.Args[1:])
 	if err != nil {
-		fmt.Println("Cannot find `DEX_WS_LAYER_PORT` env variable.\n\nStarting on port 7424...")
-		port = 7424
+		log.Fatal(err)
 	}
+	cfg.Print(os.Stdout)
 
-	socketServer := socketserver.NewSocketServer(
-		port,
-		os.Getenv("CDEX_WEBAPP_HOST"),
-		os.Getenv("CDEX_REDIS_HOST"),
-		os.Getenv("CDEX_REDIS_PASSWORD"),
-	)
+	socketServer := socketserver.NewSocketServer(cfg)
 
-	// socketServer.Initialize()
-
-	c := make(chan os.Signal, 1)
-	signal.Notify(c, os.Interrupt)
-	go func() {
-		for sig := range c {
-			fmt.Printf("\nReceived signal %s\n", sig)
-			socketServer.Shutdown()
-			os.Exit(0)
-		}
-	}()
-
-	done := make(chan bool)
+	reloader := config.NewReloader(config.ServiceSocketServer, os.Args[1:], cfg)
+	reloader.OnReload(socketServer.Reload)
+	reloader.Watch()
 
-	socketServer.Start()
+	// socketServer.Initialize()
 
-	<-done
+	if err := socketServer.Run(lifecycle.SignalContext()); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("\nBye bye...\n")
 }
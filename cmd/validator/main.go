@@ -2,36 +2,147 @@ package main
 
 import (
 	"fmt"
+	"log"
 	"os"
-	"os/signal"
+	"time"
 
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"hameid.net/cdex/dex/internal/config"
+	"hameid.net/cdex/dex/internal/lifecycle"
+	"hameid.net/cdex/dex/internal/signer"
+	"hameid.net/cdex/dex/internal/utils"
 	"hameid.net/cdex/dex/internal/validator"
 )
 
 func main() {
-	app := validator.NewValidator(
-		os.Getenv("DEX_VALIDATOR_CONTRACTS_FILE"),
-		os.Getenv("DEX_VALIDATOR_NETWORKS_FILE"),
-		os.Getenv("DEX_VALIDATOR_KEYSTORE_FILE"),
-		os.Getenv("DEX_VALIDATOR_PASSWORD_FILE"),
+	cfg, args, err := config.Read(config.ServiceValidator, os.Args[1:])
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Verification works offline and needs no key
+	if len(args) > 0 && args[0] == "verify" {
+		os.Exit(verifySignature(cfg, args[1:]))
+	}
+
+	if err := cfg.Validate(config.ServiceValidator); err != nil {
+		log.Fatal(err)
+	}
+	cfg.Print(os.Stdout)
+
+	fmt.Printf("Loading validator signer...\n")
+	accountSigner, err := signer.NewSigner(
+		cfg.Validator.Key.KeystoreFile,
+		cfg.Validator.Key.PasswordFile,
+		cfg.Validator.Key.SignerURL,
+		cfg.Validator.Key.SignerAddress,
 	)
+	if err != nil {
+		log.Panic(err)
+	}
 
-	c := make(chan os.Signal, 1)
-	signal.Notify(c, os.Interrupt)
-	go func() {
-		for sig := range c {
-			fmt.Printf("\nReceived signal %s", sig)
-			app.Quit()
-			os.Exit(0)
-		}
-	}()
+	app := validator.NewValidator(cfg, accountSigner)
+	app.UseWithdrawalMessageFormat(uint8(cfg.Validator.MessageVersion), cfg.Validator.TypedSignatures)
+
+	if len(args) > 0 {
+		runCommand(app, args)
+		return
+	}
+
+	// Transfer limits are read again on SIGHUP or when the config changes
+	reloader := config.NewReloader(config.ServiceValidator, os.Args[1:], cfg)
+	reloader.OnReload(app.Reload)
+	reloader.Watch()
+
+	ctx := lifecycle.SignalContext()
 
 	app.Initialize()
+	app.ServeHealth(cfg.Validator.HealthPort, time.Duration(cfg.Health.MaxBlockAge)*time.Second)
+
+	app.RunOnBridgeNetwork(ctx)
+	app.RunOnExchangeNetwork(ctx)
+
+	<-ctx.Done()
+	app.Quit()
+}
+
+// runCommand runs one-off management commands
+func runCommand(app *validator.Validator, args []string) {
+	switch args[0] {
+	case "parked":
+		transfers, err := app.ParkedTransfers()
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, t := range transfers {
+			fmt.Printf("%s\t%s\t%s %s -> %s\t%s\n", t.TxHash.Hex(), t.Kind, t.Amount.String(), t.Token.Hex(), t.Recipient.Hex(), t.Reason)
+		}
 
-	done := make(chan bool)
+	case "approve":
+		if len(args) < 2 {
+			log.Fatal("Usage: validator approve <tx_hash>")
+		}
+		app.Initialize()
+		if err := app.ApproveParkedTransfer(common.HexToHash(args[1])); err != nil {
+			log.Fatal(err)
+		}
+
+	default:
+		log.Fatalf("Unknown command `%s`", args[0])
+	}
+}
+
+// verifySignature checks a message signature and whether its signer is an
+// authority. Returns the process exit code.
+func verifySignature(cfg *config.Config, args []string) int {
+	if len(args) < 2 {
+		fmt.Println("Usage: validator verify <message_hex> <signature_hex> [signer]")
+		return 2
+	}
+
+	message, err := hexutil.Decode(args[0])
+	if err != nil {
+		fmt.Println("Invalid message:", err)
+		return 2
+	}
+
+	signature, err := hexutil.Decode(args[1])
+	if err != nil {
+		fmt.Println("Invalid signature:", err)
+		return 2
+	}
+
+	recovered, err := utils.RecoverSigner(message, signature)
+	if err != nil {
+		fmt.Println("Cannot recover signer:", err)
+		return 1
+	}
+	fmt.Printf("Recovered signer: %s\n", recovered.Hex())
+
+	signer := recovered
+	if len(args) > 2 {
+		if !common.IsHexAddress(args[2]) {
+			fmt.Println("Invalid signer address")
+			return 2
+		}
+		signer = common.HexToAddress(args[2])
+	}
+
+	err = utils.VerifyWithdrawalSignature(message, signature, signer)
+	if err == nil {
+		if cfg.Networks == nil {
+			fmt.Println("Networks file not set, authorities not checked")
+		} else {
+			err = utils.CheckAuthority(signer, cfg.Networks.Authorities)
+		}
+	}
 
-	app.RunOnBridgeNetwork()
-	app.RunOnExchangeNetwork()
+	if err != nil {
+		fmt.Println("INVALID:", err)
+		return 1
+	}
 
-	<-done
+	fmt.Println("VALID")
+	return 0
 }
@@ -4,7 +4,7 @@ import (
 	"os"
 	"testing"
 
-	"hameid.net/cdex/dex/internal/validator"
+	"hameid.net/cdex/dex/internal/utils"
 )
 
 func TestMain(m *testing.M) {
@@ -14,16 +14,22 @@ func TestMain(m *testing.M) {
 }
 
 func TestConfigReader(t *testing.T) {
-	contractsInfo, err := validator.ReadContractsInfo("/home/shah/Projects/dex/src/hameid.net/cdex/dex/configs/contracts.g.json")
+	nwInfo, err := utils.ReadNetworksInfo("../../configs/network.json")
 	if err != nil {
 		t.Error(err)
 	}
 
-	nwInfo, err := validator.ReadNetworksInfo("/home/shah/Projects/dex/src/hameid.net/cdex/dex/configs/network.json")
+	t.Log(nwInfo)
+
+	// Generated by contract deployment
+	if _, err := os.Stat("../../configs/contracts.g.json"); os.IsNotExist(err) {
+		t.Skip("contracts.g.json not generated")
+	}
+
+	contractsInfo, err := utils.ReadContractsInfo("../../configs/contracts.g.json")
 	if err != nil {
 		t.Error(err)
 	}
 
 	t.Log(contractsInfo)
-	t.Log(nwInfo)
 }
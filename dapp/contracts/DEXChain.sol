@@ -147,10 +147,15 @@ contract DEXChain is DEXContract {
     }
 
     function submitSignature(bytes signature, bytes message) public onlyAuthority {
-        // ensure that `signature` is really `message` signed by `msg.sender`
-        require(msg.sender == MessageSigning.recoverAddressFromSignedMessage(signature, message), "ERR_INVALID_SIGN");
+        require(Message.isValid(message), "ERR_INVALID_MESSAGE");
 
-        require(message.length == 104, "ERR_INVALID_MESSAGE");
+        // ensure that `signature` is really `message` signed by `msg.sender`,
+        // versioned messages may be signed as EIP-712 typed data instead
+        address signer = MessageSigning.recoverAddressFromSignedMessage(signature, message);
+        if (signer != msg.sender && Message.getVersion(message) != 0) {
+            signer = MessageSigning.recoverAddressFromSignedTypedWithdrawal(signature, message);
+        }
+        require(msg.sender == signer, "ERR_INVALID_SIGN");
         bytes32 messageHash = MessageSigning.hashMessage(message);
 
         // each authority can only provide one signature per message
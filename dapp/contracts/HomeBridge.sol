@@ -20,14 +20,25 @@ contract HomeBridge is Ownable {
     bool public isWithdrawDisabled;
     bool public isDepositDisabled;
 
+    // Chain versioned withdraw messages must be bound to, along with the
+    // address of this contract
+    uint256 public chainId;
+
+    // Legacy messages are not bound to a bridge, so their signatures can be
+    // replayed on any bridge of the same authorities
+    bool public isLegacyMessageDisabled;
+
     event Deposit (address recipient, address token, uint256 value);
     event Withdraw (address recipient, address token, uint256 value, bytes32 transactionHash);
 
     constructor (
         uint256 requiredSignaturesParam,
-        address[] authoritiesParam
+        address[] authoritiesParam,
+        uint256 chainIdParam
     ) public
     {
+        require(chainIdParam != 0, "ERR_ZERO_CHAIN_ID");
+        chainId = chainIdParam;
         updateAuthorities(requiredSignaturesParam, authoritiesParam);
         // require(requiredSignaturesParam != 0);
         // require(requiredSignaturesParam <= authoritiesParam.length);
@@ -45,6 +56,10 @@ contract HomeBridge is Ownable {
         isWithdrawDisabled = !enable;
     }
 
+    function toggleLegacyMessages(bool enable) public onlyOwner {
+        isLegacyMessageDisabled = !enable;
+    }
+
     function updateAuthorities(
         uint256 requiredSignaturesParam,
         address[] authoritiesParam
@@ -71,16 +86,17 @@ contract HomeBridge is Ownable {
     function withdraw(uint8[] vs, bytes32[] rs, bytes32[] ss, bytes message) public {
         require(!isWithdrawDisabled, "ERR_WITHDRAW_DISABLED");
         
-        require(message.length == 104, "MESSAGE_LENGTH_MISMATCH");
-
-        require(Helpers.hasEnoughValidSignatures(message, vs, rs, ss, authorities, requiredSignatures), "ERR_INSUFFICIENT_SIGNS");
+        requireValidMessage(vs, rs, ss, message);
 
         address recipient = Message.getRecipient(message);
         address token = Message.getToken(message);
         uint256 value = Message.getValue(message);
         bytes32 txHash = Message.getTransactionHash(message);
 
-        require(recipient == msg.sender, "ERR_NOT_BENEFICIARY");
+        // Anyone holding the signatures may submit the withdrawal, funds
+        // always go to the recipient bound in the signed message. This lets
+        // the relayer complete withdrawals, bridges deployed before it only
+        // accepted them from the recipient and must be redeployed.
 
         // The following two statements guard against reentry into this function.
         // Duplicated withdraw or reentry.
@@ -95,4 +111,25 @@ contract HomeBridge is Ownable {
 
         emit Withdraw(recipient, token, value, txHash);
     }
+
+    // reverts unless `message` is bound to this bridge, or is a legacy
+    // message while they are enabled, and has enough authority signatures
+    function requireValidMessage(uint8[] vs, bytes32[] rs, bytes32[] ss, bytes message) private view {
+        require(Message.isValid(message), "MESSAGE_LENGTH_MISMATCH");
+
+        if (Message.getVersion(message) == 0) {
+            require(!isLegacyMessageDisabled, "ERR_LEGACY_MESSAGE");
+            require(Helpers.hasEnoughValidSignatures(message, vs, rs, ss, authorities, requiredSignatures), "ERR_INSUFFICIENT_SIGNS");
+            return;
+        }
+
+        require(Message.getChainId(message) == chainId, "ERR_WRONG_CHAIN");
+        require(Message.getContract(message) == address(this), "ERR_WRONG_BRIDGE");
+        // signed either as bytes or as EIP-712 typed data
+        require(
+            Helpers.hasEnoughValidSignatures(message, vs, rs, ss, authorities, requiredSignatures) ||
+            Helpers.hasEnoughValidSignaturesOfHash(MessageSigning.hashTypedWithdrawal(message), vs, rs, ss, authorities, requiredSignatures),
+            "ERR_INSUFFICIENT_SIGNS"
+        );
+    }
 }
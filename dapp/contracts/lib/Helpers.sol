@@ -48,13 +48,24 @@ library Helpers {
         bytes32[] ss, 
         address[] allowed_signers, 
         uint256 requiredSignatures
+    ) internal pure returns (bool) {
+        return hasEnoughValidSignaturesOfHash(MessageSigning.hashMessage(message), vs, rs, ss, allowed_signers, requiredSignatures);
+    }
+
+    /// same as `hasEnoughValidSignatures` for signatures of `hash`
+    function hasEnoughValidSignaturesOfHash(
+        bytes32 hash, 
+        uint8[] vs, 
+        bytes32[] rs, 
+        bytes32[] ss, 
+        address[] allowed_signers, 
+        uint256 requiredSignatures
     ) internal pure returns (bool) {
         // not enough signatures
         if (vs.length < requiredSignatures) {
             return false;
         }
 
-        bytes32 hash = MessageSigning.hashMessage(message);
         address[] memory encountered_addresses = new address[](allowed_signers.length);
 
         for (uint256 i = 0; i < requiredSignatures; i++) {
@@ -11,6 +11,12 @@ library Message {
     // offset 72: 32 bytes :: uint256 (big endian) - value
     // offset 104: 32 bytes :: bytes32 - transaction hash
 
+    // versioned messages bind the layout above to one bridge, which starts
+    // at offset 85 instead:
+    // offset 32:  1 byte :: uint8 - version, 1
+    // offset 33: 32 bytes :: uint256 (big endian) - chain id
+    // offset 65: 20 bytes :: address - bridge contract address
+
     // mload always reads 32 bytes.
     // if mload reads an address it only interprets the last 20 bytes as the address.
     // so we can and have to start reading recipient at offset 20 instead of 32.
@@ -22,46 +28,109 @@ library Message {
     // for more details see discussion in:
     // https://github.com/paritytech/parity-bridge/issues/61
 
+    uint256 constant LEGACY_LENGTH = 104;
+    uint256 constant V1_LENGTH = 157;
+    uint256 constant V1_HEADER_LENGTH = 53;
+
+    function isValid(bytes message) internal pure returns (bool) {
+        return message.length == LEGACY_LENGTH || (message.length == V1_LENGTH && uint8(message[0]) == 1);
+    }
+
+    // returns 0 for legacy messages
+    function getVersion(bytes message) internal pure returns (uint8) {
+        if (message.length == LEGACY_LENGTH) {
+            return 0;
+        }
+        return uint8(message[0]);
+    }
+
+    function getChainId(bytes message) internal pure returns (uint256) {
+        require(getVersion(message) == 1, "ERR_UNVERSIONED_MESSAGE");
+        uint256 chainId;
+        // solium-disable-next-line security/no-inline-assembly
+        assembly {
+            chainId := mload(add(message, 33))
+        }
+        return chainId;
+    }
+
+    function getContract(bytes message) internal pure returns (address) {
+        require(getVersion(message) == 1, "ERR_UNVERSIONED_MESSAGE");
+        address bridge;
+        // solium-disable-next-line security/no-inline-assembly
+        assembly {
+            bridge := mload(add(message, 53))
+        }
+        return bridge;
+    }
+
     function getRecipient(bytes message) internal pure returns (address) {
+        uint256 offset = headerLength(message) + 20;
         address recipient;
         // solium-disable-next-line security/no-inline-assembly
         assembly {
-            recipient := mload(add(message, 20))
+            recipient := mload(add(message, offset))
         }
         return recipient;
     }
 
     function getToken(bytes message) internal pure returns (address) {
+        uint256 offset = headerLength(message) + 40;
         address token;
         // solium-disable-next-line security/no-inline-assembly
         assembly {
-            token := mload(add(message, 40))
+            token := mload(add(message, offset))
         }
         return token;
     }
 
     function getValue(bytes message) internal pure returns (uint256) {
+        uint256 offset = headerLength(message) + 72;
         uint256 value;
         // solium-disable-next-line security/no-inline-assembly
         assembly {
-            value := mload(add(message, 72))
+            value := mload(add(message, offset))
         }
         return value;
     }
 
     function getTransactionHash(bytes message) internal pure returns (bytes32) {
+        uint256 offset = headerLength(message) + 104;
         bytes32 hash;
         // solium-disable-next-line security/no-inline-assembly
         assembly {
-            hash := mload(add(message, 104))
+            hash := mload(add(message, offset))
         }
         return hash;
     }
+
+    function headerLength(bytes message) private pure returns (uint256) {
+        if (message.length == LEGACY_LENGTH) {
+            return 0;
+        }
+        return V1_HEADER_LENGTH;
+    }
 }
 
 
 /// Library used only to test Message library via rpc calls
 library MessageTest {
+    function isValid(bytes message) public pure returns (bool) {
+        return Message.isValid(message);
+    }
+
+    function getVersion(bytes message) public pure returns (uint8) {
+        return Message.getVersion(message);
+    }
+
+    function getChainId(bytes message) public pure returns (uint256) {
+        return Message.getChainId(message);
+    }
+
+    function getContract(bytes message) public pure returns (address) {
+        return Message.getContract(message);
+    }
+
     function getRecipient(bytes message) public pure returns (address) {
         return Message.getRecipient(message);
     }
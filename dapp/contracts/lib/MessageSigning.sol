@@ -1,11 +1,26 @@
 pragma solidity ^0.4.24;
 
 import "./Helpers.sol";
+import "./Message.sol";
 
 // helpers for message signing.
 // `internal` so they get compiled into contracts using them.
 library MessageSigning {
+    // EIP-712 types of versioned withdrawal messages
+    bytes32 constant EIP712_DOMAIN_TYPEHASH = keccak256("EIP712Domain(string name,string version,uint256 chainId,address verifyingContract)");
+    bytes32 constant WITHDRAWAL_TYPEHASH = keccak256("Withdrawal(address recipient,address token,uint256 amount,bytes32 transactionHash)");
+    bytes32 constant DOMAIN_NAME_HASH = keccak256("CDEX Bridge");
+    bytes32 constant DOMAIN_VERSION_HASH = keccak256("1");
+
     function recoverAddressFromSignedMessage(bytes signature, bytes message) internal pure returns (address) {
+        return recoverAddress(signature, hashMessage(message));
+    }
+
+    function recoverAddressFromSignedTypedWithdrawal(bytes signature, bytes message) internal pure returns (address) {
+        return recoverAddress(signature, hashTypedWithdrawal(message));
+    }
+
+    function recoverAddress(bytes signature, bytes32 hash) internal pure returns (address) {
         require(signature.length == 65);
         bytes32 r;
         bytes32 s;
@@ -25,7 +40,7 @@ library MessageSigning {
             v = v + 27;
         }
 
-        return ecrecover(hashMessage(message), v, r, s);
+        return ecrecover(hash, v, r, s);
     }
 
     function hashMessage(bytes message) internal pure returns (bytes32) {
@@ -33,6 +48,26 @@ library MessageSigning {
         // return keccak256(abi.encodePacked(prefix, Helpers.uintToString(message.length), message));
         return keccak256(message);
     }
+
+    // EIP-712 digest of a versioned withdrawal message, in the domain of the
+    // bridge the message is bound to
+    function hashTypedWithdrawal(bytes message) internal pure returns (bytes32) {
+        bytes32 domainSeparator = keccak256(abi.encode(
+            EIP712_DOMAIN_TYPEHASH,
+            DOMAIN_NAME_HASH,
+            DOMAIN_VERSION_HASH,
+            Message.getChainId(message),
+            Message.getContract(message)
+        ));
+        bytes32 structHash = keccak256(abi.encode(
+            WITHDRAWAL_TYPEHASH,
+            Message.getRecipient(message),
+            Message.getToken(message),
+            Message.getValue(message),
+            Message.getTransactionHash(message)
+        ));
+        return keccak256(abi.encodePacked("\x19\x01", domainSeparator, structHash));
+    }
 }
 
 
@@ -41,4 +76,12 @@ library MessageSigningTest {
     function recoverAddressFromSignedMessage(bytes signature, bytes message) public pure returns (address) {
         return MessageSigning.recoverAddressFromSignedMessage(signature, message);
     }
+
+    function recoverAddressFromSignedTypedWithdrawal(bytes signature, bytes message) public pure returns (address) {
+        return MessageSigning.recoverAddressFromSignedTypedWithdrawal(signature, message);
+    }
+
+    function hashTypedWithdrawal(bytes message) public pure returns (bytes32) {
+        return MessageSigning.hashTypedWithdrawal(message);
+    }
 }
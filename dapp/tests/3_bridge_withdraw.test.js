@@ -0,0 +1,205 @@
+let Web3 = require('web3');
+let Account = require('eth-lib/lib/account');
+let fs = require('fs');
+let networksConfig = require('./../../configs/network.json');
+
+let BN = require('bn.js')
+
+let web3 = new Web3();
+web3.setProvider(new web3.providers.HttpProvider(networksConfig.bridge.provider));
+
+let accounts;
+
+// Authorities of the bridge deployed by these tests. Withdraw messages are
+// signed with keccak256(message), which unlocked node accounts cannot do.
+let authority = web3.eth.accounts.create();
+
+let TEST_VALUES = {
+	token: "0x0000000000000000000000000000000000000000",
+	value: '1000000000',
+	chainId: '15',
+}
+
+let HomeBridge;
+
+jest.setTimeout(30000)
+
+describe('Withdraws', () => {
+	test('should deploy HomeBridge with a single authority', async (done) => {
+		accounts = await getAccounts()
+
+		HomeBridge = await deployHomeBridge(accounts[0], 1, [authority.address], TEST_VALUES.chainId)
+
+		await fund('4')
+
+		done()
+	})
+
+	test('should pay recipient of a withdraw submitted by a relayer', async (done) => {
+		accounts = await getAccounts()
+
+		let recipient = accounts[4];
+		let relayer = accounts[5];
+		let message = withdrawMessage(recipient, TEST_VALUES.token, TEST_VALUES.value, randomHash())
+
+		let balanceBefore = new BN(await web3.eth.getBalance(recipient))
+
+		await withdraw(message, relayer)
+
+		let balanceAfter = new BN(await web3.eth.getBalance(recipient))
+		expect(balanceAfter.sub(balanceBefore).toString()).toBe(TEST_VALUES.value)
+
+		done()
+	})
+
+	test('should not withdraw the same transaction twice', async (done) => {
+		accounts = await getAccounts()
+
+		let message = withdrawMessage(accounts[4], TEST_VALUES.token, TEST_VALUES.value, randomHash())
+		await withdraw(message, accounts[5])
+
+		await expect(withdraw(message, accounts[4])).rejects.toBeDefined()
+
+		done()
+	})
+
+	test('should pay recipient of a versioned message signed as bytes or typed data', async (done) => {
+		accounts = await getAccounts()
+
+		let recipient = accounts[4];
+		let balanceBefore = new BN(await web3.eth.getBalance(recipient))
+
+		let message = versionedMessage(TEST_VALUES.chainId, HomeBridge.options.address, recipient, TEST_VALUES.token, TEST_VALUES.value, randomHash())
+		await withdraw(message, accounts[5])
+
+		let txHash = randomHash()
+		let typedMessage = versionedMessage(TEST_VALUES.chainId, HomeBridge.options.address, recipient, TEST_VALUES.token, TEST_VALUES.value, txHash)
+		let digest = typedDataHash(TEST_VALUES.chainId, HomeBridge.options.address, recipient, TEST_VALUES.token, TEST_VALUES.value, txHash)
+		await withdrawSigned(typedMessage, digest, accounts[5])
+
+		let balanceAfter = new BN(await web3.eth.getBalance(recipient))
+		expect(balanceAfter.sub(balanceBefore).toString()).toBe(new BN(TEST_VALUES.value).muln(2).toString())
+
+		done()
+	})
+
+	test('should not withdraw a versioned message of another bridge', async (done) => {
+		accounts = await getAccounts()
+
+		let otherChain = versionedMessage('16', HomeBridge.options.address, accounts[4], TEST_VALUES.token, TEST_VALUES.value, randomHash())
+		await expect(withdraw(otherChain, accounts[5])).rejects.toBeDefined()
+
+		let otherBridge = versionedMessage(TEST_VALUES.chainId, accounts[0], accounts[4], TEST_VALUES.token, TEST_VALUES.value, randomHash())
+		await expect(withdraw(otherBridge, accounts[5])).rejects.toBeDefined()
+
+		done()
+	})
+
+	test('should not withdraw legacy messages once they are disabled', async (done) => {
+		accounts = await getAccounts()
+
+		await fund('1')
+		await HomeBridge.methods.toggleLegacyMessages(false).send({ from: accounts[0], gasPrice: 0 })
+
+		let message = withdrawMessage(accounts[4], TEST_VALUES.token, TEST_VALUES.value, randomHash())
+		await expect(withdraw(message, accounts[5])).rejects.toBeDefined()
+
+		done()
+	})
+})
+
+// fund deposits count times the test value into the bridge
+function fund(count) {
+	return HomeBridge.methods.deposit(TEST_VALUES.token, 0).send({
+		from: accounts[0],
+		value: new BN(TEST_VALUES.value).mul(new BN(count)).toString(),
+		gasPrice: 0
+	})
+}
+
+function deployHomeBridge(from, requiredSignatures, authorities, chainId) {
+	let contracts = JSON.parse(fs.readFileSync('dapp/build/HomeBridge.json'))['contracts'];
+	let HomeBridgeJSON = contracts['HomeBridge.sol:HomeBridge'];
+
+	return new web3.eth.Contract(JSON.parse(HomeBridgeJSON.abi))
+		.deploy({
+			data: '0x' + HomeBridgeJSON.bin,
+			arguments: [requiredSignatures, authorities, chainId]
+		})
+		.send({ from, gas: '3000000', gasPrice: 0 })
+}
+
+// withdrawMessage encodes recipient, token, value and transaction hash the
+// way Message.sol reads them
+function withdrawMessage(recipient, token, value, txHash) {
+	return '0x' +
+		recipient.slice(2).toLowerCase() +
+		token.slice(2).toLowerCase() +
+		web3.utils.padLeft(web3.utils.toHex(value), 64).slice(2) +
+		txHash.slice(2)
+}
+
+// versionedMessage binds the withdraw message to the chain and bridge
+function versionedMessage(chainId, bridge, recipient, token, value, txHash) {
+	return '0x01' +
+		web3.utils.padLeft(web3.utils.toHex(chainId), 64).slice(2) +
+		bridge.slice(2).toLowerCase() +
+		withdrawMessage(recipient, token, value, txHash).slice(2)
+}
+
+// typedDataHash returns the EIP-712 digest of a versioned withdraw message
+function typedDataHash(chainId, bridge, recipient, token, value, txHash) {
+	let domainSeparator = web3.utils.keccak256(web3.eth.abi.encodeParameters(
+		['bytes32', 'bytes32', 'bytes32', 'uint256', 'address'],
+		[
+			web3.utils.keccak256('EIP712Domain(string name,string version,uint256 chainId,address verifyingContract)'),
+			web3.utils.keccak256('CDEX Bridge'),
+			web3.utils.keccak256('1'),
+			chainId,
+			bridge
+		]
+	))
+	let structHash = web3.utils.keccak256(web3.eth.abi.encodeParameters(
+		['bytes32', 'address', 'address', 'uint256', 'bytes32'],
+		[
+			web3.utils.keccak256('Withdrawal(address recipient,address token,uint256 amount,bytes32 transactionHash)'),
+			recipient,
+			token,
+			value,
+			txHash
+		]
+	))
+
+	return web3.utils.keccak256('0x1901' + domainSeparator.slice(2) + structHash.slice(2))
+}
+
+function withdraw(message, from) {
+	return withdrawSigned(message, web3.utils.keccak256(message), from)
+}
+
+function withdrawSigned(message, hash, from) {
+	let signature = Account.decodeSignature(Account.sign(hash, authority.privateKey))
+
+	return HomeBridge.methods.withdraw([signature[0]], [signature[1]], [signature[2]], message)
+		.send({ from, gas: '500000', gasPrice: 0 })
+}
+
+function randomHash() {
+	return web3.utils.randomHex(32)
+}
+
+function getAccounts() {
+	return new Promise((resolve, reject) => {
+		if (accounts != null) {
+			resolve(accounts)
+		} else {
+			web3.eth.getAccounts(function (err, accounts_) {
+				if (err) {
+					reject(err);
+				} else {
+					resolve(accounts_);
+				}
+			});
+		}
+	})
+}
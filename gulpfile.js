@@ -180,7 +180,8 @@ gulp.task('deploy-bridge-contracts', async (done) => {
             contractJSON: HomeBridgeJSON,
             args: [
                 networksConfig.bridge.requiredSignatures,
-                networksConfig.authorities
+                networksConfig.authorities,
+                await getChainID(web3, networksConfig.bridge)
             ],
             txOpts
         });
@@ -398,6 +399,25 @@ function getWeb3Accounts(web3) {
     });
 }
 
+// getChainID returns chain ID of the network the way validators resolve it:
+// as configured, as reported by eth_chainId or else the network ID
+function getChainID(web3, network) {
+    if (network.chainId) {
+        return Promise.resolve(network.chainId);
+    }
+
+    return new Promise(function (resolve, reject) {
+        web3.currentProvider.send({ jsonrpc: '2.0', id: Date.now(), method: 'eth_chainId', params: [] }, function (err, response) {
+            if (!err && response && response.result) {
+                resolve(web3.utils.hexToNumberString(response.result));
+                return;
+            }
+
+            web3.eth.net.getId().then(resolve, reject);
+        });
+    });
+}
+
 function delay(timeInMillis) {
     return new Promise((resolve, _) => {
         setTimeout(resolve, timeInMillis)
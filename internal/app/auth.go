@@ -0,0 +1,164 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/gorilla/mux"
+	"hameid.net/cdex/dex/internal/auth"
+	"hameid.net/cdex/dex/internal/helpers"
+	"hameid.net/cdex/dex/internal/models"
+	"hameid.net/cdex/dex/internal/wrappers"
+)
+
+var errInvalidLoginRequest = helpers.NewError(http.StatusBadRequest, helpers.CodeInvalidRequest, "Request body must be JSON with `address` and `signature`")
+var errNotSessionWallet = helpers.NewError(http.StatusForbidden, helpers.CodeForbidden, "Session is not of this wallet")
+var errWithdrawRequestNotFound = helpers.NotFound("Withdraw request not found")
+
+type sessionContextKey struct{}
+
+type nonceRequest struct {
+	Address string `json:"address"`
+}
+
+type loginRequest struct {
+	Address   string `json:"address"`
+	Nonce     string `json:"nonce"`
+	Signature string `json:"signature"`
+}
+
+type nonceResponse struct {
+	Address string `json:"address"`
+	Nonce   string `json:"nonce"`
+	Message string `json:"message"`
+}
+
+// bearerToken returns session token of Authorization header
+func bearerToken(r *http.Request) string {
+	header := r.Header.Get("Authorization")
+	if len(header) < 7 || !strings.EqualFold(header[:7], "Bearer ") {
+		return ""
+	}
+
+	return strings.TrimSpace(header[7:])
+}
+
+// sessionAddress returns wallet of the session verified by requireSession
+func sessionAddress(r *http.Request) common.Address {
+	address, _ := r.Context().Value(sessionContextKey{}).(common.Address)
+	return address
+}
+
+// requireSession responds 401 to requests without a valid session token and
+// 403 if the address of the route is not the session's wallet
+func (app *App) requireSession(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		address, err := app.sessions.Verify(bearerToken(r))
+
+		switch err {
+		case nil:
+		case auth.ErrInvalidSession:
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			helpers.RespondWithError(w, r, helpers.NewError(http.StatusUnauthorized, helpers.CodeUnauthorized, err.Error()))
+			return
+		default:
+			helpers.RespondWithError(w, r, err)
+			return
+		}
+
+		if val, ok := mux.Vars(r)["address"]; ok && common.HexToAddress(val) != address {
+			helpers.RespondWithError(w, r, errNotSessionWallet)
+			return
+		}
+
+		next(w, r.WithContext(context.WithValue(r.Context(), sessionContextKey{}, address)))
+	}
+}
+
+// requireWithdrawRecipient responds 403 unless the session's wallet is the
+// recipient of the withdraw request of the route
+func (app *App) requireWithdrawRecipient(next http.HandlerFunc) http.HandlerFunc {
+	return app.requireSession(func(w http.ResponseWriter, r *http.Request) {
+		txHash := common.HexToHash(mux.Vars(r)["tx_hash"])
+		withdrawMeta := models.NewWithdrawMeta()
+		withdrawMeta.TxHash = wrappers.WrapHash(&txHash)
+
+		switch err := withdrawMeta.Get(app.store); err {
+		case nil:
+		case sql.ErrNoRows:
+			helpers.RespondWithError(w, r, errWithdrawRequestNotFound)
+			return
+		default:
+			helpers.RespondWithError(w, r, err)
+			return
+		}
+
+		if withdrawMeta.Recipient.Address != sessionAddress(r) {
+			helpers.RespondWithError(w, r, errNotSessionWallet)
+			return
+		}
+
+		next(w, r)
+	})
+}
+
+func (app *App) getLoginNonceHandler(w http.ResponseWriter, r *http.Request) {
+	var req nonceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || !common.IsHexAddress(req.Address) {
+		helpers.RespondWithError(w, r, errInvalidLoginRequest)
+		return
+	}
+
+	address := common.HexToAddress(req.Address)
+	nonce, err := app.sessions.NewNonce(address)
+
+	if err != nil {
+		helpers.RespondWithError(w, r, err)
+		return
+	}
+
+	helpers.RespondWithJSON(w, http.StatusOK, nonceResponse{
+		Address: strings.ToLower(address.Hex()),
+		Nonce:   nonce,
+		Message: auth.LoginMessage(address, nonce),
+	})
+}
+
+func (app *App) loginHandler(w http.ResponseWriter, r *http.Request) {
+	var req loginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || !common.IsHexAddress(req.Address) {
+		helpers.RespondWithError(w, r, errInvalidLoginRequest)
+		return
+	}
+
+	signature, err := hexutil.Decode(req.Signature)
+	if err != nil {
+		helpers.RespondWithError(w, r, errInvalidLoginRequest)
+		return
+	}
+
+	session, err := app.sessions.Login(common.HexToAddress(req.Address), req.Nonce, signature)
+
+	switch err {
+	case nil:
+		helpers.RespondWithJSON(w, http.StatusOK, session)
+	case auth.ErrInvalidNonce, auth.ErrInvalidSignature:
+		helpers.RespondWithError(w, r, helpers.NewError(http.StatusUnauthorized, helpers.CodeUnauthorized, err.Error()))
+	default:
+		helpers.RespondWithError(w, r, err)
+	}
+}
+
+func (app *App) logoutHandler(w http.ResponseWriter, r *http.Request) {
+	if err := app.sessions.Logout(bearerToken(r)); err != nil {
+		helpers.RespondWithError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
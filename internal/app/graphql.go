@@ -0,0 +1,817 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"hameid.net/cdex/dex/internal/auth"
+	"hameid.net/cdex/dex/internal/helpers"
+	"hameid.net/cdex/dex/internal/models"
+	"hameid.net/cdex/dex/internal/wrappers"
+)
+
+var errGraphQLSignIn = helpers.NewError(http.StatusUnauthorized, helpers.CodeUnauthorized, "Sign in to read private fields of the wallet")
+var errInvalidGraphQLRequest = helpers.NewError(http.StatusBadRequest, helpers.CodeInvalidRequest, "Request body must be JSON with `query`")
+
+type graphQLRequestContextKey struct{}
+
+// graphQLRequest is the body of GraphQL requests over HTTP and the payload
+// of subscribe messages
+type graphQLRequest struct {
+	Query         string                 `json:"query"`
+	Variables     map[string]interface{} `json:"variables"`
+	OperationName string                 `json:"operationName"`
+}
+
+// graphQLError reports APIError in the extensions of a GraphQL error
+type graphQLError struct {
+	*helpers.APIError
+}
+
+func (err graphQLError) Extensions() map[string]interface{} {
+	extensions := map[string]interface{}{"code": err.Code}
+	if err.Field != "" {
+		extensions["field"] = err.Field
+	}
+	if err.RequestID != "" {
+		extensions["request_id"] = err.RequestID
+	}
+	return extensions
+}
+
+// graphQLWallet is the source of Wallet fields
+type graphQLWallet struct {
+	Address common.Address
+}
+
+// graphQLEvent is a message of a pair channel
+type graphQLEvent struct {
+	Type  string
+	Order *models.Order
+}
+
+var bigIntScalar = graphql.NewScalar(graphql.ScalarConfig{
+	Name:        "BigInt",
+	Description: "Integer of any size as a base 10 string",
+	Serialize: func(value interface{}) interface{} {
+		switch v := value.(type) {
+		case *wrappers.BigInt:
+			return v.String()
+		case *big.Int:
+			return v.String()
+		}
+		return nil
+	},
+	ParseValue: func(value interface{}) interface{} {
+		if s, ok := value.(string); ok {
+			if v, ok := new(big.Int).SetString(s, 10); ok {
+				return v
+			}
+		}
+		return nil
+	},
+	ParseLiteral: func(valueAST ast.Value) interface{} {
+		switch v := valueAST.(type) {
+		case *ast.StringValue:
+			if i, ok := new(big.Int).SetString(v.Value, 10); ok {
+				return i
+			}
+		case *ast.IntValue:
+			if i, ok := new(big.Int).SetString(v.Value, 10); ok {
+				return i
+			}
+		}
+		return nil
+	},
+})
+
+// hexScalar is a string scalar of values with a Hex method, checked with
+// valid on input
+func hexScalar(name string, description string, valid func(string) bool) *graphql.Scalar {
+	parse := func(s string) interface{} {
+		if !valid(s) {
+			return nil
+		}
+		return strings.ToLower(s)
+	}
+
+	return graphql.NewScalar(graphql.ScalarConfig{
+		Name:        name,
+		Description: description,
+		Serialize: func(value interface{}) interface{} {
+			if v, ok := value.(interface{ Hex() string }); ok {
+				return strings.ToLower(v.Hex())
+			}
+			if s, ok := value.(string); ok {
+				return strings.ToLower(s)
+			}
+			return nil
+		},
+		ParseValue: func(value interface{}) interface{} {
+			if s, ok := value.(string); ok {
+				return parse(s)
+			}
+			return nil
+		},
+		ParseLiteral: func(valueAST ast.Value) interface{} {
+			if v, ok := valueAST.(*ast.StringValue); ok {
+				return parse(v.Value)
+			}
+			return nil
+		},
+	})
+}
+
+var addressScalar = hexScalar("Address", "Hex address with 0x prefix", func(s string) bool {
+	return compilePattern(addressPattern).MatchString(s)
+})
+
+var hashScalar = hexScalar("Hash", "Hex hash with 0x prefix", func(s string) bool {
+	return compilePattern(hashPattern).MatchString(s)
+})
+
+var timestampScalar = graphql.NewScalar(graphql.ScalarConfig{
+	Name:        "Timestamp",
+	Description: "Unix time in seconds",
+	Serialize: func(value interface{}) interface{} {
+		switch v := value.(type) {
+		case *wrappers.Timestamp:
+			return int64(v.Uint64())
+		case time.Time:
+			return v.Unix()
+		case *time.Time:
+			return v.Unix()
+		case int64:
+			return v
+		case uint64:
+			return int64(v)
+		}
+		return nil
+	},
+	ParseValue: func(value interface{}) interface{} {
+		switch v := value.(type) {
+		case int:
+			return int64(v)
+		case float64:
+			return int64(v)
+		}
+		return nil
+	},
+	ParseLiteral: func(valueAST ast.Value) interface{} {
+		if v, ok := valueAST.(*ast.IntValue); ok {
+			if i, ok := new(big.Int).SetString(v.Value, 10); ok && i.IsInt64() {
+				return i.Int64()
+			}
+		}
+		return nil
+	},
+})
+
+// pageType returns type of a page of items, see models.Page
+func pageType(name string, item graphql.Output) *graphql.Object {
+	return graphql.NewObject(graphql.ObjectConfig{
+		Name: name,
+		Fields: graphql.Fields{
+			"items":      &graphql.Field{Type: graphql.NewList(item)},
+			"nextCursor": &graphql.Field{Type: graphql.String, Description: "Cursor of the next page, null on last page"},
+		},
+	})
+}
+
+func pageArgs(args graphql.FieldConfigArgument) graphql.FieldConfigArgument {
+	args["count"] = &graphql.ArgumentConfig{Type: graphql.Int, Description: "Number of items, capped by the maximum page size"}
+	args["cursor"] = &graphql.ArgumentConfig{Type: graphql.String, Description: "nextCursor of the previous page"}
+	return args
+}
+
+func marketArgs() graphql.FieldConfigArgument {
+	return graphql.FieldConfigArgument{
+		"token": &graphql.ArgumentConfig{Type: graphql.NewNonNull(addressScalar)},
+		"base":  &graphql.ArgumentConfig{Type: graphql.NewNonNull(addressScalar)},
+	}
+}
+
+// newGraphQLSchema builds the schema of the GraphQL endpoint
+func (app *App) newGraphQLSchema() (graphql.Schema, error) {
+	orderFillType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "OrderFill",
+		Fields: graphql.Fields{
+			"counterpartyOrderHash": &graphql.Field{Type: hashScalar},
+			"price":                 &graphql.Field{Type: bigIntScalar},
+			"volume":                &graphql.Field{Type: bigIntScalar},
+			"tradedAt":              &graphql.Field{Type: timestampScalar},
+			"txHash":                &graphql.Field{Type: hashScalar},
+		},
+	})
+
+	orderFillsType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "OrderFills",
+		Fields: graphql.Fields{
+			"fills":           &graphql.Field{Type: graphql.NewList(orderFillType)},
+			"filledVolume":    &graphql.Field{Type: bigIntScalar},
+			"remainingVolume": &graphql.Field{Type: bigIntScalar},
+			"averagePrice":    &graphql.Field{Type: bigIntScalar},
+		},
+	})
+
+	orderType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Order",
+		Fields: graphql.Fields{
+			"hash":         &graphql.Field{Type: hashScalar},
+			"token":        &graphql.Field{Type: addressScalar},
+			"base":         &graphql.Field{Type: addressScalar},
+			"price":        &graphql.Field{Type: bigIntScalar},
+			"quantity":     &graphql.Field{Type: bigIntScalar},
+			"isBid":        &graphql.Field{Type: graphql.Boolean},
+			"createdAt":    &graphql.Field{Type: timestampScalar},
+			"createdBy":    &graphql.Field{Type: addressScalar},
+			"volume":       &graphql.Field{Type: bigIntScalar},
+			"volumeFilled": &graphql.Field{Type: bigIntScalar},
+			"isOpen":       &graphql.Field{Type: graphql.Boolean},
+			"fills": &graphql.Field{
+				Type:        orderFillsType,
+				Description: "Trades that filled the order",
+				Resolve:     app.resolveOrderFills,
+			},
+		},
+	})
+
+	tradeType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Trade",
+		Fields: graphql.Fields{
+			"buyOrderHash":  &graphql.Field{Type: hashScalar},
+			"sellOrderHash": &graphql.Field{Type: hashScalar},
+			"token":         &graphql.Field{Type: addressScalar},
+			"base":          &graphql.Field{Type: addressScalar},
+			"price":         &graphql.Field{Type: bigIntScalar},
+			"volume":        &graphql.Field{Type: bigIntScalar},
+			"tradedAt":      &graphql.Field{Type: timestampScalar},
+			"txHash":        &graphql.Field{Type: hashScalar},
+			"takeFee":       &graphql.Field{Type: bigIntScalar},
+			"makeFee":       &graphql.Field{Type: bigIntScalar},
+		},
+	})
+
+	tradeHistoryEntryType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "TradeHistoryEntry",
+		Fields: graphql.Fields{
+			"price":  &graphql.Field{Type: bigIntScalar},
+			"volume": &graphql.Field{Type: bigIntScalar},
+			"tradedAt": &graphql.Field{Type: timestampScalar, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				if trade, ok := p.Source.(*models.TradeHistoryResponse); ok {
+					return trade.Timestamp, nil
+				}
+				return p.Source.(models.TradeHistoryResponse).Timestamp, nil
+			}},
+		},
+	})
+
+	userTradeType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "UserTrade",
+		Fields: graphql.Fields{
+			"orderHash": &graphql.Field{Type: hashScalar},
+			"isBuy":     &graphql.Field{Type: graphql.Boolean},
+			"price":     &graphql.Field{Type: bigIntScalar},
+			"volume":    &graphql.Field{Type: bigIntScalar},
+			"tradedAt":  &graphql.Field{Type: timestampScalar},
+			"txHash":    &graphql.Field{Type: hashScalar},
+		},
+	})
+
+	balanceType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Balance",
+		Fields: graphql.Fields{
+			"token":   &graphql.Field{Type: addressScalar},
+			"balance": &graphql.Field{Type: bigIntScalar},
+			"escrow":  &graphql.Field{Type: bigIntScalar},
+		},
+	})
+
+	withdrawRequestType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "WithdrawRequest",
+		Fields: graphql.Fields{
+			"token":       &graphql.Field{Type: addressScalar},
+			"recipient":   &graphql.Field{Type: addressScalar},
+			"amount":      &graphql.Field{Type: bigIntScalar},
+			"txHash":      &graphql.Field{Type: hashScalar},
+			"status":      &graphql.Field{Type: graphql.Int, Description: "0 requested, 1 signed, 2 processed"},
+			"requestedAt": &graphql.Field{Type: timestampScalar},
+		},
+	})
+
+	orderbookLevelType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "OrderbookLevel",
+		Fields: graphql.Fields{
+			"price":              &graphql.Field{Type: bigIntScalar},
+			"quantity":           &graphql.Field{Type: bigIntScalar},
+			"volume":             &graphql.Field{Type: bigIntScalar},
+			"cumulativeQuantity": &graphql.Field{Type: bigIntScalar},
+			"cumulativeVolume":   &graphql.Field{Type: bigIntScalar},
+			"orders":             &graphql.Field{Type: graphql.Int},
+		},
+	})
+
+	orderbookSide := func(side func(*models.OrderbookResponse) *[]models.OrderbookResponseItem) *graphql.Field {
+		return &graphql.Field{
+			Type: graphql.NewList(orderbookLevelType),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				if levels := side(p.Source.(*models.OrderbookResponse)); levels != nil {
+					return *levels, nil
+				}
+				return nil, nil
+			},
+		}
+	}
+
+	orderbookType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Orderbook",
+		Fields: graphql.Fields{
+			"bids":      orderbookSide(func(ob *models.OrderbookResponse) *[]models.OrderbookResponseItem { return ob.Bids }),
+			"asks":      orderbookSide(func(ob *models.OrderbookResponse) *[]models.OrderbookResponseItem { return ob.Asks }),
+			"lastPrice": &graphql.Field{Type: bigIntScalar},
+		},
+	})
+
+	tickerType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Ticker",
+		Fields: graphql.Fields{
+			"token":         &graphql.Field{Type: addressScalar},
+			"base":          &graphql.Field{Type: addressScalar},
+			"lastPrice":     &graphql.Field{Type: bigIntScalar},
+			"openPrice":     &graphql.Field{Type: bigIntScalar},
+			"priceChange":   &graphql.Field{Type: bigIntScalar},
+			"changePercent": &graphql.Field{Type: graphql.Float},
+			"high":          &graphql.Field{Type: bigIntScalar},
+			"low":           &graphql.Field{Type: bigIntScalar},
+			"volume":        &graphql.Field{Type: bigIntScalar},
+			"trades":        &graphql.Field{Type: graphql.Int},
+			"bestBid":       &graphql.Field{Type: bigIntScalar},
+			"bestAsk":       &graphql.Field{Type: bigIntScalar},
+			"updatedAt":     &graphql.Field{Type: timestampScalar},
+		},
+	})
+
+	orderPageType := pageType("OrderPage", orderType)
+	orderFilterArgs := func() graphql.FieldConfigArgument {
+		return pageArgs(graphql.FieldConfigArgument{
+			"token":  &graphql.ArgumentConfig{Type: addressScalar},
+			"base":   &graphql.ArgumentConfig{Type: addressScalar},
+			"isBid":  &graphql.ArgumentConfig{Type: graphql.Boolean},
+			"isOpen": &graphql.ArgumentConfig{Type: graphql.Boolean},
+			"before": &graphql.ArgumentConfig{Type: timestampScalar, Description: "Orders created before, defaults to now"},
+		})
+	}
+
+	walletOrdersArgs := orderFilterArgs()
+	ordersArgs := orderFilterArgs()
+	ordersArgs["creator"] = &graphql.ArgumentConfig{Type: addressScalar}
+
+	walletTradesArgs := pageArgs(marketArgs())
+
+	walletType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Wallet",
+		Fields: graphql.Fields{
+			"address": &graphql.Field{Type: addressScalar},
+			"balances": &graphql.Field{
+				Type:        graphql.NewList(balanceType),
+				Description: "Token balances, requires a session of the wallet",
+				Resolve:     app.resolveWalletBalances,
+			},
+			"balance": &graphql.Field{
+				Type:        balanceType,
+				Description: "Balance of a token, requires a session of the wallet",
+				Args: graphql.FieldConfigArgument{
+					"token": &graphql.ArgumentConfig{Type: graphql.NewNonNull(addressScalar)},
+				},
+				Resolve: app.resolveWalletBalance,
+			},
+			"withdrawRequests": &graphql.Field{
+				Type:        graphql.NewList(withdrawRequestType),
+				Description: "Withdraw requests not processed yet, requires a session of the wallet",
+				Resolve:     app.resolveWalletWithdrawRequests,
+			},
+			"orders": &graphql.Field{
+				Type:        orderPageType,
+				Description: "Orders placed by the wallet, newest first",
+				Args:        walletOrdersArgs,
+				Resolve:     app.resolveOrders,
+			},
+			"trades": &graphql.Field{
+				Type:        pageType("UserTradePage", userTradeType),
+				Description: "Trades of the wallet in the market, newest first",
+				Args:        walletTradesArgs,
+				Resolve:     app.resolveWalletTrades,
+			},
+		},
+	})
+
+	orderEventType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "OrderEvent",
+		Fields: graphql.Fields{
+			"type":  &graphql.Field{Type: graphql.String, Description: "NEW_ORDER, CANCEL_ORDER or ORDER_FILL"},
+			"order": &graphql.Field{Type: orderType},
+		},
+	})
+
+	orderbookArgs := marketArgs()
+	orderbookArgs["depth"] = &graphql.ArgumentConfig{Type: graphql.Int, Description: "Number of price levels on each side"}
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"order": &graphql.Field{
+				Type: orderType,
+				Args: graphql.FieldConfigArgument{
+					"hash": &graphql.ArgumentConfig{Type: graphql.NewNonNull(hashScalar)},
+				},
+				Resolve: app.resolveOrder,
+			},
+			"orders": &graphql.Field{
+				Type:        orderPageType,
+				Description: "Orders, newest first",
+				Args:        ordersArgs,
+				Resolve:     app.resolveOrders,
+			},
+			"trades": &graphql.Field{
+				Type:        pageType("TradeHistoryPage", tradeHistoryEntryType),
+				Description: "Trades of the market, newest first",
+				Args:        pageArgs(marketArgs()),
+				Resolve:     app.resolveTradeHistory,
+			},
+			"orderbook": &graphql.Field{
+				Type:    orderbookType,
+				Args:    orderbookArgs,
+				Resolve: app.resolveOrderbook,
+			},
+			"wallet": &graphql.Field{
+				Type: walletType,
+				Args: graphql.FieldConfigArgument{
+					"address": &graphql.ArgumentConfig{Type: graphql.NewNonNull(addressScalar)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return &graphQLWallet{common.HexToAddress(p.Args["address"].(string))}, nil
+				},
+			},
+		},
+	})
+
+	subscription := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Subscription",
+		Fields: graphql.Fields{
+			"trades": &graphql.Field{
+				Type:        tradeType,
+				Description: "Trades of the market as they happen",
+				Args:        marketArgs(),
+				Subscribe:   app.subscribePair(func(event *pairEvent) (interface{}, error) { return event.decode("TRADE", &models.Trade{}) }),
+				Resolve:     resolveSource,
+			},
+			"orders": &graphql.Field{
+				Type:        orderEventType,
+				Description: "Orders placed, cancelled and filled in the market",
+				Args:        marketArgs(),
+				Subscribe: app.subscribePair(func(event *pairEvent) (interface{}, error) {
+					switch event.MessageType {
+					case "NEW_ORDER", "CANCEL_ORDER", "ORDER_FILL":
+						order := &models.Order{}
+						if err := json.Unmarshal(event.Payload, order); err != nil {
+							return nil, err
+						}
+						return &graphQLEvent{event.MessageType, order}, nil
+					}
+					return nil, nil
+				}),
+				Resolve: resolveSource,
+			},
+			"ticker": &graphql.Field{
+				Type:        tickerType,
+				Description: "Ticker of the market, published periodically",
+				Args:        marketArgs(),
+				Subscribe:   app.subscribePair(func(event *pairEvent) (interface{}, error) { return event.decode("TICKER", &models.Ticker{}) }),
+				Resolve:     resolveSource,
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{
+		Query:        query,
+		Subscription: subscription,
+	})
+}
+
+func resolveSource(p graphql.ResolveParams) (interface{}, error) {
+	return p.Source, nil
+}
+
+// graphQLResolveError converts err as helpers.ToAPIError does
+func graphQLResolveError(ctx context.Context, err error) error {
+	r, ok := ctx.Value(graphQLRequestContextKey{}).(*http.Request)
+	if !ok {
+		return err
+	}
+
+	return graphQLError{helpers.ToAPIError(r, err)}
+}
+
+// requireWalletSession checks that the request has a session of the wallet
+func requireWalletSession(ctx context.Context, wallet common.Address) error {
+	address, ok := ctx.Value(sessionContextKey{}).(common.Address)
+	if !ok {
+		return graphQLResolveError(ctx, errGraphQLSignIn)
+	}
+	if address != wallet {
+		return graphQLResolveError(ctx, errNotSessionWallet)
+	}
+
+	return nil
+}
+
+// graphQLPage returns page size and cursor arguments of a paginated field
+func (app *App) graphQLPage(p graphql.ResolveParams, cursorKeys int) (int, *models.Cursor, error) {
+	count, maxCount := app.pageSizes()
+	if val, ok := p.Args["count"].(int); ok && val >= 1 && val <= maxCount {
+		count = val
+	} else if ok {
+		count = maxCount
+	}
+
+	if val, ok := p.Args["cursor"].(string); ok && len(val) > 0 {
+		cursor, err := models.DecodeCursor(val, cursorKeys)
+		if err != nil {
+			return 0, nil, graphQLResolveError(p.Context, errInvalidCursorParam)
+		}
+		return count, cursor, nil
+	}
+
+	return count, nil, nil
+}
+
+// marketArg returns the market of token and base arguments if it is listed
+func (app *App) marketArg(p graphql.ResolveParams) (*common.Address, *common.Address, error) {
+	token := common.HexToAddress(p.Args["token"].(string))
+	base := common.HexToAddress(p.Args["base"].(string))
+
+	if !app.isMarketAvailable(&token, &base) {
+		return nil, nil, graphQLResolveError(p.Context, errMarketNotListed)
+	}
+
+	return &token, &base, nil
+}
+
+func sourceOrder(source interface{}) *models.Order {
+	if order, ok := source.(*models.Order); ok {
+		return order
+	}
+	order := source.(models.Order)
+	return &order
+}
+
+func (app *App) resolveOrder(p graphql.ResolveParams) (interface{}, error) {
+	hash := common.HexToHash(p.Args["hash"].(string))
+
+	order := models.NewOrder()
+	order.Hash = wrappers.WrapHash(&hash)
+
+	switch err := order.Get(app.store); err {
+	case nil:
+		return order, nil
+	case sql.ErrNoRows:
+		return nil, nil
+	default:
+		return nil, graphQLResolveError(p.Context, err)
+	}
+}
+
+func (app *App) resolveOrderFills(p graphql.ResolveParams) (interface{}, error) {
+	fills, err := sourceOrder(p.Source).GetFills(app.store)
+	if err != nil {
+		return nil, graphQLResolveError(p.Context, err)
+	}
+
+	return fills, nil
+}
+
+// resolveOrders serves orders of the query and of a wallet
+func (app *App) resolveOrders(p graphql.ResolveParams) (interface{}, error) {
+	params := make(map[string]interface{})
+
+	params["before"] = uint64(time.Now().Unix())
+	if val, ok := p.Args["before"].(int64); ok {
+		params["before"] = uint64(val)
+	}
+	for _, name := range []string{"token", "base", "creator"} {
+		if val, ok := p.Args[name].(string); ok {
+			params[name] = val
+		}
+	}
+	if wallet, ok := p.Source.(*graphQLWallet); ok {
+		params["creator"] = strings.ToLower(wallet.Address.Hex())
+	}
+	// 0 selects buy and open orders, see buildWhereConstraintFromParams
+	if val, ok := p.Args["isBid"].(bool); ok {
+		params["side"] = boolToFilter(val)
+	}
+	if val, ok := p.Args["isOpen"].(bool); ok {
+		params["status"] = boolToFilter(val)
+	}
+
+	count, cursor, err := app.graphQLPage(p, 1)
+	if err != nil {
+		return nil, err
+	}
+	params["count"] = count
+	if cursor != nil {
+		params["cursor"] = cursor
+	}
+
+	page, err := models.GetOrders(app.store, &params)
+	if err != nil {
+		return nil, graphQLResolveError(p.Context, err)
+	}
+
+	return page, nil
+}
+
+func boolToFilter(val bool) int {
+	if val {
+		return 0
+	}
+	return 1
+}
+
+func (app *App) resolveTradeHistory(p graphql.ResolveParams) (interface{}, error) {
+	token, base, err := app.marketArg(p)
+	if err != nil {
+		return nil, err
+	}
+
+	count, cursor, err := app.graphQLPage(p, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	page, err := models.GetTradeHistory(app.store, token, base, count, cursor)
+	if err != nil {
+		return nil, graphQLResolveError(p.Context, err)
+	}
+
+	return page, nil
+}
+
+func (app *App) resolveOrderbook(p graphql.ResolveParams) (interface{}, error) {
+	if _, _, err := app.marketArg(p); err != nil {
+		return nil, err
+	}
+
+	params := map[string]interface{}{
+		"token": p.Args["token"],
+		"base":  p.Args["base"],
+	}
+	if depth, ok := p.Args["depth"].(int); ok {
+		if depth < 1 || depth > models.MaxOrderbookDepth {
+			return nil, graphQLResolveError(p.Context, errInvalidDepthParam)
+		}
+		params["depth"] = depth
+	}
+
+	orderbook, err := models.GetOrderbook(app.store, &params)
+	if err != nil {
+		return nil, graphQLResolveError(p.Context, err)
+	}
+
+	return orderbook, nil
+}
+
+func (app *App) resolveWalletBalances(p graphql.ResolveParams) (interface{}, error) {
+	wallet := p.Source.(*graphQLWallet)
+	if err := requireWalletSession(p.Context, wallet.Address); err != nil {
+		return nil, err
+	}
+
+	balances, err := models.GetTokenBalancesForWallet(app.store, wrappers.WrapAddress(&wallet.Address))
+	if err != nil {
+		return nil, graphQLResolveError(p.Context, err)
+	}
+
+	return balances, nil
+}
+
+func (app *App) resolveWalletBalance(p graphql.ResolveParams) (interface{}, error) {
+	wallet := p.Source.(*graphQLWallet)
+	if err := requireWalletSession(p.Context, wallet.Address); err != nil {
+		return nil, err
+	}
+
+	token := common.HexToAddress(p.Args["token"].(string))
+	balance := models.NewWallet(wrappers.WrapAddress(&wallet.Address), wrappers.WrapAddress(&token))
+
+	switch err := balance.GetBalance(app.store); err {
+	case nil:
+	case sql.ErrNoRows:
+		balance.Balance = wrappers.WrapBigInt(big.NewInt(0))
+		balance.EscrowBalance = wrappers.WrapBigInt(big.NewInt(0))
+	default:
+		return nil, graphQLResolveError(p.Context, err)
+	}
+
+	return balance, nil
+}
+
+func (app *App) resolveWalletWithdrawRequests(p graphql.ResolveParams) (interface{}, error) {
+	wallet := p.Source.(*graphQLWallet)
+	if err := requireWalletSession(p.Context, wallet.Address); err != nil {
+		return nil, err
+	}
+
+	requests, err := models.GetUnprocessedWithdrawRequests(app.store, wrappers.WrapAddress(&wallet.Address))
+	if err != nil && err != sql.ErrNoRows {
+		return nil, graphQLResolveError(p.Context, err)
+	}
+
+	return requests, nil
+}
+
+func (app *App) resolveWalletTrades(p graphql.ResolveParams) (interface{}, error) {
+	wallet := p.Source.(*graphQLWallet)
+
+	token, base, err := app.marketArg(p)
+	if err != nil {
+		return nil, err
+	}
+
+	count, cursor, err := app.graphQLPage(p, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	page, err := models.GetTradesOfUser(app.store, token, base, &wallet.Address, count, cursor)
+	if err != nil {
+		return nil, graphQLResolveError(p.Context, err)
+	}
+
+	return page, nil
+}
+
+// graphQLHandler executes GraphQL queries. Private fields of a wallet need
+// a session of the wallet in the Authorization header. Subscriptions are
+// served over websocket on the same path.
+func (app *App) graphQLHandler(w http.ResponseWriter, r *http.Request) {
+	if strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+		app.serveGraphQLWebsocket(w, r)
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), graphQLRequestContextKey{}, r)
+
+	if token := bearerToken(r); token != "" {
+		address, err := app.sessions.Verify(token)
+		switch err {
+		case nil:
+			ctx = context.WithValue(ctx, sessionContextKey{}, address)
+		case auth.ErrInvalidSession:
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			helpers.RespondWithError(w, r, helpers.NewError(http.StatusUnauthorized, helpers.CodeUnauthorized, err.Error()))
+			return
+		default:
+			helpers.RespondWithError(w, r, err)
+			return
+		}
+	}
+
+	var req graphQLRequest
+	if r.Method == "GET" {
+		req.Query = r.FormValue("query")
+		req.OperationName = r.FormValue("operationName")
+		if vars := r.FormValue("variables"); vars != "" {
+			if err := json.Unmarshal([]byte(vars), &req.Variables); err != nil {
+				helpers.RespondWithError(w, r, errInvalidGraphQLRequest)
+				return
+			}
+		}
+	} else if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		helpers.RespondWithError(w, r, errInvalidGraphQLRequest)
+		return
+	}
+
+	if req.Query == "" {
+		helpers.RespondWithError(w, r, errInvalidGraphQLRequest)
+		return
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema:         app.graphQLSchema,
+		RequestString:  req.Query,
+		VariableValues: req.Variables,
+		OperationName:  req.OperationName,
+		Context:        ctx,
+	})
+
+	helpers.RespondWithJSON(w, http.StatusOK, result)
+}
@@ -0,0 +1,61 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"hameid.net/cdex/dex/internal/models"
+)
+
+func TestGraphQLWalletRequiresSession(t *testing.T) {
+	app := &App{}
+	schema, err := app.newGraphQLSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `{ wallet(address: "0x00000000000000000000000000000000000000AA") { address balances { balance } } }`,
+		Context:       context.Background(),
+	})
+
+	wallet := result.Data.(map[string]interface{})["wallet"].(map[string]interface{})
+	if wallet["address"] != "0x00000000000000000000000000000000000000aa" {
+		t.Errorf("unexpected address %v", wallet["address"])
+	}
+	if wallet["balances"] != nil || len(result.Errors) != 1 {
+		t.Fatalf("balances resolved without session: %v", result)
+	}
+
+	result = graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `{ order(hash: "0x12") { hash } }`,
+		Context:       context.Background(),
+	})
+	if !result.HasErrors() {
+		t.Error("invalid hash accepted")
+	}
+}
+
+func TestPairEventDecode(t *testing.T) {
+	event := &pairEvent{
+		MessageType: "NEW_ORDER",
+		Payload:     []byte(`{"order_hash":"0x1111111111111111111111111111111111111111111111111111111111111111","price":"100","is_bid":true,"created_at":1540000000}`),
+	}
+
+	if source, err := event.decode("TRADE", &models.Trade{}); source != nil || err != nil {
+		t.Errorf("event of other type decoded: %v %v", source, err)
+	}
+
+	source, err := event.decode("NEW_ORDER", &models.Order{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	order := source.(*models.Order)
+	if order.Price.String() != "100" || !order.IsBid || order.CreatedAt.Uint64() != 1540000000 {
+		t.Errorf("unexpected order %+v", order)
+	}
+}
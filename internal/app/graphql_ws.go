@@ -0,0 +1,384 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/graphql-go/graphql"
+	"hameid.net/cdex/dex/internal/auth"
+)
+
+// Subscriptions are served with the graphql-transport-ws protocol, see
+// https://github.com/enisdenjo/graphql-ws/blob/master/PROTOCOL.md
+const graphQLSubprotocol = "graphql-transport-ws"
+
+const (
+	// Time allowed to send connection_init after connecting
+	graphQLInitWait = 10 * time.Second
+
+	// Time allowed to write a message to the peer
+	graphQLWriteWait = 10 * time.Second
+
+	// Maximum message size allowed from peer
+	graphQLMaxMessageSize = 64 * 1024
+
+	// Maximum number of subscriptions of a connection
+	graphQLMaxSubscriptions = 50
+)
+
+// Close codes of the protocol
+const (
+	graphQLCloseInvalidMessage   = 4400
+	graphQLCloseUnauthorized     = 4401
+	graphQLCloseForbidden        = 4403
+	graphQLCloseInitTimeout      = 4408
+	graphQLCloseSubscriberExists = 4409
+	graphQLCloseTooManyInits     = 4429
+	graphQLCloseInternalError    = 4500
+)
+
+type graphQLMessage struct {
+	ID      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+type graphQLInitPayload struct {
+	Token string `json:"token"`
+}
+
+// pairEvent is a message the relayer publishes to a pair channel
+type pairEvent struct {
+	MessageType string          `json:"messageType"`
+	Payload     json.RawMessage `json:"messageContent"`
+}
+
+// decode unmarshals payload into v if the event is of messageType, and
+// returns nil otherwise
+func (event *pairEvent) decode(messageType string, v interface{}) (interface{}, error) {
+	if event.MessageType != messageType {
+		return nil, nil
+	}
+
+	if err := json.Unmarshal(event.Payload, v); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// subscribePair returns a Subscribe function of a field that streams events
+// of the pair channel of token and base arguments. decode returns the
+// source of the field for an event, or nil to skip it.
+func (app *App) subscribePair(decode func(*pairEvent) (interface{}, error)) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		token, base, err := app.marketArg(p)
+		if err != nil {
+			return nil, err
+		}
+
+		pubsub := app.redisClient.Subscribe(strings.ToLower(token.Hex() + "/" + base.Hex()))
+		if _, err := pubsub.Receive(); err != nil {
+			pubsub.Close()
+			return nil, graphQLResolveError(p.Context, err)
+		}
+
+		events := make(chan interface{})
+
+		go func() {
+			defer close(events)
+			defer pubsub.Close()
+
+			messages := pubsub.Channel()
+
+			for {
+				select {
+				case <-p.Context.Done():
+					return
+				case msg, ok := <-messages:
+					if !ok {
+						return
+					}
+
+					var event pairEvent
+					if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+						log.Println("GRAPHQL_SUBSCRIPTION", err)
+						continue
+					}
+
+					source, err := decode(&event)
+					if err != nil {
+						log.Println("GRAPHQL_SUBSCRIPTION", err)
+						continue
+					}
+					if source == nil {
+						continue
+					}
+
+					select {
+					case events <- source:
+					case <-p.Context.Done():
+						return
+					}
+				}
+			}
+		}()
+
+		return events, nil
+	}
+}
+
+// graphQLSocket is a websocket connection serving subscriptions
+type graphQLSocket struct {
+	app  *App
+	conn *websocket.Conn
+	ctx  context.Context
+
+	// Guards writes to conn
+	writeMu sync.Mutex
+
+	mu            sync.Mutex
+	acknowledged  bool
+	subscriptions map[string]context.CancelFunc
+}
+
+// serveGraphQLWebsocket serves subscriptions of a websocket connection. A
+// session token can be sent in the payload of connection_init.
+func (app *App) serveGraphQLWebsocket(w http.ResponseWriter, r *http.Request) {
+	// Counted before upgrading, while the server still waits for the request
+	app.sockets.Add(1)
+	defer app.sockets.Done()
+
+	upgrader := websocket.Upgrader{
+		ReadBufferSize:  1024,
+		WriteBufferSize: 1024,
+		Subprotocols:    []string{graphQLSubprotocol},
+		CheckOrigin: func(r *http.Request) bool {
+			origin := r.Header.Get("Origin")
+			return origin == "" || app.allowOrigin(origin)
+		},
+	}
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade has responded with the error
+		return
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(context.WithValue(r.Context(), graphQLRequestContextKey{}, r))
+	defer cancel()
+
+	socket := &graphQLSocket{
+		app:           app,
+		conn:          conn,
+		ctx:           ctx,
+		subscriptions: make(map[string]context.CancelFunc),
+	}
+
+	conn.SetReadLimit(graphQLMaxMessageSize)
+	conn.SetReadDeadline(time.Now().Add(graphQLInitWait))
+
+	// Peers answer the close frame, reading ends when they do or time out
+	go func() {
+		select {
+		case <-app.closing:
+			socket.close(websocket.CloseGoingAway, "Server is shutting down")
+			conn.SetReadDeadline(time.Now().Add(graphQLWriteWait))
+		case <-ctx.Done():
+		}
+	}()
+
+	for {
+		var msg graphQLMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			select {
+			case <-app.closing:
+				// Closed by the server
+				return
+			default:
+			}
+
+			if netErr, ok := err.(interface{ Timeout() bool }); ok && netErr.Timeout() {
+				socket.close(graphQLCloseInitTimeout, "Connection initialisation timeout")
+			} else if _, ok := err.(*json.SyntaxError); ok {
+				socket.close(graphQLCloseInvalidMessage, "Invalid message")
+			}
+			return
+		}
+
+		if !socket.handle(&msg) {
+			return
+		}
+	}
+}
+
+// handle handles a message from the peer and returns false if the
+// connection has been closed
+func (socket *graphQLSocket) handle(msg *graphQLMessage) bool {
+	switch msg.Type {
+	case "connection_init":
+		return socket.init(msg)
+
+	case "ping":
+		socket.write(&graphQLMessage{Type: "pong"})
+
+	case "pong":
+
+	case "subscribe":
+		return socket.subscribe(msg)
+
+	case "complete":
+		socket.mu.Lock()
+		if cancel, ok := socket.subscriptions[msg.ID]; ok {
+			cancel()
+			delete(socket.subscriptions, msg.ID)
+		}
+		socket.mu.Unlock()
+
+	default:
+		socket.close(graphQLCloseInvalidMessage, "Invalid message type")
+		return false
+	}
+
+	return true
+}
+
+func (socket *graphQLSocket) init(msg *graphQLMessage) bool {
+	socket.mu.Lock()
+	defer socket.mu.Unlock()
+
+	if socket.acknowledged {
+		socket.close(graphQLCloseTooManyInits, "Too many initialisation requests")
+		return false
+	}
+
+	var payload graphQLInitPayload
+	if len(msg.Payload) > 0 {
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			socket.close(graphQLCloseInvalidMessage, "Invalid connection_init payload")
+			return false
+		}
+	}
+
+	if payload.Token != "" {
+		address, err := socket.app.sessions.Verify(payload.Token)
+		switch err {
+		case nil:
+			socket.ctx = context.WithValue(socket.ctx, sessionContextKey{}, address)
+		case auth.ErrInvalidSession:
+			socket.close(graphQLCloseForbidden, "Forbidden")
+			return false
+		default:
+			log.Println("GRAPHQL_SOCKET", err)
+			socket.close(graphQLCloseInternalError, "Internal error")
+			return false
+		}
+	}
+
+	socket.acknowledged = true
+	socket.conn.SetReadDeadline(time.Time{})
+	socket.write(&graphQLMessage{Type: "connection_ack"})
+
+	return true
+}
+
+func (socket *graphQLSocket) subscribe(msg *graphQLMessage) bool {
+	var req graphQLRequest
+	if msg.ID == "" || json.Unmarshal(msg.Payload, &req) != nil || req.Query == "" {
+		socket.close(graphQLCloseInvalidMessage, "Invalid subscribe message")
+		return false
+	}
+
+	socket.mu.Lock()
+	defer socket.mu.Unlock()
+
+	if !socket.acknowledged {
+		socket.close(graphQLCloseUnauthorized, "Unauthorized")
+		return false
+	}
+	if _, ok := socket.subscriptions[msg.ID]; ok {
+		socket.close(graphQLCloseSubscriberExists, "Subscriber for "+msg.ID+" already exists")
+		return false
+	}
+	if len(socket.subscriptions) >= graphQLMaxSubscriptions {
+		socket.writeErrors(msg.ID, "Too many subscriptions")
+		return true
+	}
+
+	ctx, cancel := context.WithCancel(socket.ctx)
+	socket.subscriptions[msg.ID] = cancel
+
+	results := graphql.Subscribe(graphql.Params{
+		Schema:         socket.app.graphQLSchema,
+		RequestString:  req.Query,
+		VariableValues: req.Variables,
+		OperationName:  req.OperationName,
+		Context:        ctx,
+	})
+
+	go func() {
+		for result := range results {
+			if result.Data == nil && result.HasErrors() {
+				socket.writeJSON(msg.ID, "error", result.Errors)
+				cancel()
+			} else if ctx.Err() == nil {
+				socket.writeJSON(msg.ID, "next", result)
+			}
+		}
+
+		socket.mu.Lock()
+		defer socket.mu.Unlock()
+
+		// Peer has completed the subscription if it is gone from the map
+		if _, ok := socket.subscriptions[msg.ID]; !ok {
+			return
+		}
+		delete(socket.subscriptions, msg.ID)
+		cancel()
+
+		if socket.ctx.Err() == nil {
+			socket.write(&graphQLMessage{ID: msg.ID, Type: "complete"})
+		}
+	}()
+
+	return true
+}
+
+func (socket *graphQLSocket) writeErrors(id string, message string) {
+	socket.writeJSON(id, "error", []map[string]string{{"message": message}})
+}
+
+func (socket *graphQLSocket) writeJSON(id string, messageType string, payload interface{}) {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		log.Println("GRAPHQL_SOCKET", err)
+		return
+	}
+
+	socket.write(&graphQLMessage{ID: id, Type: messageType, Payload: raw})
+}
+
+func (socket *graphQLSocket) write(msg *graphQLMessage) {
+	socket.writeMu.Lock()
+	defer socket.writeMu.Unlock()
+
+	socket.conn.SetWriteDeadline(time.Now().Add(graphQLWriteWait))
+	if err := socket.conn.WriteJSON(msg); err != nil {
+		// Reading fails too and ends the connection
+		socket.conn.Close()
+	}
+}
+
+func (socket *graphQLSocket) close(code int, text string) {
+	socket.writeMu.Lock()
+	defer socket.writeMu.Unlock()
+
+	socket.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, text), time.Now().Add(graphQLWriteWait))
+}
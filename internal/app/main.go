@@ -1,55 +1,114 @@
 package app
 
 import (
+	"context"
 	"fmt"
-	"log"
 	"net/http"
+	"sync"
 
+	"github.com/go-redis/redis"
 	"github.com/gorilla/handlers"
 	"github.com/gorilla/mux"
+	"github.com/graphql-go/graphql"
+	"hameid.net/cdex/dex/internal/auth"
+	"hameid.net/cdex/dex/internal/config"
+	"hameid.net/cdex/dex/internal/health"
+	"hameid.net/cdex/dex/internal/helpers"
+	"hameid.net/cdex/dex/internal/lifecycle"
+	"hameid.net/cdex/dex/internal/metrics"
 	"hameid.net/cdex/dex/internal/store"
+	"hameid.net/cdex/dex/internal/utils"
 )
 
 // App layer struct
 type App struct {
-	router *mux.Router
-	store  *store.DataStore
-	server *http.Server
-	port   string
+	router        *mux.Router
+	store         *store.DataStore
+	redisClient   *redis.Client
+	server        *http.Server
+	port          string
+	networks      *utils.NetworksInfo
+	settings      settings
+	tickers       tickerCache
+	sessions      *auth.Sessions
+	openAPI       *openAPIDocument
+	graphQLSchema graphql.Schema
+	health        *health.Health
+
+	// Closed when the server shuts down, to close websockets
+	closing chan struct{}
+	// Handlers of websockets, hijacked from the server
+	sockets sync.WaitGroup
 }
 
-// Start starts app server
-func (app *App) Start() {
+// Run serves the app until ctx is done, then drains in-flight requests and
+// closes websockets
+func (app *App) Run(ctx context.Context) error {
 	app.store.Initialize()
 
 	app.InitializeRoutes()
 
-	corsObj := handlers.AllowedOrigins([]string{"*"})
+	corsObj := handlers.AllowedOriginValidator(app.allowOrigin)
+	corsHeaders := handlers.AllowedHeaders([]string{"Authorization", "Content-Type"})
+	corsExposedHeaders := handlers.ExposedHeaders([]string{helpers.RequestIDHeader, "X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset", "Retry-After"})
 
 	app.server = &http.Server{
 		Addr:    app.port,
-		Handler: handlers.CompressHandler(handlers.CORS(corsObj)(app.router))}
+		Handler: app.health.Wrap(metrics.Wrap(handlers.CompressHandler(handlers.CORS(corsObj, corsHeaders, corsExposedHeaders)(helpers.RequestID(app.router)))))}
+
+	app.server.RegisterOnShutdown(func() { close(app.closing) })
 
 	fmt.Printf("Running app server on address %s\n", app.server.Addr)
 
-	err := app.server.ListenAndServe()
+	errs := make(chan error, 1)
+	go func() {
+		errs <- app.server.ListenAndServe()
+	}()
 
-	if err != nil {
-		log.Fatal(err)
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
 	}
+
+	return app.shutdown()
 }
 
-// Shutdown terminates process and cleans up
-func (app *App) Shutdown() {
-	app.server.Shutdown(nil)
+// shutdown stops accepting requests, waits for in-flight requests and
+// websockets and closes connections
+func (app *App) shutdown() error {
+	ctx, cancel := lifecycle.ShutdownContext()
+	defer cancel()
+
+	fmt.Println("Draining app server...")
+	err := app.server.Shutdown(ctx)
+	if err == nil {
+		err = lifecycle.Wait(ctx, &app.sockets)
+	}
+
+	app.redisClient.Close()
 	app.store.Close()
+
+	return err
 }
 
 // NewApp creates new instance of App struct
-func NewApp(port uint, connectionString string) *App {
-	return &App{
-		router: mux.NewRouter(),
-		store:  store.NewDataStore(connectionString),
-		port:   fmt.Sprintf(":%d", port),
+func NewApp(cfg *config.Config) *App {
+	redisClient := store.NewRedisClient(cfg.Redis.Host, cfg.Redis.Password)
+
+	app := &App{
+		router:      mux.NewRouter(),
+		store:       store.NewDataStore(cfg.Database.ConnectionString),
+		redisClient: redisClient,
+		port:        fmt.Sprintf(":%d", cfg.App.Port),
+		networks:    cfg.Networks,
+		sessions:    auth.NewSessions(redisClient, cfg.Auth.SessionTTL, cfg.Auth.NonceTTL),
+		health:      health.NewHealth(config.ServiceApp),
+		closing:     make(chan struct{}),
 	}
+	app.health.AddCheck("postgres", health.Postgres(app.store))
+	app.health.AddCheck("redis", health.Redis(redisClient))
+	app.Reload(cfg)
+
+	return app
 }
@@ -0,0 +1,90 @@
+package app
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gorilla/mux"
+	"hameid.net/cdex/dex/internal/helpers"
+	"hameid.net/cdex/dex/internal/models"
+	"hameid.net/cdex/dex/internal/wrappers"
+)
+
+// How long tickers are served from cache
+const tickerCacheTTL = 5 * time.Second
+
+// tickerCache holds tickers of all markets, computed at most once per TTL
+type tickerCache struct {
+	mu        sync.Mutex
+	tickers   []models.Ticker
+	fetchedAt time.Time
+}
+
+// getTickers returns cached tickers of the markets served by the app
+func (app *App) getTickers() ([]models.Ticker, error) {
+	app.tickers.mu.Lock()
+	defer app.tickers.mu.Unlock()
+
+	if time.Since(app.tickers.fetchedAt) > tickerCacheTTL {
+		tickers, err := models.GetTickers(app.store)
+		if err != nil {
+			return nil, err
+		}
+
+		app.tickers.tickers = tickers
+		app.tickers.fetchedAt = time.Now()
+	}
+
+	available := []models.Ticker{}
+	for _, ticker := range app.tickers.tickers {
+		if app.isMarketAvailable(&ticker.Token.Address, &ticker.Base.Address) {
+			available = append(available, ticker)
+		}
+	}
+
+	return available, nil
+}
+
+func (app *App) getMarketsHandler(w http.ResponseWriter, r *http.Request) {
+	tickers, err := app.getTickers()
+	if err != nil {
+		helpers.RespondWithError(w, r, err)
+		return
+	}
+
+	helpers.RespondWithJSON(w, http.StatusOK, tickers)
+}
+
+func (app *App) getTickerHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	token := common.HexToAddress(strings.TrimPrefix(vars["token"], "0x"))
+	base := common.HexToAddress(strings.TrimPrefix(vars["base"], "0x"))
+
+	if !app.isMarketAvailable(&token, &base) {
+		helpers.RespondWithError(w, r, errMarketNotListed)
+		return
+	}
+
+	tickers, err := app.getTickers()
+	if err != nil {
+		helpers.RespondWithError(w, r, err)
+		return
+	}
+
+	for _, ticker := range tickers {
+		if ticker.Token.Address == token && ticker.Base.Address == base {
+			helpers.RespondWithJSON(w, http.StatusOK, ticker)
+			return
+		}
+	}
+
+	// Listed market without trades
+	helpers.RespondWithJSON(w, http.StatusOK, models.Ticker{
+		Token:     wrappers.WrapAddress(&token),
+		Base:      wrappers.WrapAddress(&base),
+		UpdatedAt: time.Now().Unix(),
+	})
+}
@@ -0,0 +1,17 @@
+package app
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"hameid.net/cdex/dex/internal/metrics"
+)
+
+var rateLimitErrors = prometheus.NewCounter(prometheus.CounterOpts{
+	Namespace: metrics.Namespace,
+	Subsystem: "app",
+	Name:      "rate_limit_errors_total",
+	Help:      "Requests let through because their rate limit could not be checked.",
+})
+
+func init() {
+	prometheus.MustRegister(rateLimitErrors)
+}
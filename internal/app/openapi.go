@@ -0,0 +1,335 @@
+package app
+
+import (
+	"math/big"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"hameid.net/cdex/dex/internal/helpers"
+	"hameid.net/cdex/dex/internal/wrappers"
+)
+
+const (
+	addressPattern = "^0x[0-9A-Fa-f]{40}$"
+	hashPattern    = "^0x[0-9A-Fa-f]{64}$"
+	integerPattern = "^-?[0-9]+$"
+)
+
+type openAPIDocument struct {
+	OpenAPI    string                                  `json:"openapi"`
+	Info       openAPIInfo                             `json:"info"`
+	Paths      map[string]map[string]*openAPIOperation `json:"paths"`
+	Components openAPIComponents                       `json:"components"`
+}
+
+type openAPIInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type openAPIComponents struct {
+	Schemas         map[string]*openAPISchema         `json:"schemas"`
+	SecuritySchemes map[string]*openAPISecurityScheme `json:"securitySchemes"`
+}
+
+type openAPISecurityScheme struct {
+	Type        string `json:"type"`
+	Scheme      string `json:"scheme"`
+	Description string `json:"description,omitempty"`
+}
+
+type openAPIOperation struct {
+	OperationID string                      `json:"operationId"`
+	Summary     string                      `json:"summary"`
+	Tags        []string                    `json:"tags,omitempty"`
+	Parameters  []*openAPIParameter         `json:"parameters,omitempty"`
+	RequestBody *openAPIRequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*openAPIResponse `json:"responses"`
+	Security    []map[string][]string       `json:"security,omitempty"`
+}
+
+type openAPIParameter struct {
+	Name        string         `json:"name"`
+	In          string         `json:"in"`
+	Description string         `json:"description,omitempty"`
+	Required    bool           `json:"required,omitempty"`
+	Schema      *openAPISchema `json:"schema"`
+}
+
+type openAPIRequestBody struct {
+	Required bool                        `json:"required"`
+	Content  map[string]openAPIMediaType `json:"content"`
+}
+
+type openAPIResponse struct {
+	Description string                      `json:"description"`
+	Content     map[string]openAPIMediaType `json:"content,omitempty"`
+}
+
+type openAPIMediaType struct {
+	Schema *openAPISchema `json:"schema"`
+}
+
+type openAPISchema struct {
+	Ref                  string                    `json:"$ref,omitempty"`
+	Type                 string                    `json:"type,omitempty"`
+	Format               string                    `json:"format,omitempty"`
+	Pattern              string                    `json:"pattern,omitempty"`
+	Enum                 []string                  `json:"enum,omitempty"`
+	Minimum              *int64                    `json:"minimum,omitempty"`
+	Maximum              *int64                    `json:"maximum,omitempty"`
+	Items                *openAPISchema            `json:"items,omitempty"`
+	Properties           map[string]*openAPISchema `json:"properties,omitempty"`
+	AdditionalProperties *openAPISchema            `json:"additionalProperties,omitempty"`
+	OneOf                []*openAPISchema          `json:"oneOf,omitempty"`
+	Nullable             bool                      `json:"nullable,omitempty"`
+	Description          string                    `json:"description,omitempty"`
+}
+
+// apiRoute describes an endpoint. Routes, the OpenAPI document and
+// parameter validation are all built from it.
+type apiRoute struct {
+	id      string
+	method  string
+	path    string
+	summary string
+	tag     string
+	handler http.HandlerFunc
+	// Requires a session, see requireSession
+	auth   func(http.HandlerFunc) http.HandlerFunc
+	heavy  bool
+	params []*openAPIParameter
+	body   interface{}
+	// Value of the response type. nil for no content.
+	response interface{}
+	// Response is also available as CSV
+	csv bool
+	// Responds to invalid parameters instead of helpers.RespondWithError
+	invalid func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+// pageOf is the response type of a page of items, see models.Page
+type pageOf struct {
+	item interface{}
+}
+
+// oneOf is the response type of endpoints that return one of the types
+type oneOf []interface{}
+
+// Wrapper types marshalled as JSON strings or numbers
+var openAPIScalarSchemas = map[reflect.Type]openAPISchema{
+	reflect.TypeOf(wrappers.Address{}):   {Type: "string", Pattern: addressPattern},
+	reflect.TypeOf(common.Address{}):     {Type: "string", Pattern: addressPattern},
+	reflect.TypeOf(wrappers.Hash{}):      {Type: "string", Pattern: hashPattern},
+	reflect.TypeOf(common.Hash{}):        {Type: "string", Pattern: hashPattern},
+	reflect.TypeOf(wrappers.BigInt{}):    {Type: "string", Pattern: integerPattern, Description: "Integer in base 10"},
+	reflect.TypeOf(big.Int{}):            {Type: "integer"},
+	reflect.TypeOf(wrappers.Timestamp{}): {Type: "integer", Format: "int64", Description: "Unix time in seconds"},
+	reflect.TypeOf(time.Time{}):          {Type: "string", Format: "date-time"},
+	reflect.TypeOf(hexutil.Bytes{}):      {Type: "string", Pattern: "^0x[0-9a-f]*$"},
+}
+
+func addressParam(name string, in string, required bool, description string) *openAPIParameter {
+	return &openAPIParameter{Name: name, In: in, Required: required || in == "path", Description: description,
+		Schema: &openAPISchema{Type: "string", Pattern: addressPattern}}
+}
+
+func hashParam(name string, description string) *openAPIParameter {
+	return &openAPIParameter{Name: name, In: "path", Required: true, Description: description,
+		Schema: &openAPISchema{Type: "string", Pattern: hashPattern}}
+}
+
+func integerParam(name string, description string) *openAPIParameter {
+	return &openAPIParameter{Name: name, In: "query", Description: description,
+		Schema: &openAPISchema{Type: "integer", Format: "int64"}}
+}
+
+func rangeParam(name string, description string, min int64, max int64) *openAPIParameter {
+	param := integerParam(name, description)
+	param.Schema.Minimum, param.Schema.Maximum = &min, &max
+	return param
+}
+
+func timeParam(name string, description string) *openAPIParameter {
+	return &openAPIParameter{Name: name, In: "query", Description: description + ", unix time in seconds",
+		Schema: &openAPISchema{Type: "integer", Format: "int64"}}
+}
+
+func enumParam(name string, description string, values ...string) *openAPIParameter {
+	return &openAPIParameter{Name: name, In: "query", Description: description,
+		Schema: &openAPISchema{Type: "string", Enum: values}}
+}
+
+func pageParams() []*openAPIParameter {
+	return []*openAPIParameter{
+		integerParam("count", "Number of items in the page, capped by the maximum page size"),
+		{Name: "cursor", In: "query", Description: "next_cursor of the previous page", Schema: &openAPISchema{Type: "string"}},
+	}
+}
+
+// newOpenAPIDocument describes routes
+func newOpenAPIDocument(routes []apiRoute) *openAPIDocument {
+	doc := &openAPIDocument{
+		OpenAPI: "3.0.3",
+		Info:    openAPIInfo{Title: "CDEX API", Version: "1.0.0"},
+		Paths:   make(map[string]map[string]*openAPIOperation),
+		Components: openAPIComponents{
+			Schemas: map[string]*openAPISchema{
+				"Error": {
+					Type: "object",
+					Properties: map[string]*openAPISchema{
+						"code":       {Type: "string", Description: "Stable error code"},
+						"error":      {Type: "string"},
+						"field":      {Type: "string", Description: "Invalid parameter"},
+						"request_id": {Type: "string", Description: "Correlation ID, also in X-Request-ID header"},
+					},
+				},
+			},
+			SecuritySchemes: map[string]*openAPISecurityScheme{
+				"session": {Type: "http", Scheme: "bearer", Description: "Token of POST /auth/login"},
+			},
+		},
+	}
+
+	errorResponse := func(description string) *openAPIResponse {
+		return &openAPIResponse{
+			Description: description,
+			Content:     map[string]openAPIMediaType{"application/json": {Schema: &openAPISchema{Ref: "#/components/schemas/Error"}}},
+		}
+	}
+
+	for _, route := range routes {
+		op := &openAPIOperation{
+			OperationID: route.id,
+			Summary:     route.summary,
+			Parameters:  route.params,
+			Responses: map[string]*openAPIResponse{
+				"429": errorResponse("Rate limit exceeded"),
+				"500": errorResponse("Internal error"),
+			},
+		}
+		if route.tag != "" {
+			op.Tags = []string{route.tag}
+		}
+
+		if route.response == nil {
+			op.Responses["204"] = &openAPIResponse{Description: "No content"}
+		} else {
+			content := map[string]openAPIMediaType{"application/json": {Schema: doc.schemaOf(route.response)}}
+			if route.csv {
+				content["text/csv"] = openAPIMediaType{Schema: &openAPISchema{Type: "string"}}
+			}
+			op.Responses["200"] = &openAPIResponse{Description: "OK", Content: content}
+		}
+
+		if route.body != nil {
+			op.RequestBody = &openAPIRequestBody{
+				Required: true,
+				Content:  map[string]openAPIMediaType{"application/json": {Schema: doc.schemaOf(route.body)}},
+			}
+		}
+		if len(route.params) > 0 || route.body != nil {
+			op.Responses["400"] = errorResponse("Invalid parameter")
+		}
+		if route.auth != nil {
+			op.Security = []map[string][]string{{"session": {}}}
+			op.Responses["401"] = errorResponse("Missing or expired session")
+			op.Responses["403"] = errorResponse("Session is not of the wallet")
+		}
+
+		if doc.Paths[route.path] == nil {
+			doc.Paths[route.path] = make(map[string]*openAPIOperation)
+		}
+		doc.Paths[route.path][strings.ToLower(route.method)] = op
+	}
+
+	return doc
+}
+
+// schemaOf returns schema of the JSON encoding of value. Structs are added
+// to the components of the document.
+func (doc *openAPIDocument) schemaOf(value interface{}) *openAPISchema {
+	switch v := value.(type) {
+	case pageOf:
+		return &openAPISchema{
+			Type: "object",
+			Properties: map[string]*openAPISchema{
+				"items":       {Type: "array", Items: doc.schemaOf(v.item)},
+				"next_cursor": {Type: "string", Nullable: true, Description: "Cursor of the next page, null on last page"},
+			},
+		}
+	case oneOf:
+		schema := &openAPISchema{}
+		for _, item := range v {
+			schema.OneOf = append(schema.OneOf, doc.schemaOf(item))
+		}
+		return schema
+	}
+
+	return doc.schemaOfType(reflect.TypeOf(value))
+}
+
+func (doc *openAPIDocument) schemaOfType(t reflect.Type) *openAPISchema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if schema, ok := openAPIScalarSchemas[t]; ok {
+		return &schema
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &openAPISchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &openAPISchema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &openAPISchema{Type: "number"}
+	case reflect.String:
+		return &openAPISchema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		return &openAPISchema{Type: "array", Items: doc.schemaOfType(t.Elem())}
+	case reflect.Map:
+		return &openAPISchema{Type: "object", AdditionalProperties: doc.schemaOfType(t.Elem())}
+	case reflect.Struct:
+		return doc.structSchema(t)
+	}
+
+	return &openAPISchema{}
+}
+
+func (doc *openAPIDocument) structSchema(t reflect.Type) *openAPISchema {
+	name := strings.ToUpper(t.Name()[:1]) + t.Name()[1:]
+	ref := &openAPISchema{Ref: "#/components/schemas/" + name}
+
+	if _, ok := doc.Components.Schemas[name]; ok {
+		return ref
+	}
+
+	schema := &openAPISchema{Type: "object", Properties: make(map[string]*openAPISchema)}
+	doc.Components.Schemas[name] = schema
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := strings.Split(field.Tag.Get("json"), ",")[0]
+		if field.PkgPath != "" || tag == "-" {
+			continue
+		}
+		if tag == "" {
+			tag = field.Name
+		}
+
+		schema.Properties[tag] = doc.schemaOfType(field.Type)
+	}
+
+	return ref
+}
+
+func (app *App) getOpenAPIHandler(w http.ResponseWriter, r *http.Request) {
+	helpers.RespondWithJSON(w, http.StatusOK, app.openAPI)
+}
@@ -0,0 +1,72 @@
+package app
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestOpenAPIDocument(t *testing.T) {
+	app := &App{}
+	routes := app.apiRoutes()
+	doc := newOpenAPIDocument(routes)
+
+	for _, route := range routes {
+		op := doc.Paths[route.path][strings.ToLower(route.method)]
+		if op == nil {
+			t.Fatalf("%s %s is not documented", route.method, route.path)
+		}
+
+		for _, param := range route.params {
+			if param.In == "path" && !strings.Contains(route.path, "{"+param.Name+"}") {
+				t.Errorf("%s has no path parameter %s", route.path, param.Name)
+			}
+		}
+	}
+
+	for _, route := range routes {
+		if route.id == "getWalletBalances" {
+			if got := muxPath(route); got != "/wallets/{address:0x[0-9A-Fa-f]{40}}" {
+				t.Errorf("unexpected mux path %s", got)
+			}
+		}
+	}
+
+	order := doc.Components.Schemas["Order"]
+	if order == nil || order.Properties["order_hash"] == nil || order.Properties["order_hash"].Pattern != hashPattern {
+		t.Error("Order schema does not describe order_hash")
+	}
+
+	if _, err := json.Marshal(doc); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestValidateParam(t *testing.T) {
+	tests := []struct {
+		param   *openAPIParameter
+		raw     string
+		message string
+	}{
+		{addressParam("creator", "query", false, ""), "", ""},
+		{addressParam("creator", "query", false, ""), "0x0000000000000000000000000000000000000001", ""},
+		{addressParam("creator", "query", false, ""), "0x01", "Invalid value for `creator` parameter"},
+		{addressParam("token", "query", true, ""), "", "Missing `token` parameter"},
+		{rangeParam("depth", "", 1, 500), "501", "Value of `depth` parameter must be between 1 and 500"},
+		{rangeParam("depth", "", 1, 500), "ten", "Invalid value for `depth` parameter"},
+		{rangeParam("depth", "", 1, 500), "10", ""},
+		{enumParam("format", "", "json", "csv"), "xml", "Value of `format` parameter must be one of json, csv"},
+		{enumParam("format", "", "json", "csv"), "csv", ""},
+	}
+
+	for _, test := range tests {
+		err := validateParam(test.param, test.raw)
+
+		switch {
+		case test.message == "" && err != nil:
+			t.Errorf("%s=%q: unexpected error %s", test.param.Name, test.raw, err)
+		case test.message != "" && (err == nil || err.Message != test.message || err.Field != test.param.Name):
+			t.Errorf("%s=%q: expected %q, got %v", test.param.Name, test.raw, test.message, err)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis"
+	"hameid.net/cdex/dex/internal/config"
+	"hameid.net/cdex/dex/internal/helpers"
+)
+
+const rateLimitKeyPrefix = "ratelimit:"
+
+var errTooManyRequests = helpers.NewError(http.StatusTooManyRequests, helpers.CodeRateLimited, "Too many requests")
+
+type rateLimitContextKey struct{}
+
+// rateLimits of clients, see config.Config.App.RateLimits
+type rateLimits struct {
+	ip      config.RateLimitConfig
+	address config.RateLimitConfig
+	heavy   config.RateLimitConfig
+	// Proxies that append to X-Forwarded-For
+	trustedProxies int
+}
+
+// Token bucket kept in a redis hash. Takes a token if there is one and
+// returns whether it was taken, tokens left and milliseconds until the next
+// token and until the bucket is full.
+//
+// KEYS[1] bucket, ARGV[1] tokens per millisecond, ARGV[2] burst,
+// ARGV[3] current time in milliseconds
+const takeTokenLua = `
+	local rate = tonumber(ARGV[1])
+	local burst = tonumber(ARGV[2])
+	local now = tonumber(ARGV[3])
+
+	local bucket = redis.call('HMGET', KEYS[1], 'tokens', 'at')
+	local tokens = tonumber(bucket[1]) or burst
+	local at = tonumber(bucket[2]) or now
+	tokens = math.min(burst, tokens + math.max(0, now - at) * rate)
+
+	local taken = 0
+	if tokens >= 1 then
+		tokens = tokens - 1
+		taken = 1
+	end
+
+	local full = math.ceil((burst - tokens) / rate)
+	redis.call('HMSET', KEYS[1], 'tokens', tostring(tokens), 'at', now)
+	redis.call('PEXPIRE', KEYS[1], full + 1000)
+
+	return {taken, math.floor(tokens), math.ceil(math.max(0, 1 - tokens) / rate), full}
+`
+
+var takeTokenScript = redis.NewScript(takeTokenLua)
+
+// Clock of token buckets
+var rateLimitNow = time.Now
+
+// bucketState is the result of taking a token from a bucket
+type bucketState struct {
+	limit      uint64
+	taken      bool
+	remaining  int64
+	retryAfter time.Duration
+	reset      time.Duration
+}
+
+// takeToken takes a token from the bucket of the client under the limit
+func (app *App) takeToken(key string, limit config.RateLimitConfig) (*bucketState, error) {
+	rate := float64(limit.Rate) / float64(time.Minute/time.Millisecond)
+	now := rateLimitNow().UnixNano() / int64(time.Millisecond)
+
+	result, err := takeTokenScript.Run(app.redisClient, []string{rateLimitKeyPrefix + key}, rate, limit.Burst, now).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	values, ok := result.([]interface{})
+	if !ok || len(values) != 4 {
+		return nil, fmt.Errorf("Unexpected rate limit result %v", result)
+	}
+
+	state := &bucketState{limit: limit.Burst}
+	state.taken = values[0].(int64) == 1
+	state.remaining = values[1].(int64)
+	state.retryAfter = time.Duration(values[2].(int64)) * time.Millisecond
+	state.reset = time.Duration(values[3].(int64)) * time.Millisecond
+
+	return state, nil
+}
+
+// rateLimit limits requests of each client, by wallet of its session or by
+// IP without one. Limits are skipped if redis cannot be reached, so that
+// the API stays up.
+func (app *App) rateLimit(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limits := app.getRateLimits()
+
+		client, limit := "ip:"+clientIP(r, limits.trustedProxies), limits.ip
+		if address, err := app.sessions.Verify(bearerToken(r)); err == nil {
+			client, limit = "address:"+strings.ToLower(address.Hex()), limits.address
+		}
+
+		if !app.limitRequest(w, r, client, limit) {
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), rateLimitContextKey{}, client)))
+	})
+}
+
+// limitHeavy additionally limits each client on endpoints that are costly to
+// serve
+func (app *App) limitHeavy(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		client, _ := r.Context().Value(rateLimitContextKey{}).(string)
+
+		if !app.limitRequest(w, r, "heavy:"+client, app.getRateLimits().heavy) {
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+// limitRequest takes a token of the client, sets rate limit headers and
+// responds 429 if there is none left
+func (app *App) limitRequest(w http.ResponseWriter, r *http.Request, key string, limit config.RateLimitConfig) bool {
+	if limit.Rate == 0 {
+		return true
+	}
+
+	state, err := app.takeToken(key, limit)
+	if err != nil {
+		// Fail open, an unavailable redis must not take the API down
+		log.Printf("ERROR request %s %s %s: rate limit not checked: %s", helpers.GetRequestID(r), r.Method, r.URL.Path, err)
+		rateLimitErrors.Inc()
+		return true
+	}
+
+	// Heavy endpoints are limited twice, headers tell the stricter limit
+	header := w.Header()
+	remaining, err := strconv.ParseInt(header.Get("X-RateLimit-Remaining"), 10, 64)
+	if err != nil || state.remaining <= remaining || !state.taken {
+		header.Set("X-RateLimit-Limit", strconv.FormatUint(state.limit, 10))
+		header.Set("X-RateLimit-Remaining", strconv.FormatInt(state.remaining, 10))
+		header.Set("X-RateLimit-Reset", strconv.FormatInt(durationSeconds(state.reset), 10))
+	}
+
+	if !state.taken {
+		header.Set("Retry-After", strconv.FormatInt(durationSeconds(state.retryAfter), 10))
+		helpers.RespondWithError(w, r, errTooManyRequests)
+		return false
+	}
+
+	return true
+}
+
+// clientIP returns IP of the client. Behind trusted proxies it is the
+// address of X-Forwarded-For appended by the outermost one, addresses before
+// it are set by the client.
+func clientIP(r *http.Request, trustedProxies int) string {
+	if trustedProxies > 0 {
+		var forwarded []string
+		for _, header := range r.Header["X-Forwarded-For"] {
+			for _, address := range strings.Split(header, ",") {
+				forwarded = append(forwarded, strings.TrimSpace(address))
+			}
+		}
+
+		// Fewer addresses than proxies means the request skipped some
+		if len(forwarded) >= trustedProxies {
+			return forwarded[len(forwarded)-trustedProxies]
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+func durationSeconds(d time.Duration) int64 {
+	return int64(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,205 @@
+package app
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis"
+	"hameid.net/cdex/dex/internal/config"
+	"hameid.net/cdex/dex/internal/helpers"
+	"hameid.net/cdex/dex/internal/redistest"
+)
+
+func TestLimitRequestFailsOpenWithRequestID(t *testing.T) {
+	// Nothing listens on port 1
+	app := &App{redisClient: redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", DialTimeout: time.Second})}
+	defer app.redisClient.Close()
+
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	var allowed bool
+	handler := helpers.RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		allowed = app.limitRequest(w, r, "ip:127.0.0.1", config.RateLimitConfig{Rate: 60, Burst: 10})
+	}))
+
+	r := httptest.NewRequest("GET", "/orders", nil)
+	r.Header.Set(helpers.RequestIDHeader, "test-request")
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+
+	if !allowed {
+		t.Error("request rejected when rate limit could not be checked")
+	}
+	if !strings.Contains(logs.String(), "ERROR request test-request GET /orders: rate limit not checked") {
+		t.Errorf("rate limit error not logged with request ID: %q", logs.String())
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	cases := []struct {
+		forwarded      []string
+		trustedProxies int
+		ip             string
+	}{
+		{nil, 0, "10.0.0.1"},
+		{[]string{"1.1.1.1"}, 0, "10.0.0.1"},
+		{nil, 1, "10.0.0.1"},
+		{[]string{"1.1.1.1"}, 1, "1.1.1.1"},
+		// Entries before the one appended by the proxy are set by the client
+		{[]string{"6.6.6.6, 1.1.1.1"}, 1, "1.1.1.1"},
+		{[]string{"6.6.6.6", "1.1.1.1"}, 1, "1.1.1.1"},
+		{[]string{"6.6.6.6, 1.1.1.1, 2.2.2.2"}, 2, "1.1.1.1"},
+		// Request did not come through every proxy
+		{[]string{"1.1.1.1"}, 2, "10.0.0.1"},
+	}
+
+	for i, c := range cases {
+		r := httptest.NewRequest("GET", "/orders", nil)
+		r.RemoteAddr = "10.0.0.1:4321"
+		for _, forwarded := range c.forwarded {
+			r.Header.Add("X-Forwarded-For", forwarded)
+		}
+
+		if ip := clientIP(r, c.trustedProxies); ip != c.ip {
+			t.Errorf("case %d: got %s, want %s", i, ip, c.ip)
+		}
+	}
+}
+
+func TestLimitRequestRespondsTooManyRequests(t *testing.T) {
+	server, err := redistest.NewServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+
+	// Bucket is empty, next token in 1.5s and full in 3s
+	server.HandleScript(takeTokenLua, func(keys []string, args []string) interface{} {
+		return []interface{}{int64(0), int64(0), int64(1500), int64(3000)}
+	})
+
+	app := &App{redisClient: redis.NewClient(&redis.Options{Addr: server.Addr()})}
+	defer app.redisClient.Close()
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/orders", nil)
+	if app.limitRequest(w, r, "ip:127.0.0.1", config.RateLimitConfig{Rate: 60, Burst: 10}) {
+		t.Fatal("request allowed without tokens")
+	}
+
+	if w.Code != http.StatusTooManyRequests {
+		t.Errorf("got status %d, want %d", w.Code, http.StatusTooManyRequests)
+	}
+
+	headers := map[string]string{
+		"X-RateLimit-Limit":     "10",
+		"X-RateLimit-Remaining": "0",
+		"X-RateLimit-Reset":     "3",
+		"Retry-After":           "2",
+	}
+	for name, value := range headers {
+		if got := w.Header().Get(name); got != value {
+			t.Errorf("got %s %q, want %q", name, got, value)
+		}
+	}
+}
+
+func TestLimitRequestKeepsStricterHeaders(t *testing.T) {
+	server, err := redistest.NewServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+
+	// Heavy bucket has fewer tokens left than the client bucket
+	server.HandleScript(takeTokenLua, func(keys []string, args []string) interface{} {
+		if strings.HasPrefix(keys[0], rateLimitKeyPrefix+"heavy:") {
+			return []interface{}{int64(1), int64(2), int64(0), int64(20000)}
+		}
+		return []interface{}{int64(1), int64(50), int64(0), int64(5000)}
+	})
+
+	app := &App{redisClient: redis.NewClient(&redis.Options{Addr: server.Addr()})}
+	defer app.redisClient.Close()
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/trades", nil)
+	for _, key := range []string{"ip:127.0.0.1", "heavy:ip:127.0.0.1"} {
+		if !app.limitRequest(w, r, key, config.RateLimitConfig{Rate: 60, Burst: 100}) {
+			t.Fatalf("request rejected by %s", key)
+		}
+	}
+
+	if remaining := w.Header().Get("X-RateLimit-Remaining"); remaining != "2" {
+		t.Errorf("got remaining %s, want the heavy limit", remaining)
+	}
+	if reset := w.Header().Get("X-RateLimit-Reset"); reset != "20" {
+		t.Errorf("got reset %s, want the heavy limit", reset)
+	}
+}
+
+// TestTakeTokenScript runs the token bucket script on the redis server at
+// CDEX_TEST_REDIS, scripts cannot run without one
+func TestTakeTokenScript(t *testing.T) {
+	addr := os.Getenv("CDEX_TEST_REDIS")
+	if addr == "" {
+		t.Skip("CDEX_TEST_REDIS is not set")
+	}
+
+	app := &App{redisClient: redis.NewClient(&redis.Options{Addr: addr})}
+	defer app.redisClient.Close()
+
+	now := time.Now()
+	rateLimitNow = func() time.Time { return now }
+	defer func() { rateLimitNow = time.Now }()
+
+	key := fmt.Sprintf("test:%d", now.UnixNano())
+	defer app.redisClient.Del(rateLimitKeyPrefix + key)
+
+	// A token per second, 3 at once
+	limit := config.RateLimitConfig{Rate: 60, Burst: 3}
+
+	take := func() *bucketState {
+		state, err := app.takeToken(key, limit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return state
+	}
+
+	// Burst
+	for remaining := int64(2); remaining >= 0; remaining-- {
+		state := take()
+		if !state.taken || state.remaining != remaining {
+			t.Fatalf("got taken=%t remaining=%d, want a token with %d left", state.taken, state.remaining, remaining)
+		}
+	}
+
+	state := take()
+	if state.taken {
+		t.Fatal("token taken from an empty bucket")
+	}
+	if state.retryAfter != time.Second || state.reset != 3*time.Second {
+		t.Errorf("got retry after %s and reset %s, want 1s and 3s", state.retryAfter, state.reset)
+	}
+
+	// Refill
+	now = now.Add(time.Second)
+	if state := take(); !state.taken || state.remaining != 0 {
+		t.Errorf("got taken=%t remaining=%d after a second, want the refilled token", state.taken, state.remaining)
+	}
+
+	// Never over the burst
+	now = now.Add(time.Hour)
+	if state := take(); !state.taken || state.remaining != 2 {
+		t.Errorf("got taken=%t remaining=%d after an hour, want a full bucket", state.taken, state.remaining)
+	}
+}
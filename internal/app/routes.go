@@ -2,10 +2,11 @@ package app
 
 import (
 	"database/sql"
-	"errors"
 	"fmt"
+	"log"
 	"math/big"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -14,29 +15,232 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/gorilla/mux"
+	"hameid.net/cdex/dex/internal/auth"
 	"hameid.net/cdex/dex/internal/helpers"
+	"hameid.net/cdex/dex/internal/metrics"
 	"hameid.net/cdex/dex/internal/wrappers"
 )
 
 // InitializeRoutes initializes all modules
 func (app *App) InitializeRoutes() {
+	routes := app.apiRoutes()
+	app.openAPI = newOpenAPIDocument(routes)
+
 	app.router.StrictSlash(true)
-	app.router.HandleFunc("/wallets/{address:0x[0-9A-Za-z]{40}}", app.getWalletBalancesHandler).Methods("GET")
-	app.router.HandleFunc("/wallets/{address:0x[0-9A-Za-z]{40}}/{token:0x[0-9A-Za-z]{40}}", app.getWalletBalanceByTokenHandler).Methods("GET")
-	app.router.HandleFunc("/wallets/{address:0x[0-9A-Za-z]{40}}/withdraw_requests", app.getUnprocessedWithdrawRequests).Methods("GET")
-	app.router.HandleFunc("/withdraw_requests/{tx_hash:0x[0-9A-Za-z]{64}}/signs", app.getSignsOfWithdrawRequests).Methods("GET")
-	app.router.HandleFunc("/orders", app.getOrdersHandler).Methods("GET")
-	app.router.HandleFunc("/orders/{hash:0x[0-9A-Za-z]{64}}", app.getOrderByHashHandler).Methods("GET")
-	app.router.HandleFunc("/trades", app.getTradesHandler).Methods("GET")
-	app.router.HandleFunc("/trades/history", app.getTradeHistoryHandler).Methods("GET")
-	app.router.HandleFunc("/trades/ohlc", app.getOHLCDataHandler).Methods("GET")
-	app.router.HandleFunc("/orderbook", app.getOrderbookHandler).Methods("GET")
+	app.router.Use(metrics.Instrument, app.rateLimit)
+	for _, route := range routes {
+		handler := validateParams(route, route.handler)
+		if route.heavy {
+			handler = app.limitHeavy(handler)
+		}
+		if route.auth != nil {
+			handler = route.auth(handler)
+		}
+
+		app.router.HandleFunc(muxPath(route), handler).Methods(route.method)
+	}
+	app.router.HandleFunc("/openapi.json", app.getOpenAPIHandler).Methods("GET")
+
+	schema, err := app.newGraphQLSchema()
+	if err != nil {
+		log.Fatal(err)
+	}
+	app.graphQLSchema = schema
+	app.router.HandleFunc("/graphql", app.limitHeavy(app.graphQLHandler)).Methods("GET", "POST")
 	app.router.NotFoundHandler = notFoundHandler()
 }
 
+// apiRoutes returns all endpoints of the API
+func (app *App) apiRoutes() []apiRoute {
+	market := func() []*openAPIParameter {
+		return []*openAPIParameter{
+			addressParam("token", "query", true, "Traded token"),
+			addressParam("base", "query", true, "Base token prices are in"),
+		}
+	}
+
+	resolutions := make([]string, 0, len(models.OHLCResolutions)+len(udfResolutions))
+	for name := range models.OHLCResolutions {
+		resolutions = append(resolutions, name)
+	}
+	for name := range udfResolutions {
+		resolutions = append(resolutions, name)
+	}
+	sort.Strings(resolutions)
+
+	return []apiRoute{
+		{
+			id: "getLoginNonce", method: "POST", path: "/auth/nonce", tag: "auth",
+			summary: "Nonce and message for the wallet to sign with personal_sign",
+			handler: app.getLoginNonceHandler,
+			body:    nonceRequest{}, response: nonceResponse{},
+		},
+		{
+			id: "login", method: "POST", path: "/auth/login", tag: "auth",
+			summary: "Session of the wallet that signed its login message",
+			handler: app.loginHandler,
+			body:    loginRequest{}, response: auth.Session{},
+		},
+		{
+			id: "logout", method: "POST", path: "/auth/logout", tag: "auth",
+			summary: "Ends the session",
+			handler: app.logoutHandler,
+		},
+		{
+			id: "getWalletBalances", method: "GET", path: "/wallets/{address}", tag: "wallets",
+			summary: "Token balances of the wallet",
+			handler: app.getWalletBalancesHandler, auth: app.requireSession,
+			params:   []*openAPIParameter{addressParam("address", "path", true, "Wallet")},
+			response: []models.Wallet{},
+		},
+		{
+			id: "getWalletBalance", method: "GET", path: "/wallets/{address}/{token}", tag: "wallets",
+			summary: "Balance of a token of the wallet",
+			handler: app.getWalletBalanceByTokenHandler, auth: app.requireSession,
+			params: []*openAPIParameter{
+				addressParam("address", "path", true, "Wallet"),
+				addressParam("token", "path", true, "Token"),
+			},
+			response: models.Wallet{},
+		},
+		{
+			id: "getStatement", method: "GET", path: "/wallets/{address}/statement", tag: "wallets",
+			summary: "Balance changes of the wallet, oldest first",
+			handler: app.getStatementHandler, auth: app.requireSession, heavy: true,
+			params: []*openAPIParameter{
+				addressParam("address", "path", true, "Wallet"),
+				timeParam("from", "Start of the statement, defaults to the beginning"),
+				timeParam("to", "End of the statement, defaults to now"),
+				enumParam("format", "Response format, defaults to json", "json", "csv"),
+			},
+			response: []models.StatementEntry{}, csv: true,
+		},
+		{
+			id: "getWithdrawRequests", method: "GET", path: "/wallets/{address}/withdraw_requests", tag: "withdrawals",
+			summary: "Withdraw requests of the wallet that are not processed yet",
+			handler: app.getUnprocessedWithdrawRequests, auth: app.requireSession,
+			params:   []*openAPIParameter{addressParam("address", "path", true, "Wallet")},
+			response: []models.WithdrawMeta{},
+		},
+		{
+			id: "getWithdrawSigns", method: "GET", path: "/withdraw_requests/{tx_hash}/signs", tag: "withdrawals",
+			summary: "Authority signatures of the withdraw request",
+			handler: app.getSignsOfWithdrawRequests, auth: app.requireWithdrawRecipient,
+			params:   []*openAPIParameter{hashParam("tx_hash", "Transaction of the withdraw request")},
+			response: []models.WithdrawSign{},
+		},
+		{
+			id: "getWithdrawBundle", method: "GET", path: "/withdraw_requests/{tx_hash}/bundle", tag: "withdrawals",
+			summary: "Arguments of HomeBridge.withdraw for the withdraw request",
+			handler: app.getWithdrawBundle, auth: app.requireWithdrawRecipient,
+			params:   []*openAPIParameter{hashParam("tx_hash", "Transaction of the withdraw request")},
+			response: models.WithdrawBundle{},
+		},
+		{
+			id: "getOrders", method: "GET", path: "/orders", tag: "orders",
+			summary: "Orders, newest first",
+			handler: app.getOrdersHandler,
+			params: append([]*openAPIParameter{
+				timeParam("before", "Orders created before, defaults to now"),
+				rangeParam("side", "0 for buy orders, 1 for sell orders", 0, 1),
+				rangeParam("status", "0 for open orders, 1 for closed orders", 0, 1),
+				addressParam("token", "query", false, "Traded token"),
+				addressParam("base", "query", false, "Base token"),
+				addressParam("creator", "query", false, "Wallet that placed the orders"),
+			}, pageParams()...),
+			response: pageOf{models.Order{}},
+		},
+		{
+			id: "getOrder", method: "GET", path: "/orders/{hash}", tag: "orders",
+			summary:  "Order by hash",
+			handler:  app.getOrderByHashHandler,
+			params:   []*openAPIParameter{hashParam("hash", "Order hash")},
+			response: models.Order{},
+		},
+		{
+			id: "getOrderFills", method: "GET", path: "/orders/{hash}/fills", tag: "orders",
+			summary:  "Trades that filled the order",
+			handler:  app.getOrderFillsHandler,
+			params:   []*openAPIParameter{hashParam("hash", "Order hash")},
+			response: models.OrderFillsResponse{},
+		},
+		{
+			id: "getUserTrades", method: "GET", path: "/trades", tag: "trades",
+			summary: "Trades of a wallet in the market, newest first",
+			handler: app.getTradesHandler,
+			params: append(append(market(),
+				addressParam("user", "query", true, "Wallet"),
+			), pageParams()...),
+			response: pageOf{models.UserTradeResponse{}},
+		},
+		{
+			id: "getTradeHistory", method: "GET", path: "/trades/history", tag: "trades",
+			summary:  "Trades of the market, newest first",
+			handler:  app.getTradeHistoryHandler,
+			params:   append(market(), pageParams()...),
+			response: pageOf{models.TradeHistoryResponse{}},
+		},
+		{
+			id: "getOHLC", method: "GET", path: "/trades/ohlc", tag: "trades",
+			summary: "Candles of the market",
+			handler: app.getOHLCDataHandler, heavy: true,
+			params: append(market(),
+				enumParam("resolution", "Candle width, defaults to 5m", resolutions...),
+				timeParam("from", "Start of the range, defaults to a month before to"),
+				timeParam("to", "End of the range, defaults to now"),
+				enumParam("format", "udf for the TradingView UDF history format", "udf"),
+			),
+			response: oneOf{[]models.OHLCResponse{}, models.UDFHistoryResponse{}},
+			invalid:  respondWithOHLCError,
+		},
+		{
+			id: "getOrderbook", method: "GET", path: "/orderbook", tag: "markets",
+			summary: "Price levels of open orders of the market",
+			handler: app.getOrderbookHandler, heavy: true,
+			params: append(market(),
+				rangeParam("depth", "Number of price levels on each side", 1, models.MaxOrderbookDepth),
+				&openAPIParameter{Name: "tick", In: "query", Description: "Price step to group levels by",
+					Schema: &openAPISchema{Type: "string", Pattern: "^[1-9][0-9]*$"}},
+				rangeParam("precision", "Number of trailing price digits to group levels by, if tick is not set", 0, 77),
+				rangeParam("level", "2 for price levels, 3 to include orders of each level", 2, 3),
+			),
+			response: models.OrderbookResponse{},
+		},
+		{
+			id: "getMarkets", method: "GET", path: "/markets", tag: "markets",
+			summary:  "Tickers of all listed markets",
+			handler:  app.getMarketsHandler,
+			response: []models.Ticker{},
+		},
+		{
+			id: "getTicker", method: "GET", path: "/markets/{token}/{base}/ticker", tag: "markets",
+			summary: "Ticker of the market",
+			handler: app.getTickerHandler,
+			params: []*openAPIParameter{
+				addressParam("token", "path", true, "Traded token"),
+				addressParam("base", "path", true, "Base token"),
+			},
+			response: models.Ticker{},
+		},
+	}
+}
+
+// muxPath returns path of the route with patterns of its path parameters
+func muxPath(route apiRoute) string {
+	path := route.path
+	for _, param := range route.params {
+		if param.In == "path" {
+			pattern := strings.TrimSuffix(strings.TrimPrefix(param.Schema.Pattern, "^"), "$")
+			path = strings.Replace(path, "{"+param.Name+"}", "{"+param.Name+":"+pattern+"}", 1)
+		}
+	}
+
+	return path
+}
+
 func notFoundHandler() http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		helpers.RespondWithError(w, http.StatusBadRequest, "Invalid API endpoint")
+		helpers.RespondWithError(w, r, errInvalidEndpoint)
 	})
 }
 
@@ -46,7 +250,7 @@ func (app *App) getWalletBalancesHandler(w http.ResponseWriter, r *http.Request)
 	wallets, err := models.GetTokenBalancesForWallet(app.store, wrappers.WrapAddress(&unwrappedAddress))
 
 	if err != nil {
-		helpers.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		helpers.RespondWithError(w, r, err)
 		return
 	}
 
@@ -73,7 +277,7 @@ func (app *App) getWalletBalanceByTokenHandler(w http.ResponseWriter, r *http.Re
 		wallet.EscrowBalance = wrappers.WrapBigInt(big.NewInt(0))
 		helpers.RespondWithJSON(w, http.StatusOK, wallet)
 	default:
-		helpers.RespondWithError(w, http.StatusInternalServerError, "internal error")
+		helpers.RespondWithError(w, r, err)
 	}
 }
 
@@ -88,7 +292,7 @@ func (app *App) getUnprocessedWithdrawRequests(w http.ResponseWriter, r *http.Re
 	case sql.ErrNoRows:
 		helpers.RespondWithJSON(w, http.StatusOK, requests)
 	default:
-		helpers.RespondWithError(w, http.StatusInternalServerError, "internal error")
+		helpers.RespondWithError(w, r, err)
 	}
 }
 
@@ -102,11 +306,30 @@ func (app *App) getSignsOfWithdrawRequests(w http.ResponseWriter, r *http.Reques
 
 	switch err {
 	case nil:
+		models.VerifyWithdrawSigns(signs, app.networks.Authorities)
 		helpers.RespondWithJSON(w, http.StatusOK, signs)
 	case sql.ErrNoRows:
 		helpers.RespondWithJSON(w, http.StatusOK, signs)
 	default:
-		helpers.RespondWithError(w, http.StatusInternalServerError, "internal error")
+		helpers.RespondWithError(w, r, err)
+	}
+}
+
+func (app *App) getWithdrawBundle(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	unwrappedTxHash := common.HexToHash(strings.TrimPrefix(vars["tx_hash"], "0x"))
+	bundle, err := models.GetWithdrawBundle(
+		app.store,
+		wrappers.WrapHash(&unwrappedTxHash),
+		app.networks.Authorities,
+		app.networks.Bridge.RequiredSignatures,
+	)
+
+	switch err {
+	case nil:
+		helpers.RespondWithJSON(w, http.StatusOK, bundle)
+	default:
+		helpers.RespondWithError(w, r, err)
 	}
 }
 
@@ -117,17 +340,28 @@ func (app *App) getOrdersHandler(w http.ResponseWriter, r *http.Request) {
 	err := getOrderParamsFromRequest(r, &params)
 
 	if err != nil {
-		helpers.RespondWithError(w, http.StatusBadRequest, err.Error())
+		helpers.RespondWithError(w, r, err)
 		return
 	}
 
+	count, cursor, err := app.getPageFromRequest(r, 1)
+	if err != nil {
+		helpers.RespondWithError(w, r, err)
+		return
+	}
+
+	params["count"] = count
+	if cursor != nil {
+		params["cursor"] = cursor
+	}
+
 	orders, err := models.GetOrders(app.store, &params)
 
 	switch err {
 	case nil:
 		helpers.RespondWithJSON(w, http.StatusOK, orders)
 	default:
-		helpers.RespondWithJSON(w, http.StatusInternalServerError, "internal error")
+		helpers.RespondWithError(w, r, err)
 	}
 }
 
@@ -138,7 +372,14 @@ func (app *App) getOrderbookHandler(w http.ResponseWriter, r *http.Request) {
 	err := getOrderbookParamsFromRequest(r, &params)
 
 	if err != nil {
-		helpers.RespondWithError(w, http.StatusBadRequest, err.Error())
+		helpers.RespondWithError(w, r, err)
+		return
+	}
+
+	token := common.HexToAddress(params["token"].(string))
+	base := common.HexToAddress(params["base"].(string))
+	if !app.isMarketAvailable(&token, &base) {
+		helpers.RespondWithError(w, r, errMarketNotListed)
 		return
 	}
 
@@ -148,83 +389,137 @@ func (app *App) getOrderbookHandler(w http.ResponseWriter, r *http.Request) {
 	case nil:
 		helpers.RespondWithJSON(w, http.StatusOK, resp)
 	default:
-		helpers.RespondWithJSON(w, http.StatusInternalServerError, "internal error")
+		helpers.RespondWithError(w, r, err)
 	}
 }
 
 func (app *App) getTradesHandler(w http.ResponseWriter, r *http.Request) {
 	token, err := helpers.GetAddressQueryParam(r, "token")
 	if err != nil {
-		helpers.RespondWithError(w, http.StatusBadRequest, fmt.Sprintf(err.Error(), "token"))
+		helpers.RespondWithError(w, r, err)
 		return
 	}
 
 	base, err := helpers.GetAddressQueryParam(r, "base")
 	if err != nil {
-		helpers.RespondWithError(w, http.StatusBadRequest, fmt.Sprintf(err.Error(), "base"))
+		helpers.RespondWithError(w, r, err)
+		return
+	}
+
+	if !app.isMarketAvailable(token, base) {
+		helpers.RespondWithError(w, r, errMarketNotListed)
 		return
 	}
 
 	user, err := helpers.GetAddressQueryParam(r, "user")
 	if err != nil {
-		helpers.RespondWithError(w, http.StatusBadRequest, fmt.Sprintf(err.Error(), "user"))
+		helpers.RespondWithError(w, r, err)
+		return
+	}
+
+	count, cursor, err := app.getPageFromRequest(r, 2)
+	if err != nil {
+		helpers.RespondWithError(w, r, err)
 		return
 	}
 
-	trades, err := models.GetTradesOfUser(app.store, token, base, user)
+	trades, err := models.GetTradesOfUser(app.store, token, base, user, count, cursor)
 
 	switch err {
 	case nil:
 		helpers.RespondWithJSON(w, http.StatusOK, trades)
 	default:
-		helpers.RespondWithJSON(w, http.StatusInternalServerError, "internal error")
+		helpers.RespondWithError(w, r, err)
 	}
 }
 
 func (app *App) getOHLCDataHandler(w http.ResponseWriter, r *http.Request) {
+	respondWithError := func(err error) {
+		respondWithOHLCError(w, r, err)
+	}
+
 	token, err := helpers.GetAddressQueryParam(r, "token")
 	if err != nil {
-		helpers.RespondWithError(w, http.StatusBadRequest, fmt.Sprintf(err.Error(), "token"))
+		respondWithError(err)
 		return
 	}
 
 	base, err := helpers.GetAddressQueryParam(r, "base")
 	if err != nil {
-		helpers.RespondWithError(w, http.StatusBadRequest, fmt.Sprintf(err.Error(), "base"))
+		respondWithError(err)
+		return
+	}
+
+	if !app.isMarketAvailable(token, base) {
+		respondWithError(errMarketNotListed)
 		return
 	}
 
-	trades, err := models.GetOHLCData(app.store, token, base)
+	resolution, from, to, err := getOHLCParamsFromRequest(r)
+	if err != nil {
+		respondWithError(err)
+		return
+	}
+
+	candles, err := models.GetOHLCData(app.store, token, base, resolution, from, to)
 
 	switch err {
 	case nil:
-		helpers.RespondWithJSON(w, http.StatusOK, trades)
+		if r.FormValue("format") == "udf" {
+			helpers.RespondWithJSON(w, http.StatusOK, models.NewUDFHistoryResponse(candles))
+			return
+		}
+		helpers.RespondWithJSON(w, http.StatusOK, candles)
+	case models.ErrTooManyOHLCBuckets, models.ErrInvalidTimeRange:
+		respondWithError(helpers.InvalidParam("from", err.Error()))
+	case models.ErrUnknownResolution:
+		respondWithError(helpers.InvalidParam("resolution", err.Error()))
 	default:
-		helpers.RespondWithJSON(w, http.StatusInternalServerError, "internal error")
+		respondWithError(err)
+	}
+}
+
+// respondWithOHLCError responds in UDF format if requested
+func respondWithOHLCError(w http.ResponseWriter, r *http.Request, err error) {
+	if r.FormValue("format") == "udf" {
+		apiErr := helpers.ToAPIError(r, err)
+		helpers.RespondWithJSON(w, apiErr.Status, &models.UDFHistoryResponse{Status: "error", Error: apiErr.Message})
+		return
 	}
+	helpers.RespondWithError(w, r, err)
 }
 
 func (app *App) getTradeHistoryHandler(w http.ResponseWriter, r *http.Request) {
 	token, err := helpers.GetAddressQueryParam(r, "token")
 	if err != nil {
-		helpers.RespondWithError(w, http.StatusBadRequest, fmt.Sprintf(err.Error(), "token"))
+		helpers.RespondWithError(w, r, err)
 		return
 	}
 
 	base, err := helpers.GetAddressQueryParam(r, "base")
 	if err != nil {
-		helpers.RespondWithError(w, http.StatusBadRequest, fmt.Sprintf(err.Error(), "base"))
+		helpers.RespondWithError(w, r, err)
 		return
 	}
 
-	trades, err := models.GetTradeHistory(app.store, token, base)
+	if !app.isMarketAvailable(token, base) {
+		helpers.RespondWithError(w, r, errMarketNotListed)
+		return
+	}
+
+	count, cursor, err := app.getPageFromRequest(r, 1)
+	if err != nil {
+		helpers.RespondWithError(w, r, err)
+		return
+	}
+
+	trades, err := models.GetTradeHistory(app.store, token, base, count, cursor)
 
 	switch err {
 	case nil:
 		helpers.RespondWithJSON(w, http.StatusOK, trades)
 	default:
-		fmt.Println(err)
-		helpers.RespondWithJSON(w, http.StatusInternalServerError, "internal error")
+		helpers.RespondWithError(w, r, err)
 	}
 }
 
@@ -242,61 +537,93 @@ func (app *App) getOrderByHashHandler(w http.ResponseWriter, r *http.Request) {
 	case nil:
 		helpers.RespondWithJSON(w, http.StatusOK, order)
 	case sql.ErrNoRows:
-		helpers.RespondWithError(w, http.StatusNotFound, "Order not found")
+		helpers.RespondWithError(w, r, errOrderNotFound)
 	default:
-		helpers.RespondWithError(w, http.StatusInternalServerError, "internal error")
+		helpers.RespondWithError(w, r, err)
 	}
 }
 
-var errInvalidCountParam = errors.New("Invalid value for `count` parameter")
-var errInvalidStartParam = errors.New("Invalid value for `start` parameter")
-var errInvalidBeforeParam = errors.New("Invalid value for `before` parameter")
-var errInvalidSideParam = errors.New("Invalid value for `side` parameter")
-var errInvalidStatusParam = errors.New("Invalid value for `status` parameter")
-var errInvalidTokenParam = errors.New("Invalid value for `token` parameter")
-var errInvalidBaseParam = errors.New("Invalid value for `base` parameter")
-var errInvalidUserParam = errors.New("Invalid value for `user` parameter")
-var errMissingTokenParam = errors.New("Missing `token` parameter")
-var errMissingBaseParam = errors.New("Missing `base` parameter")
-var errMissingUserParam = errors.New("Missing `user` parameter")
-
-func getOffsetAndCountFromRequest(r *http.Request) (int, int, error) {
-	count := 50
-	start := 0
-	var err error
+func (app *App) getOrderFillsHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	unwrappedHash := common.HexToHash(strings.TrimPrefix(vars["hash"], "0x"))
+
+	order := models.NewOrder()
+	order.Hash = wrappers.WrapHash(&unwrappedHash)
 
-	if len(r.FormValue("count")) > 0 {
-		count, err = strconv.Atoi(r.FormValue("count"))
+	err := order.Get(app.store)
+
+	switch err {
+	case nil:
+	case sql.ErrNoRows:
+		helpers.RespondWithError(w, r, errOrderNotFound)
+		return
+	default:
+		helpers.RespondWithError(w, r, err)
+		return
+	}
+
+	fills, err := order.GetFills(app.store)
+	if err != nil {
+		helpers.RespondWithError(w, r, err)
+		return
+	}
+
+	helpers.RespondWithJSON(w, http.StatusOK, fills)
+}
+
+var errInvalidEndpoint = helpers.NewError(http.StatusBadRequest, helpers.CodeInvalidRequest, "Invalid API endpoint")
+var errOrderNotFound = helpers.NotFound("Order not found")
+var errInvalidCountParam = helpers.InvalidParam("count", "Invalid value for `count` parameter")
+var errInvalidCursorParam = helpers.InvalidParam("cursor", "Invalid value for `cursor` parameter")
+var errInvalidBeforeParam = helpers.InvalidParam("before", "Invalid value for `before` parameter")
+var errInvalidSideParam = helpers.InvalidParam("side", "Invalid value for `side` parameter")
+var errInvalidStatusParam = helpers.InvalidParam("status", "Invalid value for `status` parameter")
+var errInvalidTokenParam = helpers.InvalidParam("token", "Invalid value for `token` parameter")
+var errInvalidBaseParam = helpers.InvalidParam("base", "Invalid value for `base` parameter")
+var errInvalidCreatorParam = helpers.InvalidParam("creator", "Invalid value for `creator` parameter")
+var errInvalidUserParam = helpers.InvalidParam("user", "Invalid value for `user` parameter")
+var errInvalidDepthParam = helpers.InvalidParam("depth", fmt.Sprintf("Value of `depth` parameter must be between 1 and %d", models.MaxOrderbookDepth))
+var errInvalidTickParam = helpers.InvalidParam("tick", "Value of `tick` parameter must be a positive integer")
+var errInvalidPrecisionParam = helpers.InvalidParam("precision", "Invalid value for `precision` parameter")
+var errInvalidLevelParam = helpers.InvalidParam("level", "Value of `level` parameter must be 2 or 3")
+var errInvalidFormatParam = helpers.InvalidParam("format", "Value of `format` parameter must be json or csv")
+var errInvalidResolutionParam = helpers.InvalidParam("resolution", "Invalid value for `resolution` parameter")
+var errInvalidFromParam = helpers.InvalidParam("from", "Invalid value for `from` parameter")
+var errInvalidToParam = helpers.InvalidParam("to", "Invalid value for `to` parameter")
+var errMissingTokenParam = helpers.MissingParam("token", "Missing `token` parameter")
+var errMissingBaseParam = helpers.MissingParam("base", "Missing `base` parameter")
+var errMissingUserParam = helpers.MissingParam("user", "Missing `user` parameter")
+
+// getPageFromRequest returns page size and cursor of the request. Cursor is
+// nil on first page.
+func (app *App) getPageFromRequest(r *http.Request, cursorKeys int) (int, *models.Cursor, error) {
+	count, maxCount := app.pageSizes()
+
+	if val := r.FormValue("count"); len(val) > 0 {
+		var err error
+		count, err = strconv.Atoi(val)
 		if err != nil {
-			return 0, 0, errInvalidCountParam
+			return 0, nil, errInvalidCountParam
 		}
-		if count > 50 || count < 1 {
-			count = 50
+		if count > maxCount || count < 1 {
+			count = maxCount
 		}
 	}
 
-	if len(r.FormValue("start")) > 0 {
-		start, err = strconv.Atoi(r.FormValue("start"))
+	if val := r.FormValue("cursor"); len(val) > 0 {
+		cursor, err := models.DecodeCursor(val, cursorKeys)
 		if err != nil {
-			return 0, 0, errInvalidStartParam
-		}
-		if start < 0 {
-			start = 0
+			return 0, nil, errInvalidCursorParam
 		}
+		return count, cursor, nil
 	}
 
-	return start, count, nil
+	return count, nil, nil
 }
 
 func getOrderParamsFromRequest(r *http.Request, params *map[string]interface{}) error {
 	var err error
 
-	(*params)["start"], (*params)["count"], err = getOffsetAndCountFromRequest(r)
-
-	if err != nil {
-		return err
-	}
-
 	if val := r.FormValue("before"); len(val) > 0 {
 		var t int
 		t, err = strconv.Atoi(val)
@@ -338,7 +665,7 @@ func getOrderParamsFromRequest(r *http.Request, params *map[string]interface{})
 
 	if val := r.FormValue("creator"); len(val) > 0 {
 		if !common.IsHexAddress(val) {
-			return errInvalidBaseParam
+			return errInvalidCreatorParam
 		}
 		(*params)["creator"] = val
 	}
@@ -383,5 +710,89 @@ func getOrderbookParamsFromRequest(r *http.Request, params *map[string]interface
 		return errMissingBaseParam
 	}
 
+	if val := r.FormValue("depth"); len(val) > 0 {
+		depth, err := strconv.Atoi(val)
+		if err != nil || depth < 1 || depth > models.MaxOrderbookDepth {
+			return errInvalidDepthParam
+		}
+		(*params)["depth"] = depth
+	}
+
+	if val := r.FormValue("tick"); len(val) > 0 {
+		tick, ok := new(big.Int).SetString(val, 10)
+		if !ok || tick.Sign() <= 0 {
+			return errInvalidTickParam
+		}
+		(*params)["tick"] = tick
+	} else if val := r.FormValue("precision"); len(val) > 0 {
+		// Number of trailing digits of the price to group by
+		precision, err := strconv.Atoi(val)
+		if err != nil || precision < 0 || precision > 77 {
+			return errInvalidPrecisionParam
+		}
+		(*params)["tick"] = new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(precision)), nil)
+	}
+
+	switch r.FormValue("level") {
+	case "", "2":
+	case "3":
+		(*params)["l3"] = true
+	default:
+		return errInvalidLevelParam
+	}
+
 	return nil
 }
+
+// TradingView resolution names
+var udfResolutions = map[string]string{
+	"1":   "1m",
+	"5":   "5m",
+	"15":  "15m",
+	"60":  "1h",
+	"240": "4h",
+	"D":   "1d",
+	"1D":  "1d",
+	"W":   "1w",
+	"1W":  "1w",
+}
+
+// getOHLCParamsFromRequest returns candle width and time range. Range
+// defaults to the last month, or less if that holds too many candles.
+func getOHLCParamsFromRequest(r *http.Request) (time.Duration, time.Time, time.Time, error) {
+	name := r.FormValue("resolution")
+	if len(name) == 0 {
+		name = "5m"
+	}
+	if alias, ok := udfResolutions[name]; ok {
+		name = alias
+	}
+
+	resolution, ok := models.OHLCResolutions[name]
+	if !ok {
+		return 0, time.Time{}, time.Time{}, errInvalidResolutionParam
+	}
+
+	to := time.Now()
+	if val := r.FormValue("to"); len(val) > 0 {
+		t, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			return 0, time.Time{}, time.Time{}, errInvalidToParam
+		}
+		to = time.Unix(t, 0)
+	}
+
+	from := to.AddDate(0, -1, 0)
+	if earliest := to.Add(-resolution * models.MaxOHLCBuckets); from.Before(earliest) {
+		from = earliest
+	}
+	if val := r.FormValue("from"); len(val) > 0 {
+		t, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			return 0, time.Time{}, time.Time{}, errInvalidFromParam
+		}
+		from = time.Unix(t, 0)
+	}
+
+	return resolution, from, to, nil
+}
@@ -0,0 +1,92 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"hameid.net/cdex/dex/internal/config"
+	"hameid.net/cdex/dex/internal/helpers"
+)
+
+var errMarketNotListed = helpers.NotFound("Market is not listed")
+
+// settings holds configuration that can change while app is running
+type settings struct {
+	mu          sync.RWMutex
+	corsOrigins map[string]bool
+	markets     map[[2]common.Address]config.MarketConfig
+	pageSize    int
+	maxPageSize int
+	rateLimits  rateLimits
+}
+
+// Reload applies reloadable settings of the config
+func (app *App) Reload(cfg *config.Config) {
+	corsOrigins := make(map[string]bool, len(cfg.App.CORSOrigins))
+	for _, origin := range cfg.App.CORSOrigins {
+		corsOrigins[strings.ToLower(origin)] = true
+	}
+
+	markets := make(map[[2]common.Address]config.MarketConfig, len(cfg.Markets))
+	for _, market := range cfg.Markets {
+		markets[[2]common.Address{market.Token, market.Base}] = market
+	}
+
+	app.settings.mu.Lock()
+	app.settings.corsOrigins = corsOrigins
+	app.settings.markets = markets
+	app.settings.pageSize = int(cfg.App.PageSize)
+	app.settings.maxPageSize = int(cfg.App.MaxPageSize)
+	app.settings.rateLimits = rateLimits{
+		ip:             cfg.App.RateLimits.IP,
+		address:        cfg.App.RateLimits.Address,
+		heavy:          cfg.App.RateLimits.Heavy,
+		trustedProxies: int(cfg.App.RateLimits.TrustedProxies),
+	}
+	if app.settings.rateLimits.trustedProxies == 0 && cfg.App.RateLimits.TrustProxy {
+		app.settings.rateLimits.trustedProxies = 1
+	}
+	app.settings.mu.Unlock()
+
+	fmt.Printf("App settings: %d CORS origins, %d markets\n", len(corsOrigins), len(markets))
+}
+
+// allowOrigin tells if requests from the origin are allowed
+func (app *App) allowOrigin(origin string) bool {
+	app.settings.mu.RLock()
+	defer app.settings.mu.RUnlock()
+
+	return app.settings.corsOrigins["*"] || app.settings.corsOrigins[strings.ToLower(origin)]
+}
+
+// pageSizes returns default and maximum number of items in a page
+func (app *App) pageSizes() (int, int) {
+	app.settings.mu.RLock()
+	defer app.settings.mu.RUnlock()
+
+	return app.settings.pageSize, app.settings.maxPageSize
+}
+
+// getRateLimits returns rate limits of clients
+func (app *App) getRateLimits() rateLimits {
+	app.settings.mu.RLock()
+	defer app.settings.mu.RUnlock()
+
+	return app.settings.rateLimits
+}
+
+// isMarketAvailable tells if the pair is served. All pairs are served if
+// no market is configured.
+func (app *App) isMarketAvailable(token, base *common.Address) bool {
+	app.settings.mu.RLock()
+	defer app.settings.mu.RUnlock()
+
+	if len(app.settings.markets) == 0 {
+		return true
+	}
+
+	market, ok := app.settings.markets[[2]common.Address{*token, *base}]
+	return ok && !market.Disabled
+}
@@ -0,0 +1,165 @@
+package app
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gorilla/mux"
+	"hameid.net/cdex/dex/internal/helpers"
+	"hameid.net/cdex/dex/internal/models"
+)
+
+// Entries written between flushes of a streamed statement
+const statementFlushInterval = 100
+
+var statementCSVHeader = []string{"time", "type", "token", "amount", "balance", "tx_hash", "order_hash"}
+
+// getStatementHandler streams every balance change of a wallet as CSV or
+// JSON array. Errors after streaming started end the response early.
+func (app *App) getStatementHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	wallet := common.HexToAddress(strings.TrimPrefix(vars["address"], "0x"))
+
+	from := time.Unix(0, 0)
+	if val := r.FormValue("from"); len(val) > 0 {
+		t, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			helpers.RespondWithError(w, r, errInvalidFromParam)
+			return
+		}
+		from = time.Unix(t, 0)
+	}
+
+	to := time.Now()
+	if val := r.FormValue("to"); len(val) > 0 {
+		t, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			helpers.RespondWithError(w, r, errInvalidToParam)
+			return
+		}
+		to = time.Unix(t, 0)
+	}
+
+	if !to.After(from) {
+		helpers.RespondWithError(w, r, helpers.InvalidParam("from", models.ErrInvalidTimeRange.Error()))
+		return
+	}
+
+	format := r.FormValue("format")
+	switch format {
+	case "":
+		format = "json"
+	case "json", "csv":
+	default:
+		helpers.RespondWithError(w, r, errInvalidFormatParam)
+		return
+	}
+
+	rates := models.FeeRates{}
+	if app.networks != nil {
+		rates.Take = app.networks.Exchange.TakeFee
+		rates.Make = app.networks.Exchange.MakeFee
+	}
+
+	filename := fmt.Sprintf("statement-%s-%d-%d.%s", strings.ToLower(wallet.Hex()), from.Unix(), to.Unix(), format)
+
+	// Response starts with the first entry, so failed queries still get an
+	// error response
+	started := false
+	start := func() {
+		if started {
+			return
+		}
+		started = true
+
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+		if format == "csv" {
+			w.Header().Set("Content-Type", "text/csv")
+		} else {
+			w.Header().Set("Content-Type", "application/json")
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+
+	flusher, _ := w.(http.Flusher)
+	written := 0
+	flush := func() {
+		written++
+		if flusher != nil && written%statementFlushInterval == 0 {
+			flusher.Flush()
+		}
+	}
+
+	var err error
+	if format == "csv" {
+		out := csv.NewWriter(w)
+
+		err = models.StreamStatement(app.store, &wallet, from, to, rates, func(entry *models.StatementEntry) error {
+			if !started {
+				start()
+				out.Write(statementCSVHeader)
+			}
+			out.Write([]string{
+				entry.Time.Format(time.RFC3339),
+				entry.Kind,
+				strings.ToLower(entry.Token.Hex()),
+				entry.Amount.String(),
+				entry.Balance.String(),
+				stringOrEmpty(entry.TxHash),
+				stringOrEmpty(entry.OrderHash),
+			})
+			out.Flush()
+			flush()
+			return out.Error()
+		})
+
+		if err == nil && !started {
+			start()
+			out.Write(statementCSVHeader)
+			out.Flush()
+		}
+	} else {
+		encoder := json.NewEncoder(w)
+
+		err = models.StreamStatement(app.store, &wallet, from, to, rates, func(entry *models.StatementEntry) error {
+			if !started {
+				start()
+				w.Write([]byte("["))
+			} else {
+				w.Write([]byte(","))
+			}
+			flush()
+			return encoder.Encode(entry)
+		})
+
+		if err == nil {
+			if !started {
+				start()
+				w.Write([]byte("["))
+			}
+			w.Write([]byte("]"))
+		}
+	}
+
+	if err != nil {
+		if !started {
+			helpers.RespondWithError(w, r, err)
+			return
+		}
+		log.Printf("ERROR request %s: statement of %s ended early: %s", helpers.GetRequestID(r), wallet.Hex(), err)
+	}
+}
+
+func stringOrEmpty(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
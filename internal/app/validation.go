@@ -0,0 +1,99 @@
+package app
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/mux"
+	"hameid.net/cdex/dex/internal/helpers"
+)
+
+var patterns = struct {
+	sync.Mutex
+	compiled map[string]*regexp.Regexp
+}{compiled: make(map[string]*regexp.Regexp)}
+
+func compilePattern(pattern string) *regexp.Regexp {
+	patterns.Lock()
+	defer patterns.Unlock()
+
+	re, ok := patterns.compiled[pattern]
+	if !ok {
+		re = regexp.MustCompile(pattern)
+		patterns.compiled[pattern] = re
+	}
+
+	return re
+}
+
+// validateParam checks raw value of the parameter against its schema
+func validateParam(param *openAPIParameter, raw string) *helpers.APIError {
+	if raw == "" {
+		if param.Required {
+			return helpers.MissingParam(param.Name, fmt.Sprintf("Missing `%s` parameter", param.Name))
+		}
+		return nil
+	}
+
+	schema := param.Schema
+	invalid := helpers.InvalidParam(param.Name, fmt.Sprintf("Invalid value for `%s` parameter", param.Name))
+
+	switch schema.Type {
+	case "integer":
+		v, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return invalid
+		}
+		if schema.Minimum != nil && schema.Maximum != nil && (v < *schema.Minimum || v > *schema.Maximum) {
+			return helpers.InvalidParam(param.Name, fmt.Sprintf("Value of `%s` parameter must be between %d and %d", param.Name, *schema.Minimum, *schema.Maximum))
+		}
+	case "string":
+		if len(schema.Enum) > 0 {
+			for _, value := range schema.Enum {
+				if raw == value {
+					return nil
+				}
+			}
+			return helpers.InvalidParam(param.Name, fmt.Sprintf("Value of `%s` parameter must be one of %s", param.Name, strings.Join(schema.Enum, ", ")))
+		}
+		if schema.Pattern != "" && !compilePattern(schema.Pattern).MatchString(raw) {
+			return invalid
+		}
+	}
+
+	return nil
+}
+
+// validateParams responds 400 to requests with parameters that do not
+// match the route
+func validateParams(route apiRoute, next http.HandlerFunc) http.HandlerFunc {
+	invalid := route.invalid
+	if invalid == nil {
+		invalid = helpers.RespondWithError
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+
+		for _, param := range route.params {
+			var raw string
+			switch param.In {
+			case "path":
+				raw = vars[param.Name]
+			case "query":
+				raw = strings.TrimSpace(r.FormValue(param.Name))
+			}
+
+			if err := validateParam(param, raw); err != nil {
+				invalid(w, r, err)
+				return
+			}
+		}
+
+		next(w, r)
+	}
+}
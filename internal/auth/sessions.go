@@ -0,0 +1,169 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/go-redis/redis"
+	"hameid.net/cdex/dex/internal/utils"
+)
+
+// ErrInvalidNonce thrown if login nonce is not one of the wallet, expired or
+// was used
+var ErrInvalidNonce = errors.New("Login nonce is invalid or expired")
+
+// ErrInvalidSignature thrown if login message was not signed by the wallet
+var ErrInvalidSignature = errors.New("Signature does not match wallet")
+
+// ErrInvalidSession thrown if session token is unknown or expired
+var ErrInvalidSession = errors.New("Session is invalid or expired")
+
+const (
+	nonceKeyPrefix   = "auth:nonce:"
+	sessionKeyPrefix = "auth:session:"
+)
+
+// Sessions issues session tokens to wallets that sign a login nonce with
+// personal_sign. Nonces and sessions are kept in redis, so every service
+// connected to it accepts the same tokens. Nonces are keyed by their value,
+// so requesting one does not affect nonces handed out before.
+type Sessions struct {
+	redisClient *redis.Client
+	sessionTTL  time.Duration
+	nonceTTL    time.Duration
+}
+
+// Session of a wallet
+type Session struct {
+	Token     string         `json:"token"`
+	Address   common.Address `json:"address"`
+	ExpiresAt int64          `json:"expires_at"`
+}
+
+// LoginMessage returns the text a wallet signs to log in with nonce
+func LoginMessage(address common.Address, nonce string) string {
+	return fmt.Sprintf("Sign in to CDEX\n\nWallet: %s\nNonce: %s", strings.ToLower(address.Hex()), nonce)
+}
+
+// NewNonce creates a login nonce for the wallet
+func (sessions *Sessions) NewNonce(address common.Address) (string, error) {
+	nonce, err := randomHex(16)
+	if err != nil {
+		return "", err
+	}
+
+	created, err := sessions.redisClient.SetNX(nonceKey(nonce), strings.ToLower(address.Hex()), sessions.nonceTTL).Result()
+	if err != nil {
+		return "", err
+	}
+	if !created {
+		return "", errors.New("Login nonce already exists")
+	}
+
+	return nonce, nil
+}
+
+// Login verifies that signature is the wallet's personal_sign of its login
+// message with nonce and starts a session. Nonce can only be used once.
+func (sessions *Sessions) Login(address common.Address, nonce string, signature []byte) (*Session, error) {
+	if nonce == "" {
+		return nil, ErrInvalidNonce
+	}
+	key := nonceKey(nonce)
+
+	wallet, err := sessions.redisClient.Get(key).Result()
+	if err == redis.Nil {
+		return nil, ErrInvalidNonce
+	}
+	if err != nil {
+		return nil, err
+	}
+	if wallet != strings.ToLower(address.Hex()) {
+		return nil, ErrInvalidNonce
+	}
+
+	// Only the wallet can use its nonce up
+	signer, err := utils.RecoverPersonalSigner([]byte(LoginMessage(address, nonce)), signature)
+	if err != nil || signer != address {
+		return nil, ErrInvalidSignature
+	}
+
+	// Nonce keys never change value, only the request that deletes the
+	// nonce may use it
+	deleted, err := sessions.redisClient.Del(key).Result()
+	if err != nil {
+		return nil, err
+	}
+	if deleted == 0 {
+		return nil, ErrInvalidNonce
+	}
+
+	token, err := randomHex(32)
+	if err != nil {
+		return nil, err
+	}
+
+	err = sessions.redisClient.Set(sessionKey(token), strings.ToLower(address.Hex()), sessions.sessionTTL).Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Session{
+		Token:     token,
+		Address:   address,
+		ExpiresAt: time.Now().Add(sessions.sessionTTL).Unix(),
+	}, nil
+}
+
+// Verify returns the wallet of the session token
+func (sessions *Sessions) Verify(token string) (common.Address, error) {
+	if token == "" {
+		return common.Address{}, ErrInvalidSession
+	}
+
+	address, err := sessions.redisClient.Get(sessionKey(token)).Result()
+	if err == redis.Nil {
+		return common.Address{}, ErrInvalidSession
+	}
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	return common.HexToAddress(address), nil
+}
+
+// Logout ends the session
+func (sessions *Sessions) Logout(token string) error {
+	return sessions.redisClient.Del(sessionKey(token)).Err()
+}
+
+func nonceKey(nonce string) string {
+	return nonceKeyPrefix + nonce
+}
+
+func sessionKey(token string) string {
+	return sessionKeyPrefix + token
+}
+
+func randomHex(size int) (string, error) {
+	b := make([]byte, size)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+// NewSessions creates new instance of Sessions
+func NewSessions(redisClient *redis.Client, sessionTTL uint64, nonceTTL uint64) *Sessions {
+	return &Sessions{
+		redisClient: redisClient,
+		sessionTTL:  time.Duration(sessionTTL) * time.Second,
+		nonceTTL:    time.Duration(nonceTTL) * time.Second,
+	}
+}
@@ -0,0 +1,215 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/go-redis/redis"
+	"hameid.net/cdex/dex/internal/redistest"
+	"hameid.net/cdex/dex/internal/utils"
+)
+
+func newTestSessions(t *testing.T) (*Sessions, *redistest.Server, func()) {
+	server, err := redistest.NewServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	sessions := NewSessions(client, 3600, 60)
+
+	return sessions, server, func() {
+		client.Close()
+		server.Close()
+	}
+}
+
+func newTestWallet(t *testing.T) (*ecdsa.PrivateKey, common.Address) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key, crypto.PubkeyToAddress(key.PublicKey)
+}
+
+// signLogin signs the login message of the wallet with personal_sign
+func signLogin(t *testing.T, key *ecdsa.PrivateKey, address common.Address, nonce string) []byte {
+	signature, err := crypto.Sign(utils.PersonalMessageHash([]byte(LoginMessage(address, nonce))), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signature[64] += 27
+	return signature
+}
+
+func TestLogin(t *testing.T) {
+	sessions, _, cleanup := newTestSessions(t)
+	defer cleanup()
+
+	key, address := newTestWallet(t)
+	nonce, err := sessions.NewNonce(address)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	session, err := sessions.Login(address, nonce, signLogin(t, key, address, nonce))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if session.Address != address {
+		t.Errorf("got session of %s, want %s", session.Address.Hex(), address.Hex())
+	}
+
+	verified, err := sessions.Verify(session.Token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if verified != address {
+		t.Errorf("token verified as %s, want %s", verified.Hex(), address.Hex())
+	}
+}
+
+func TestLoginRejectsWrongSigner(t *testing.T) {
+	sessions, _, cleanup := newTestSessions(t)
+	defer cleanup()
+
+	key, address := newTestWallet(t)
+	otherKey, _ := newTestWallet(t)
+
+	nonce, err := sessions.NewNonce(address)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := sessions.Login(address, nonce, signLogin(t, otherKey, address, nonce)); err != ErrInvalidSignature {
+		t.Fatalf("got error %v, want %v", err, ErrInvalidSignature)
+	}
+
+	// A failed attempt must not use up the nonce of the wallet
+	if _, err := sessions.Login(address, nonce, signLogin(t, key, address, nonce)); err != nil {
+		t.Errorf("wallet cannot log in after a wrong signature: %v", err)
+	}
+}
+
+func TestNewNonceKeepsEarlierNonces(t *testing.T) {
+	sessions, _, cleanup := newTestSessions(t)
+	defer cleanup()
+
+	key, address := newTestWallet(t)
+
+	nonce, err := sessions.NewNonce(address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Requested by anyone for the same wallet
+	if _, err := sessions.NewNonce(address); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := sessions.Login(address, nonce, signLogin(t, key, address, nonce)); err != nil {
+		t.Errorf("wallet cannot log in after another nonce was requested: %v", err)
+	}
+}
+
+func TestLoginRejectsNonceOfAnotherWallet(t *testing.T) {
+	sessions, _, cleanup := newTestSessions(t)
+	defer cleanup()
+
+	key, address := newTestWallet(t)
+	_, otherAddress := newTestWallet(t)
+
+	nonce, err := sessions.NewNonce(otherAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := sessions.Login(address, nonce, signLogin(t, key, address, nonce)); err != ErrInvalidNonce {
+		t.Errorf("got error %v, want %v", err, ErrInvalidNonce)
+	}
+}
+
+func TestLoginRejectsReusedNonce(t *testing.T) {
+	sessions, _, cleanup := newTestSessions(t)
+	defer cleanup()
+
+	key, address := newTestWallet(t)
+	nonce, err := sessions.NewNonce(address)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signature := signLogin(t, key, address, nonce)
+	if _, err := sessions.Login(address, nonce, signature); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := sessions.Login(address, nonce, signature); err != ErrInvalidNonce {
+		t.Errorf("got error %v, want %v", err, ErrInvalidNonce)
+	}
+}
+
+func TestLoginRejectsExpiredNonce(t *testing.T) {
+	sessions, server, cleanup := newTestSessions(t)
+	defer cleanup()
+
+	key, address := newTestWallet(t)
+	nonce, err := sessions.NewNonce(address)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server.FastForward(sessions.nonceTTL + time.Second)
+
+	if _, err := sessions.Login(address, nonce, signLogin(t, key, address, nonce)); err != ErrInvalidNonce {
+		t.Errorf("got error %v, want %v", err, ErrInvalidNonce)
+	}
+}
+
+func TestVerifyRejectsExpiredSession(t *testing.T) {
+	sessions, server, cleanup := newTestSessions(t)
+	defer cleanup()
+
+	key, address := newTestWallet(t)
+	nonce, err := sessions.NewNonce(address)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	session, err := sessions.Login(address, nonce, signLogin(t, key, address, nonce))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server.FastForward(sessions.sessionTTL + time.Second)
+
+	if _, err := sessions.Verify(session.Token); err != ErrInvalidSession {
+		t.Errorf("got error %v, want %v", err, ErrInvalidSession)
+	}
+}
+
+func TestLogout(t *testing.T) {
+	sessions, _, cleanup := newTestSessions(t)
+	defer cleanup()
+
+	key, address := newTestWallet(t)
+	nonce, err := sessions.NewNonce(address)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	session, err := sessions.Login(address, nonce, signLogin(t, key, address, nonce))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := sessions.Logout(session.Token); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := sessions.Verify(session.Token); err != ErrInvalidSession {
+		t.Errorf("got error %v, want %v", err, ErrInvalidSession)
+	}
+}
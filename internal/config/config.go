@@ -0,0 +1,415 @@
+package config
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"hameid.net/cdex/dex/internal/utils"
+)
+
+// Services that can be configured
+const (
+	ServiceApp          = "app"
+	ServiceSocketServer = "socketserver"
+	ServiceRelayer      = "relayer"
+	ServiceValidator    = "validator"
+	ServiceRPCServer    = "rpcserver"
+)
+
+// KeyConfig tells where to find the key of an account. Either a keystore and
+// its password file or a remote signer has to be set.
+type KeyConfig struct {
+	KeystoreFile  string `json:"keystoreFile"`
+	PasswordFile  string `json:"passwordFile"`
+	SignerURL     string `json:"signerURL"`
+	SignerAddress string `json:"signerAddress"`
+}
+
+// IsSet returns true if a keystore or a remote signer is configured
+func (key *KeyConfig) IsSet() bool {
+	return key.KeystoreFile != "" || key.SignerURL != ""
+}
+
+// RateLimitConfig is a token bucket refilled with Rate requests per minute
+// that holds up to Burst requests. Rate 0 disables the limit.
+type RateLimitConfig struct {
+	Rate  uint64 `json:"rate"`
+	Burst uint64 `json:"burst"`
+}
+
+// MarketConfig holds settings of a token pair
+type MarketConfig struct {
+	Token    common.Address `json:"token"`
+	Base     common.Address `json:"base"`
+	Disabled bool           `json:"disabled"`
+}
+
+// Config holds settings of all services
+type Config struct {
+	ContractsFile string `json:"contractsFile"`
+	NetworksFile  string `json:"networksFile"`
+
+	Database struct {
+		ConnectionString string `json:"connectionString"`
+	} `json:"db"`
+
+	Redis struct {
+		Host     string `json:"host"`
+		Password string `json:"password"`
+	} `json:"redis"`
+
+	App struct {
+		Port        uint64   `json:"port"`
+		CORSOrigins []string `json:"corsOrigins"`
+		PageSize    uint64   `json:"pageSize"`
+		MaxPageSize uint64   `json:"maxPageSize"`
+
+		RateLimits struct {
+			// Requests without a session, by client IP
+			IP RateLimitConfig `json:"ip"`
+			// Requests with a session, by wallet address
+			Address RateLimitConfig `json:"address"`
+			// Additional limit of each client on heavy endpoints
+			Heavy RateLimitConfig `json:"heavy"`
+			// Take client IP from X-Forwarded-For set by a proxy, same as
+			// one trusted proxy
+			TrustProxy bool `json:"trustProxy"`
+			// Number of proxies in front of app that append to
+			// X-Forwarded-For
+			TrustedProxies uint64 `json:"trustedProxies"`
+		} `json:"rateLimits"`
+	} `json:"app"`
+
+	SocketServer struct {
+		Port       uint64 `json:"port"`
+		WebappHost string `json:"webappHost"`
+	} `json:"socketServer"`
+
+	// gRPC server, sharing page sizes and markets with app
+	RPCServer struct {
+		Port uint64 `json:"port"`
+		// HTTP port of metrics and health checks
+		MetricsPort uint64 `json:"metricsPort"`
+	} `json:"rpcServer"`
+
+	// Wallet signature login
+	Auth struct {
+		// Lifetime of session tokens in seconds
+		SessionTTL uint64 `json:"sessionTTL"`
+		// Time given to sign a login nonce in seconds
+		NonceTTL uint64 `json:"nonceTTL"`
+	} `json:"auth"`
+
+	Validator struct {
+		Key             KeyConfig `json:"key"`
+		LimitsFile      string    `json:"limitsFile"`
+		LimitsStateFile string    `json:"limitsStateFile"`
+		MessageVersion  uint64    `json:"messageVersion"`
+		TypedSignatures bool      `json:"typedSignatures"`
+		HealthPort      uint64    `json:"healthPort"`
+		CheckpointFile  string    `json:"checkpointFile"`
+	} `json:"validator"`
+
+	Relayer struct {
+		Matcher       KeyConfig `json:"matcher"`
+		WithdrawRelay KeyConfig `json:"withdrawRelay"`
+		HealthPort    uint64    `json:"healthPort"`
+	} `json:"relayer"`
+
+	Health struct {
+		// Readiness fails if the head block of a chain is older, in seconds
+		MaxBlockAge uint64 `json:"maxBlockAge"`
+	} `json:"health"`
+
+	// Markets listed on the exchange. All pairs are served if empty.
+	Markets []MarketConfig `json:"markets"`
+
+	// File the config was read from, if any
+	FilePath string `json:"-"`
+
+	// Loaded from ContractsFile and NetworksFile
+	Contracts *utils.ContractsInfo `json:"-"`
+	Networks  *utils.NetworksInfo  `json:"-"`
+}
+
+// Settings that can change without restarting services. Settings read only
+// from config file (markets) are always reloadable.
+var reloadableOptions = map[string]bool{
+	"app.corsOrigins":               true,
+	"app.pageSize":                  true,
+	"app.maxPageSize":               true,
+	"app.rateLimits.ip.rate":        true,
+	"app.rateLimits.ip.burst":       true,
+	"app.rateLimits.address.rate":   true,
+	"app.rateLimits.address.burst":  true,
+	"app.rateLimits.heavy.rate":     true,
+	"app.rateLimits.heavy.burst":    true,
+	"app.rateLimits.trustProxy":     true,
+	"app.rateLimits.trustedProxies": true,
+	"socketServer.webappHost":       true,
+}
+
+// option maps a config field to its flag and environment variables
+type option struct {
+	name string
+	// First one is the preferred name, the rest are kept for compatibility
+	env    []string
+	secret bool
+	value  interface{}
+	usage  string
+}
+
+func (cfg *Config) options() []option {
+	rateLimitOptions := func(prefix string, envPrefix string, limit *RateLimitConfig, usage string) []option {
+		return []option{
+			{prefix + ".rate", []string{envPrefix + "_RATE"}, false, &limit.Rate, "Requests per minute " + usage + ", 0 for no limit"},
+			{prefix + ".burst", []string{envPrefix + "_BURST"}, false, &limit.Burst, "Burst of requests " + usage},
+		}
+	}
+
+	keyOptions := func(prefix string, envPrefix string, key *KeyConfig, usage string) []option {
+		return []option{
+			{prefix + ".keystoreFile", []string{envPrefix + "_KEYSTORE_FILE"}, false, &key.KeystoreFile, "Keystore of " + usage},
+			{prefix + ".passwordFile", []string{envPrefix + "_PASSWORD_FILE"}, false, &key.PasswordFile, "Keystore password file of " + usage},
+			{prefix + ".signerURL", []string{envPrefix + "_SIGNER_URL"}, false, &key.SignerURL, "Remote signer of " + usage},
+			{prefix + ".signerAddress", []string{envPrefix + "_SIGNER_ADDRESS"}, false, &key.SignerAddress, "Remote signer account of " + usage},
+		}
+	}
+
+	options := []option{
+		{"contractsFile", []string{"CDEX_CONTRACTS_FILE", "DEX_VALIDATOR_CONTRACTS_FILE"}, false, &cfg.ContractsFile, "Contracts file"},
+		{"networksFile", []string{"CDEX_NETWORKS_FILE", "DEX_VALIDATOR_NETWORKS_FILE", "DEX_APP_NETWORKS_FILE"}, false, &cfg.NetworksFile, "Networks file"},
+		{"db.connectionString", []string{"CDEX_DB_CONNECTION_STRING"}, true, &cfg.Database.ConnectionString, "Database connection string"},
+		{"redis.host", []string{"CDEX_REDIS_HOST"}, false, &cfg.Redis.Host, "Redis address"},
+		{"redis.password", []string{"CDEX_REDIS_PASSWORD"}, true, &cfg.Redis.Password, "Redis password"},
+		{"app.port", []string{"DEX_APP_LAYER_PORT"}, false, &cfg.App.Port, "App server port"},
+		{"app.corsOrigins", []string{"CDEX_CORS_ORIGINS"}, false, &cfg.App.CORSOrigins, "Comma separated origins allowed to call the app server"},
+		{"app.pageSize", []string{"CDEX_APP_PAGE_SIZE"}, false, &cfg.App.PageSize, "Default number of items in a page"},
+		{"app.maxPageSize", []string{"CDEX_APP_MAX_PAGE_SIZE"}, false, &cfg.App.MaxPageSize, "Maximum number of items in a page"},
+		{"app.rateLimits.trustProxy", []string{"CDEX_RATE_LIMIT_TRUST_PROXY"}, false, &cfg.App.RateLimits.TrustProxy, "Take client IP from X-Forwarded-For header set by one proxy"},
+		{"app.rateLimits.trustedProxies", []string{"CDEX_RATE_LIMIT_TRUSTED_PROXIES"}, false, &cfg.App.RateLimits.TrustedProxies, "Number of proxies that append to X-Forwarded-For header, client IP is the one appended by the outermost"},
+		{"socketServer.port", []string{"DEX_WS_LAYER_PORT"}, false, &cfg.SocketServer.Port, "Websocket server port"},
+		{"socketServer.webappHost", []string{"CDEX_WEBAPP_HOST"}, false, &cfg.SocketServer.WebappHost, "Origin of the web app"},
+		{"rpcServer.port", []string{"CDEX_RPC_PORT"}, false, &cfg.RPCServer.Port, "gRPC server port"},
+		{"rpcServer.metricsPort", []string{"CDEX_RPC_METRICS_PORT"}, false, &cfg.RPCServer.MetricsPort, "Port of gRPC server metrics and health checks"},
+		{"auth.sessionTTL", []string{"CDEX_AUTH_SESSION_TTL"}, false, &cfg.Auth.SessionTTL, "Lifetime of session tokens in seconds"},
+		{"auth.nonceTTL", []string{"CDEX_AUTH_NONCE_TTL"}, false, &cfg.Auth.NonceTTL, "Time given to sign a login nonce in seconds"},
+		{"validator.limitsFile", []string{"DEX_VALIDATOR_LIMITS_FILE"}, false, &cfg.Validator.LimitsFile, "Deposit and withdrawal limits file"},
+		{"validator.limitsStateFile", []string{"DEX_VALIDATOR_LIMITS_STATE_FILE"}, false, &cfg.Validator.LimitsStateFile, "State file of deposit and withdrawal limits"},
+		{"validator.messageVersion", []string{"DEX_VALIDATOR_MESSAGE_VERSION"}, false, &cfg.Validator.MessageVersion, "Withdrawal message version, 1 binds withdrawals to the chain and bridge"},
+		{"validator.typedSignatures", []string{"DEX_VALIDATOR_TYPED_SIGNATURES"}, false, &cfg.Validator.TypedSignatures, "Sign withdrawals as EIP-712 typed data, requires message version 1"},
+		{"validator.checkpointFile", []string{"DEX_VALIDATOR_CHECKPOINT_FILE"}, false, &cfg.Validator.CheckpointFile, "File recording the last processed block of each network"},
+		{"validator.healthPort", []string{"CDEX_VALIDATOR_HEALTH_PORT"}, false, &cfg.Validator.HealthPort, "Port of validator health checks and metrics"},
+		{"relayer.healthPort", []string{"CDEX_RELAYER_HEALTH_PORT"}, false, &cfg.Relayer.HealthPort, "Port of relayer health checks and metrics"},
+		{"health.maxBlockAge", []string{"CDEX_HEALTH_MAX_BLOCK_AGE"}, false, &cfg.Health.MaxBlockAge, "Seconds the head block of a chain can be old before readiness fails"},
+	}
+
+	options = append(options, rateLimitOptions("app.rateLimits.ip", "CDEX_RATE_LIMIT_IP", &cfg.App.RateLimits.IP, "of a client IP")...)
+	options = append(options, rateLimitOptions("app.rateLimits.address", "CDEX_RATE_LIMIT_ADDRESS", &cfg.App.RateLimits.Address, "of a signed in wallet")...)
+	options = append(options, rateLimitOptions("app.rateLimits.heavy", "CDEX_RATE_LIMIT_HEAVY", &cfg.App.RateLimits.Heavy, "of a client on heavy endpoints")...)
+	options = append(options, keyOptions("validator.key", "DEX_VALIDATOR", &cfg.Validator.Key, "validator")...)
+	options = append(options, keyOptions("relayer.matcher", "DEX_ORDER_MATCHER", &cfg.Relayer.Matcher, "order matcher")...)
+	options = append(options, keyOptions("relayer.withdrawRelay", "DEX_WITHDRAW_RELAY", &cfg.Relayer.WithdrawRelay, "withdraw relay operator")...)
+
+	return options
+}
+
+// Load reads and validates config of the service. See Read.
+func Load(service string, args []string) (*Config, []string, error) {
+	cfg, args, err := Read(service, args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := cfg.Validate(service); err != nil {
+		return nil, nil, err
+	}
+
+	return cfg, args, nil
+}
+
+// Read reads config file, then environment variables, then command line
+// flags, each overriding the previous one. Remaining command line arguments
+// are returned. Config is not validated.
+func Read(service string, args []string) (*Config, []string, error) {
+	cfg := &Config{}
+	cfg.App.Port = 6454
+	cfg.App.CORSOrigins = []string{"*"}
+	cfg.App.PageSize = 50
+	cfg.App.MaxPageSize = 200
+	cfg.App.RateLimits.IP = RateLimitConfig{Rate: 600, Burst: 100}
+	cfg.App.RateLimits.Address = RateLimitConfig{Rate: 1200, Burst: 200}
+	cfg.App.RateLimits.Heavy = RateLimitConfig{Rate: 60, Burst: 10}
+	cfg.SocketServer.Port = 7424
+	cfg.RPCServer.Port = 6455
+	cfg.Relayer.HealthPort = 6456
+	cfg.Validator.HealthPort = 6457
+	cfg.RPCServer.MetricsPort = 6458
+	cfg.Health.MaxBlockAge = 120
+	cfg.Auth.SessionTTL = 3600
+	cfg.Auth.NonceTTL = 300
+
+	options := cfg.options()
+
+	flags := flag.NewFlagSet(service, flag.ContinueOnError)
+	configFile := flags.String("config", os.Getenv("CDEX_CONFIG_FILE"), "JSON config file")
+	flagValues := make(map[string]*string, len(options))
+	for _, o := range options {
+		flagValues[o.name] = flags.String(o.name, "", fmt.Sprintf("%s (env %s)", o.usage, o.env[0]))
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return nil, nil, err
+	}
+
+	if *configFile != "" {
+		if err := cfg.readFile(*configFile); err != nil {
+			return nil, nil, err
+		}
+		cfg.FilePath = *configFile
+	}
+
+	for _, o := range options {
+		for _, env := range o.env {
+			if val, ok := os.LookupEnv(env); ok && len(val) > 0 {
+				if err := setOption(o, val); err != nil {
+					return nil, nil, fmt.Errorf("%s: %s", env, err)
+				}
+				break
+			}
+		}
+	}
+
+	var err error
+	flags.Visit(func(f *flag.Flag) {
+		for _, o := range options {
+			if o.name == f.Name && err == nil {
+				if setErr := setOption(o, *flagValues[o.name]); setErr != nil {
+					err = fmt.Errorf("-%s: %s", f.Name, setErr)
+				}
+			}
+		}
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := cfg.loadNetworks(service); err != nil {
+		return nil, nil, err
+	}
+
+	return cfg, flags.Args(), nil
+}
+
+func (cfg *Config) readFile(filePath string) error {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	decoder := json.NewDecoder(file)
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(cfg); err != nil {
+		return fmt.Errorf("%s: %s", filePath, err)
+	}
+
+	return nil
+}
+
+// loadNetworks reads contracts and networks files needed by the service
+func (cfg *Config) loadNetworks(service string) error {
+	var err error
+
+	if cfg.NetworksFile != "" && service != ServiceSocketServer {
+		cfg.Networks, err = utils.ReadNetworksInfo(cfg.NetworksFile)
+		if err != nil {
+			return fmt.Errorf("%s: %s", cfg.NetworksFile, err)
+		}
+	}
+
+	if cfg.ContractsFile != "" && (service == ServiceRelayer || service == ServiceValidator) {
+		cfg.Contracts, err = utils.ReadContractsInfo(cfg.ContractsFile)
+		if err != nil {
+			return fmt.Errorf("%s: %s", cfg.ContractsFile, err)
+		}
+	}
+
+	return nil
+}
+
+func setOption(o option, raw string) error {
+	raw = strings.TrimSpace(raw)
+
+	switch value := o.value.(type) {
+	case *string:
+		*value = raw
+	case *uint64:
+		v, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			return err
+		}
+		*value = v
+	case *bool:
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		*value = v
+	case *[]string:
+		*value = []string{}
+		for _, v := range strings.Split(raw, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				*value = append(*value, v)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Print writes the resolved config with secrets redacted
+func (cfg *Config) Print(w io.Writer) {
+	fmt.Fprintf(w, "Configuration:\n")
+	for _, o := range cfg.options() {
+		var value string
+
+		switch v := o.value.(type) {
+		case *string:
+			value = *v
+			if o.secret {
+				value = redact(value)
+			}
+		case *uint64:
+			value = strconv.FormatUint(*v, 10)
+		case *bool:
+			value = strconv.FormatBool(*v)
+		case *[]string:
+			value = strings.Join(*v, ",")
+		}
+
+		if value != "" {
+			fmt.Fprintf(w, "  %s = %s\n", o.name, value)
+		}
+	}
+
+	if cfg.Networks != nil {
+		fmt.Fprintf(w, "  networks.bridge = %s (%d signatures required)\n", redactProvider(cfg.Networks.Bridge.WebSocketProvider), cfg.Networks.Bridge.RequiredSignatures)
+		fmt.Fprintf(w, "  networks.exchange = %s (%d signatures required)\n", redactProvider(cfg.Networks.Exchange.WebSocketProvider), cfg.Networks.Exchange.RequiredSignatures)
+		fmt.Fprintf(w, "  networks.authorities = %d\n", len(cfg.Networks.Authorities))
+	}
+
+	if len(cfg.Markets) > 0 {
+		fmt.Fprintf(w, "  markets = %d\n", len(cfg.Markets))
+	}
+
+	fmt.Fprintf(w, "\n")
+}
@@ -0,0 +1,139 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"reflect"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Interval of config file modification checks
+const watchInterval = 2 * time.Second
+
+// Reloader reloads config on SIGHUP or when config file changes and passes
+// it to subscribers. Changes of settings that need a restart are rejected.
+type Reloader struct {
+	mu          sync.Mutex
+	service     string
+	args        []string
+	current     *Config
+	subscribers []func(*Config)
+}
+
+// NewReloader creates a reloader for config loaded with the same service and args
+func NewReloader(service string, args []string, cfg *Config) *Reloader {
+	return &Reloader{
+		service: service,
+		args:    args,
+		current: cfg,
+	}
+}
+
+// OnReload registers a function called with every accepted config
+func (r *Reloader) OnReload(fn func(*Config)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.subscribers = append(r.subscribers, fn)
+}
+
+// Reload loads config again and applies it if only reloadable settings changed
+func (r *Reloader) Reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cfg, _, err := Load(r.service, r.args)
+	if err != nil {
+		return err
+	}
+
+	if changed := restartRequiredChanges(r.current, cfg); len(changed) > 0 {
+		return fmt.Errorf("Configuration not reloaded, restart is required to change %s", strings.Join(changed, ", "))
+	}
+
+	r.current = cfg
+	for _, fn := range r.subscribers {
+		fn(cfg)
+	}
+
+	return nil
+}
+
+// Watch reloads config on SIGHUP and when config file is modified
+func (r *Reloader) Watch() {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+
+	modTime := r.fileModTime()
+
+	go func() {
+		ticker := time.NewTicker(watchInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-hup:
+				fmt.Printf("Received SIGHUP, reloading configuration...\n")
+			case <-ticker.C:
+				t := r.fileModTime()
+				if t.Equal(modTime) {
+					continue
+				}
+				modTime = t
+				fmt.Printf("Configuration file changed, reloading...\n")
+			}
+
+			if err := r.Reload(); err != nil {
+				fmt.Println("CONFIG_RELOAD", err)
+				continue
+			}
+
+			fmt.Printf("Configuration reloaded\n")
+		}
+	}()
+}
+
+func (r *Reloader) fileModTime() time.Time {
+	r.mu.Lock()
+	filePath := r.current.FilePath
+	r.mu.Unlock()
+
+	if filePath == "" {
+		return time.Time{}
+	}
+
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return info.ModTime()
+}
+
+// restartRequiredChanges returns names of changed settings that cannot be reloaded
+func restartRequiredChanges(old, new *Config) []string {
+	var changed []string
+
+	oldOptions := old.options()
+	for i, o := range new.options() {
+		if reloadableOptions[o.name] {
+			continue
+		}
+		if !reflect.DeepEqual(reflect.ValueOf(o.value).Elem().Interface(), reflect.ValueOf(oldOptions[i].value).Elem().Interface()) {
+			changed = append(changed, o.name)
+		}
+	}
+
+	if !reflect.DeepEqual(old.Networks, new.Networks) {
+		changed = append(changed, "networksFile contents")
+	}
+	if !reflect.DeepEqual(old.Contracts, new.Contracts) {
+		changed = append(changed, "contractsFile contents")
+	}
+
+	return changed
+}
@@ -0,0 +1,318 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"hameid.net/cdex/dex/internal/utils"
+	"hameid.net/cdex/dex/internal/wrappers"
+)
+
+// Validate checks that settings needed by the service are present and well formed.
+// All problems are reported at once.
+func (cfg *Config) Validate(service string) error {
+	var errs []string
+	fail := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Sprintf(format, args...))
+	}
+
+	needsDB := service == ServiceApp || service == ServiceRelayer || service == ServiceRPCServer
+	needsRedis := service == ServiceApp || service == ServiceSocketServer || service == ServiceRelayer || service == ServiceRPCServer
+	needsChains := service == ServiceRelayer || service == ServiceValidator
+
+	if needsDB {
+		if cfg.Database.ConnectionString == "" {
+			fail("db.connectionString is required")
+		}
+	}
+
+	if needsRedis {
+		if _, _, err := net.SplitHostPort(cfg.Redis.Host); err != nil {
+			fail("redis.host must be host:port, got %q", cfg.Redis.Host)
+		}
+	}
+
+	switch service {
+	case ServiceApp:
+		checkPort(fail, "app.port", cfg.App.Port)
+		for i, origin := range cfg.App.CORSOrigins {
+			if origin != "*" {
+				checkURL(fail, fmt.Sprintf("app.corsOrigins[%d]", i), origin, "http", "https")
+			}
+		}
+		cfg.validatePageSize(fail)
+		cfg.validateMarkets(fail)
+		checkRateLimit(fail, "app.rateLimits.ip", cfg.App.RateLimits.IP)
+		checkRateLimit(fail, "app.rateLimits.address", cfg.App.RateLimits.Address)
+		checkRateLimit(fail, "app.rateLimits.heavy", cfg.App.RateLimits.Heavy)
+		if cfg.Auth.SessionTTL == 0 {
+			fail("auth.sessionTTL must be greater than 0")
+		}
+		if cfg.Auth.NonceTTL == 0 {
+			fail("auth.nonceTTL must be greater than 0")
+		}
+
+	case ServiceRPCServer:
+		checkPort(fail, "rpcServer.port", cfg.RPCServer.Port)
+		checkPort(fail, "rpcServer.metricsPort", cfg.RPCServer.MetricsPort)
+		if cfg.RPCServer.MetricsPort == cfg.RPCServer.Port {
+			fail("rpcServer.metricsPort must differ from rpcServer.port")
+		}
+		cfg.validatePageSize(fail)
+		cfg.validateMarkets(fail)
+
+	case ServiceSocketServer:
+		checkPort(fail, "socketServer.port", cfg.SocketServer.Port)
+		if cfg.SocketServer.WebappHost == "" {
+			fail("socketServer.webappHost is required")
+		}
+
+	case ServiceValidator:
+		checkKey(fail, "validator.key", &cfg.Validator.Key, true)
+		if cfg.Validator.LimitsFile != "" && cfg.Validator.LimitsStateFile == "" {
+			fail("validator.limitsStateFile is required when validator.limitsFile is set")
+		}
+		checkPort(fail, "validator.healthPort", cfg.Validator.HealthPort)
+		if cfg.Validator.MessageVersion > 1 {
+			fail("validator.messageVersion %d is not supported", cfg.Validator.MessageVersion)
+		}
+		// Legacy messages carry no domain to sign as typed data
+		if cfg.Validator.TypedSignatures && cfg.Validator.MessageVersion == 0 {
+			fail("validator.typedSignatures requires validator.messageVersion 1")
+		}
+
+	case ServiceRelayer:
+		checkKey(fail, "relayer.matcher", &cfg.Relayer.Matcher, true)
+		checkKey(fail, "relayer.withdrawRelay", &cfg.Relayer.WithdrawRelay, false)
+		checkPort(fail, "relayer.healthPort", cfg.Relayer.HealthPort)
+	}
+
+	if needsChains || service == ServiceApp {
+		if cfg.Networks == nil {
+			fail("networksFile is required")
+		} else {
+			cfg.validateNetworks(fail, needsChains)
+		}
+	}
+
+	if needsChains {
+		if cfg.Health.MaxBlockAge == 0 {
+			fail("health.maxBlockAge must be greater than 0")
+		}
+		if cfg.Contracts == nil {
+			fail("contractsFile is required")
+		} else {
+			cfg.validateContracts(fail)
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("Invalid %s configuration:\n  %s", service, strings.Join(errs, "\n  "))
+	}
+
+	return nil
+}
+
+func (cfg *Config) validatePageSize(fail func(string, ...interface{})) {
+	if cfg.App.PageSize == 0 || cfg.App.PageSize > cfg.App.MaxPageSize {
+		fail("app.pageSize must be between 1 and app.maxPageSize (%d), got %d", cfg.App.MaxPageSize, cfg.App.PageSize)
+	}
+}
+
+func (cfg *Config) validateMarkets(fail func(string, ...interface{})) {
+	seen := make(map[[2]common.Address]bool, len(cfg.Markets))
+	for i, market := range cfg.Markets {
+		if market.Token == market.Base {
+			fail("markets[%d] token and base must differ", i)
+		}
+
+		pair := [2]common.Address{market.Token, market.Base}
+		if seen[pair] {
+			fail("markets[%d] is listed twice", i)
+		}
+		seen[pair] = true
+	}
+}
+
+func (cfg *Config) validateNetworks(fail func(string, ...interface{}), needsProviders bool) {
+	networks := cfg.Networks
+
+	if needsProviders {
+		checkURL(fail, "networks.bridge.provider", networks.Bridge.Provider, "http", "https")
+		checkURL(fail, "networks.bridge.wsProvider", networks.Bridge.WebSocketProvider, "ws", "wss")
+		checkURL(fail, "networks.exchange.provider", networks.Exchange.Provider, "http", "https")
+		checkURL(fail, "networks.exchange.wsProvider", networks.Exchange.WebSocketProvider, "ws", "wss")
+		checkGas(fail, "networks.bridge.gas", &networks.Bridge.Gas)
+		checkGas(fail, "networks.exchange.gas", &networks.Exchange.Gas)
+	}
+
+	if len(networks.Authorities) == 0 {
+		fail("networks.authorities must not be empty")
+	}
+
+	seen := make(map[common.Address]bool, len(networks.Authorities))
+	for i, authority := range networks.Authorities {
+		if authority == (common.Address{}) {
+			fail("networks.authorities[%d] is the zero address", i)
+		}
+		if seen[authority] {
+			fail("networks.authorities[%d] %s is listed twice", i, authority.Hex())
+		}
+		seen[authority] = true
+	}
+
+	checkRequiredSignatures(fail, "networks.bridge", networks.Bridge.RequiredSignatures, len(networks.Authorities))
+	checkRequiredSignatures(fail, "networks.exchange", networks.Exchange.RequiredSignatures, len(networks.Authorities))
+
+	checkAddress(fail, "networks.feeAccount", networks.FeeAccount)
+	checkAddress(fail, "networks.orderMatcher", networks.OrderMatcher)
+}
+
+func (cfg *Config) validateContracts(fail func(string, ...interface{})) {
+	contracts := cfg.Contracts
+
+	checkAddress(fail, "contracts.bridge.address", contracts.Bridge.Address.Address)
+	checkAddress(fail, "contracts.exchange.address", contracts.Exchange.Address.Address)
+	checkAddress(fail, "contracts.orderbook.address", contracts.Orderbook.Address.Address)
+	checkAddress(fail, "contracts.ordermatch.address", contracts.OrderMatcher.Address.Address)
+
+	topics := []struct {
+		name  string
+		topic wrappers.Hash
+	}{
+		{"bridge.topics.Deposit", contracts.Bridge.Topics.Deposit},
+		{"bridge.topics.Withdraw", contracts.Bridge.Topics.Withdraw},
+		{"exchange.topics.Deposit", contracts.Exchange.Topics.Deposit},
+		{"exchange.topics.Withdraw", contracts.Exchange.Topics.Withdraw},
+		{"exchange.topics.DepositConfirmation", contracts.Exchange.Topics.DepositConfirmation},
+		{"exchange.topics.WithdrawSignatureSubmitted", contracts.Exchange.Topics.WithdrawSignatureSubmitted},
+		{"exchange.topics.BalanceUpdate", contracts.Exchange.Topics.BalanceUpdate},
+		{"exchange.topics.ReadyToWithdraw", contracts.Exchange.Topics.ReadyToWithdraw},
+		{"orderbook.topics.PlaceBuyOrder", contracts.Orderbook.Topics.PlaceBuyOrder},
+		{"orderbook.topics.PlaceSellOrder", contracts.Orderbook.Topics.PlaceSellOrder},
+		{"orderbook.topics.CancelOrder", contracts.Orderbook.Topics.CancelOrder},
+		{"ordermatch.topics.Trade", contracts.OrderMatcher.Topics.Trade},
+		{"ordermatch.topics.OrderFilledVolumeUpdate", contracts.OrderMatcher.Topics.OrderFilledVolumeUpdate},
+	}
+	for _, t := range topics {
+		if t.topic.Hash == (common.Hash{}) {
+			fail("contracts.%s is missing", t.name)
+		}
+	}
+}
+
+func checkKey(fail func(string, ...interface{}), name string, key *KeyConfig, required bool) {
+	if !key.IsSet() {
+		if required {
+			fail("%s.keystoreFile or %s.signerURL is required", name, name)
+		}
+		return
+	}
+
+	if key.SignerURL != "" {
+		checkURL(fail, name+".signerURL", key.SignerURL, "http", "https", "ws", "wss")
+		if key.SignerAddress != "" && !common.IsHexAddress(key.SignerAddress) {
+			fail("%s.signerAddress is not an address", name)
+		}
+		return
+	}
+
+	if key.PasswordFile == "" {
+		fail("%s.passwordFile is required with a keystore", name)
+	}
+}
+
+func checkGas(fail func(string, ...interface{}), name string, gas *utils.GasConfig) {
+	switch gas.Strategy {
+	case "", utils.GasStrategyFixed, utils.GasStrategySuggest:
+	case utils.GasStrategyEIP1559:
+		if gas.MaxFeePerGas == nil {
+			fail("%s.maxFeePerGas is required with eip1559 strategy", name)
+		}
+	default:
+		fail("%s.strategy must be one of fixed, suggest, eip1559, got %q", name, gas.Strategy)
+	}
+
+	if gas.LimitHeadroom > 500 {
+		fail("%s.limitHeadroom must be a percentage up to 500, got %d", name, gas.LimitHeadroom)
+	}
+}
+
+func checkRequiredSignatures(fail func(string, ...interface{}), name string, required uint64, authorities int) {
+	if required == 0 || required > uint64(authorities) {
+		fail("%s.requiredSignatures must be between 1 and %d, got %d", name, authorities, required)
+	}
+}
+
+func checkURL(fail func(string, ...interface{}), name string, value string, schemes ...string) {
+	u, err := url.Parse(value)
+	if err != nil || u.Host == "" {
+		fail("%s must be a URL, got %q", name, value)
+		return
+	}
+
+	for _, scheme := range schemes {
+		if u.Scheme == scheme {
+			return
+		}
+	}
+
+	fail("%s must use one of %s, got %q", name, strings.Join(schemes, ", "), u.Scheme)
+}
+
+func checkAddress(fail func(string, ...interface{}), name string, address common.Address) {
+	if address == (common.Address{}) {
+		fail("%s must be a non-zero address", name)
+	}
+}
+
+func checkPort(fail func(string, ...interface{}), name string, port uint64) {
+	if port == 0 || port > 65535 {
+		fail("%s must be between 1 and 65535, got %d", name, port)
+	}
+}
+
+func checkRateLimit(fail func(string, ...interface{}), name string, limit RateLimitConfig) {
+	if limit.Rate > 0 && limit.Burst == 0 {
+		fail("%s.burst must be greater than 0 when %s.rate is set", name, name)
+	}
+}
+
+// redact hides a secret, keeping the non-secret parts of a URL
+func redact(value string) string {
+	if value == "" {
+		return ""
+	}
+
+	if u, err := url.Parse(value); err == nil && u.Scheme != "" && u.Host != "" {
+		if u.User != nil {
+			u.User = url.UserPassword(u.User.Username(), "xxxxx")
+		}
+		if query := u.Query(); query.Get("password") != "" {
+			query.Set("password", "xxxxx")
+			u.RawQuery = query.Encode()
+		}
+		return u.String()
+	}
+
+	return "xxxxx"
+}
+
+// redactProvider hides credentials of a node URL, which providers put in the
+// user info, path or query, keeping scheme and host
+func redactProvider(value string) string {
+	u, err := url.Parse(value)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return redact(value)
+	}
+
+	redacted := (&url.URL{Scheme: u.Scheme, Host: u.Host}).String()
+	if u.User != nil || (u.Path != "" && u.Path != "/") || u.RawQuery != "" {
+		redacted += "/xxxxx"
+	}
+
+	return redacted
+}
@@ -0,0 +1,66 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/go-redis/redis"
+	"hameid.net/cdex/dex/internal/store"
+)
+
+var errNotConnected = errors.New("Not connected")
+
+// Postgres checks that the database answers
+func Postgres(dataStore *store.DataStore) Check {
+	return func(ctx context.Context) (map[string]interface{}, error) {
+		if dataStore.DB == nil {
+			return nil, errNotConnected
+		}
+
+		if err := dataStore.Ping(ctx); err != nil {
+			return nil, err
+		}
+
+		stats := dataStore.DB.Stats()
+		return map[string]interface{}{
+			"open_connections": stats.OpenConnections,
+		}, nil
+	}
+}
+
+// Redis checks that redis answers
+func Redis(client *redis.Client) Check {
+	return func(ctx context.Context) (map[string]interface{}, error) {
+		if err := client.WithContext(ctx).Ping().Err(); err != nil {
+			return nil, err
+		}
+
+		return nil, nil
+	}
+}
+
+// Chain checks that the node of a chain answers and that its head block is
+// at most maxBlockAge old
+func Chain(client *ethclient.Client, maxBlockAge time.Duration) Check {
+	return func(ctx context.Context) (map[string]interface{}, error) {
+		header, err := client.HeaderByNumber(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		age := time.Since(time.Unix(header.Time.Int64(), 0))
+		details := map[string]interface{}{
+			"block":       header.Number.Uint64(),
+			"block_age_s": int64(age / time.Second),
+		}
+
+		if age > maxBlockAge {
+			return details, fmt.Errorf("Head block %d is %s old", header.Number.Uint64(), age.Truncate(time.Second))
+		}
+
+		return details, nil
+	}
+}
@@ -0,0 +1,191 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"hameid.net/cdex/dex/internal/helpers"
+)
+
+// Paths of health endpoints
+const (
+	LivenessPath  = "/healthz"
+	ReadinessPath = "/readyz"
+)
+
+// Statuses of reports and checks
+const (
+	StatusOK          = "ok"
+	StatusUnavailable = "unavailable"
+)
+
+// Time given to all checks of a readiness request
+const checkTimeout = 5 * time.Second
+
+// Check tells if a dependency of the service can be used. Details describe
+// the state of the dependency and are reported even if it fails.
+type Check func(ctx context.Context) (map[string]interface{}, error)
+
+// CheckResult is the outcome of a check
+type CheckResult struct {
+	Status    string                 `json:"status"`
+	Error     string                 `json:"error,omitempty"`
+	LatencyMS float64                `json:"latency_ms"`
+	Details   map[string]interface{} `json:"details,omitempty"`
+}
+
+// Report is the response of health endpoints
+type Report struct {
+	Status  string                 `json:"status"`
+	Service string                 `json:"service"`
+	Uptime  int64                  `json:"uptime_s"`
+	Checks  map[string]CheckResult `json:"checks,omitempty"`
+}
+
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+// Health serves liveness and readiness of a service. The service is live
+// while it serves requests and ready when all checks pass.
+type Health struct {
+	service   string
+	startedAt time.Time
+
+	mu     sync.RWMutex
+	checks []namedCheck
+}
+
+// AddCheck adds a check run on readiness requests
+func (health *Health) AddCheck(name string, check Check) {
+	health.mu.Lock()
+	defer health.mu.Unlock()
+
+	health.checks = append(health.checks, namedCheck{name, check})
+}
+
+// Ready runs all checks at once and reports their results
+func (health *Health) Ready(ctx context.Context) *Report {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	health.mu.RLock()
+	checks := health.checks
+	health.mu.RUnlock()
+
+	report := health.report()
+	report.Checks = make(map[string]CheckResult, len(checks))
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for _, c := range checks {
+		wg.Add(1)
+		go func(c namedCheck) {
+			defer wg.Done()
+			result := runCheck(ctx, c.check)
+
+			mu.Lock()
+			report.Checks[c.name] = result
+			mu.Unlock()
+		}(c)
+	}
+
+	wg.Wait()
+
+	for _, result := range report.Checks {
+		if result.Status != StatusOK {
+			report.Status = StatusUnavailable
+		}
+	}
+
+	return report
+}
+
+func runCheck(ctx context.Context, check Check) (result CheckResult) {
+	start := time.Now()
+	defer func() {
+		result.LatencyMS = float64(time.Since(start).Nanoseconds()) / 1e6
+		if r := recover(); r != nil {
+			result.Status = StatusUnavailable
+			result.Error = fmt.Sprint(r)
+		}
+	}()
+
+	details, err := check(ctx)
+	result.Details = details
+	result.Status = StatusOK
+	if err != nil {
+		result.Status = StatusUnavailable
+		result.Error = err.Error()
+	}
+
+	return result
+}
+
+func (health *Health) report() *Report {
+	return &Report{
+		Status:  StatusOK,
+		Service: health.service,
+		Uptime:  int64(time.Since(health.startedAt) / time.Second),
+	}
+}
+
+// Wrap serves health endpoints and passes other requests to next
+func (health *Health) Wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case LivenessPath:
+			respond(w, http.StatusOK, health.report())
+
+		case ReadinessPath:
+			report := health.Ready(r.Context())
+			status := http.StatusOK
+			if report.Status != StatusOK {
+				status = http.StatusServiceUnavailable
+			}
+			respond(w, status, report)
+
+		default:
+			next.ServeHTTP(w, r)
+		}
+	})
+}
+
+// Serve serves health endpoints on the port and passes other requests to
+// next, for services without an HTTP server. The returned server is shut
+// down by the caller.
+func (health *Health) Serve(port uint64, next http.Handler) *http.Server {
+	server := &http.Server{
+		Addr:    fmt.Sprintf(":%d", port),
+		Handler: health.Wrap(next),
+	}
+
+	fmt.Printf("Serving health checks on address %s\n", server.Addr)
+
+	go func() {
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Println("HEALTH", err)
+		}
+	}()
+
+	return server
+}
+
+func respond(w http.ResponseWriter, status int, report *Report) {
+	w.Header().Set("Cache-Control", "no-store")
+	helpers.RespondWithJSON(w, status, report)
+}
+
+// NewHealth creates health of the service without checks
+func NewHealth(service string) *Health {
+	return &Health{
+		service:   service,
+		startedAt: time.Now(),
+	}
+}
@@ -0,0 +1,59 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestReadiness(t *testing.T) {
+	health := NewHealth("test")
+	health.AddCheck("ok", func(ctx context.Context) (map[string]interface{}, error) {
+		return map[string]interface{}{"block": 1}, nil
+	})
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+	handler := health.Wrap(next)
+
+	get := func(path string) (int, *Report) {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+
+		var report Report
+		json.Unmarshal(w.Body.Bytes(), &report)
+		return w.Code, &report
+	}
+
+	if code, report := get(ReadinessPath); code != http.StatusOK || report.Checks["ok"].Status != StatusOK {
+		t.Errorf("ready: got %d %+v", code, report)
+	}
+
+	health.AddCheck("failing", func(ctx context.Context) (map[string]interface{}, error) {
+		return nil, errors.New("down")
+	})
+	health.AddCheck("panicking", func(ctx context.Context) (map[string]interface{}, error) {
+		panic("not connected")
+	})
+
+	code, report := get(ReadinessPath)
+	if code != http.StatusServiceUnavailable || report.Status != StatusUnavailable {
+		t.Errorf("not ready: got %d %+v", code, report)
+	}
+	if report.Checks["failing"].Error != "down" || report.Checks["panicking"].Status != StatusUnavailable {
+		t.Errorf("unexpected checks %+v", report.Checks)
+	}
+
+	// Liveness does not depend on checks
+	if code, report := get(LivenessPath); code != http.StatusOK || report.Service != "test" || report.Checks != nil {
+		t.Errorf("live: got %d %+v", code, report)
+	}
+
+	if code, _ := get("/other"); code != http.StatusTeapot {
+		t.Errorf("other paths are not passed on, got %d", code)
+	}
+}
@@ -0,0 +1,110 @@
+package helpers
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"net/http"
+	"regexp"
+)
+
+// Error codes of API responses. Codes are stable, messages may change.
+const (
+	CodeInvalidRequest   = "invalid_request"
+	CodeInvalidParameter = "invalid_parameter"
+	CodeMissingParameter = "missing_parameter"
+	CodeUnauthorized     = "unauthorized"
+	CodeForbidden        = "forbidden"
+	CodeNotFound         = "not_found"
+	CodeRateLimited      = "rate_limited"
+	CodeInternal         = "internal_error"
+)
+
+// RequestIDHeader carries the correlation ID of a request
+const RequestIDHeader = "X-Request-ID"
+
+// APIError is an error reported to API clients
+type APIError struct {
+	Status    int    `json:"-"`
+	Code      string `json:"code"`
+	Message   string `json:"error"`
+	Field     string `json:"field,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
+}
+
+func (err *APIError) Error() string {
+	return err.Message
+}
+
+// NewError returns an error responded with the HTTP status
+func NewError(status int, code string, message string) *APIError {
+	return &APIError{Status: status, Code: code, Message: message}
+}
+
+// InvalidParam returns error of a parameter with invalid value
+func InvalidParam(field string, message string) *APIError {
+	return &APIError{Status: http.StatusBadRequest, Code: CodeInvalidParameter, Message: message, Field: field}
+}
+
+// MissingParam returns error of a required parameter that is not set
+func MissingParam(field string, message string) *APIError {
+	return &APIError{Status: http.StatusBadRequest, Code: CodeMissingParameter, Message: message, Field: field}
+}
+
+// NotFound returns error of a resource that does not exist
+func NotFound(message string) *APIError {
+	return &APIError{Status: http.StatusNotFound, Code: CodeNotFound, Message: message}
+}
+
+type requestIDContextKey struct{}
+
+// Request IDs set by clients or proxies are kept if they look sane
+var requestIDPattern = regexp.MustCompile(`^[0-9A-Za-z._-]{1,64}$`)
+
+// RequestID gives every request a correlation ID, taken from the
+// X-Request-ID header if set, and returns it in the same header
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if !requestIDPattern.MatchString(id) {
+			id = newRequestID()
+		}
+
+		w.Header().Set(RequestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), requestIDContextKey{}, id)))
+	})
+}
+
+// GetRequestID returns correlation ID of the request
+func GetRequestID(r *http.Request) string {
+	id, _ := r.Context().Value(requestIDContextKey{}).(string)
+	return id
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
+
+// ToAPIError returns err as reported to clients. Errors that are not
+// APIError are internal failures; they are logged with the request ID and
+// their details are not exposed.
+func ToAPIError(r *http.Request, err error) *APIError {
+	var resp APIError
+
+	if apiErr, ok := err.(*APIError); ok {
+		resp = *apiErr
+	} else {
+		resp = APIError{Status: http.StatusInternalServerError, Code: CodeInternal, Message: "Internal error"}
+	}
+
+	resp.RequestID = GetRequestID(r)
+	if resp.Status >= http.StatusInternalServerError {
+		log.Printf("ERROR request %s %s %s: %s", resp.RequestID, r.Method, r.URL.Path, err)
+	}
+
+	return &resp
+}
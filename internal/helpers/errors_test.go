@@ -0,0 +1,45 @@
+package helpers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestToAPIError(t *testing.T) {
+	var requestID string
+	var internal, invalid *APIError
+	errInvalid := InvalidParam("token", "Invalid value for `token` parameter")
+
+	handler := RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID = GetRequestID(r)
+		internal = ToAPIError(r, errors.New("pq: connection refused"))
+		invalid = ToAPIError(r, errInvalid)
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/orders", nil))
+
+	if requestID == "" || w.Header().Get(RequestIDHeader) != requestID {
+		t.Fatalf("request ID %q not returned in header", requestID)
+	}
+
+	if internal.Status != http.StatusInternalServerError || internal.Code != CodeInternal || internal.Message != "Internal error" || internal.RequestID != requestID {
+		t.Errorf("unexpected internal error %+v", internal)
+	}
+
+	if invalid.Status != http.StatusBadRequest || invalid.Field != "token" || invalid.RequestID != requestID {
+		t.Errorf("unexpected invalid parameter error %+v", invalid)
+	}
+	if errInvalid.RequestID != "" {
+		t.Error("request ID was set on shared error")
+	}
+
+	r := httptest.NewRequest("GET", "/orders", nil)
+	r.Header.Set(RequestIDHeader, "upstream-id")
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+	if requestID != "upstream-id" {
+		t.Errorf("request ID of client not kept, got %q", requestID)
+	}
+}
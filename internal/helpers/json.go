@@ -5,9 +5,10 @@ import (
 	"net/http"
 )
 
-// RespondWithError returns error message as JSON
-func RespondWithError(w http.ResponseWriter, code int, message string) {
-	RespondWithJSON(w, code, map[string]string{"error": message})
+// RespondWithError returns error as JSON, see ToAPIError
+func RespondWithError(w http.ResponseWriter, r *http.Request, err error) {
+	apiErr := ToAPIError(r, err)
+	RespondWithJSON(w, apiErr.Status, apiErr)
 }
 
 // RespondWithJSON returns the payload as JSON
@@ -1,28 +1,22 @@
 package helpers
 
 import (
-	"errors"
+	"fmt"
 	"net/http"
 	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
 )
 
-// ErrInvalidAddress thrown if not a valid address
-var ErrInvalidAddress = errors.New("`%s` is not a valid address")
-
-// ErrMissingAddress thrown if address missing
-var ErrMissingAddress = errors.New("Address parameter `%s` is required")
-
 // GetAddressQueryParam sanitizes and returns address
 func GetAddressQueryParam(r *http.Request, key string) (*common.Address, error) {
 	val := strings.TrimSpace(r.FormValue(key))
 	if len(val) == 0 {
-		return nil, ErrMissingAddress
+		return nil, MissingParam(key, fmt.Sprintf("Missing `%s` parameter", key))
 	}
 
 	if !common.IsHexAddress(val) {
-		return nil, ErrInvalidAddress
+		return nil, InvalidParam(key, fmt.Sprintf("Invalid value for `%s` parameter", key))
 	}
 
 	addr := common.HexToAddress(val)
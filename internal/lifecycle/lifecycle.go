@@ -0,0 +1,56 @@
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Time given to a service to drain once it is asked to shut down
+const ShutdownTimeout = 30 * time.Second
+
+// SignalContext returns a context cancelled on the first interrupt or
+// terminate signal. The process exits on a second signal without draining.
+func SignalContext() context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	c := make(chan os.Signal, 2)
+	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		sig := <-c
+		fmt.Printf("\nReceived signal %s, shutting down...\n", sig)
+		cancel()
+
+		sig = <-c
+		fmt.Printf("\nReceived signal %s again, exiting\n", sig)
+		os.Exit(1)
+	}()
+
+	return ctx
+}
+
+// ShutdownContext returns a context limiting how long a service drains
+func ShutdownContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), ShutdownTimeout)
+}
+
+// Wait waits for wg or until ctx is done, in which case the error of ctx is
+// returned
+func Wait(ctx context.Context, wg *sync.WaitGroup) error {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,172 @@
+package lifecycle
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Minimum interval between checkpoints saved while logs are processed
+var checkpointInterval = 10 * time.Second
+
+// Time given to fetch the rest of the current block on shutdown
+var finishBlockTimeout = 10 * time.Second
+
+// ProcessLogs subscribes to logs of the query and passes them to handle until
+// ctx is done or the subscription fails. If checkpoint is set, logs emitted
+// after that block while nothing was subscribed are handled first.
+//
+// save is called with the last block whose logs were all handled as blocks
+// complete, at most once per checkpointInterval, and once more on return.
+// On shutdown the rest of the current block is fetched and handled so that
+// it is complete too.
+func ProcessLogs(ctx context.Context, filterer ethereum.LogFilterer, query ethereum.FilterQuery, logs chan types.Log, checkpoint uint64, handle func(types.Log), save func(block uint64)) error {
+	// Subscribe before fetching past logs so that no block is missed between
+	sub, err := filterer.SubscribeFilterLogs(context.Background(), query, logs)
+	if err != nil {
+		return err
+	}
+	defer sub.Unsubscribe()
+
+	p := &logProcessor{
+		filterer:  filterer,
+		query:     query,
+		handle:    handle,
+		save:      save,
+		completed: checkpoint,
+		saved:     checkpoint,
+		savedAt:   time.Now(),
+	}
+	defer p.saveCompleted()
+
+	var backfilled uint64
+	if checkpoint > 0 {
+		pastQuery := query
+		pastQuery.FromBlock = new(big.Int).SetUint64(checkpoint + 1)
+		pastQuery.ToBlock = nil
+
+		past, err := filterer.FilterLogs(ctx, pastQuery)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		for _, vLog := range past {
+			if ctx.Err() != nil {
+				p.finishBlock()
+				return nil
+			}
+			p.process(vLog)
+		}
+
+		// Past logs are complete up to the last block they were found in
+		p.complete(p.current)
+		backfilled = p.current
+	}
+
+	for {
+		select {
+		case err := <-sub.Err():
+			return err
+
+		case <-ctx.Done():
+			p.finishBlock()
+			return nil
+
+		case vLog := <-logs:
+			// Logs can still be selected after ctx is done
+			if ctx.Err() != nil {
+				p.finishBlock()
+				return nil
+			}
+			// Already handled with past logs
+			if vLog.BlockNumber <= backfilled {
+				continue
+			}
+			p.process(vLog)
+		}
+	}
+}
+
+type logProcessor struct {
+	filterer ethereum.LogFilterer
+	query    ethereum.FilterQuery
+	handle   func(types.Log)
+	save     func(block uint64)
+
+	// Block being handled and indexes of its handled logs
+	current uint64
+	handled map[uint]bool
+
+	completed uint64
+	saved     uint64
+	savedAt   time.Time
+}
+
+// process handles the log. Logs of a block are delivered together, once a
+// later block shows up all blocks before it are complete.
+func (p *logProcessor) process(vLog types.Log) {
+	if vLog.BlockNumber != p.current {
+		if vLog.BlockNumber > 0 {
+			p.complete(vLog.BlockNumber - 1)
+		}
+		p.current = vLog.BlockNumber
+		p.handled = make(map[uint]bool)
+	}
+
+	if p.handled[vLog.Index] {
+		return
+	}
+	p.handled[vLog.Index] = true
+	p.handle(vLog)
+}
+
+func (p *logProcessor) complete(block uint64) {
+	if block <= p.completed {
+		return
+	}
+	p.completed = block
+
+	if time.Since(p.savedAt) >= checkpointInterval {
+		p.saveCompleted()
+	}
+}
+
+func (p *logProcessor) saveCompleted() {
+	if p.completed <= p.saved {
+		return
+	}
+	p.save(p.completed)
+	p.saved = p.completed
+	p.savedAt = time.Now()
+}
+
+// finishBlock handles logs of the current block that have not been delivered
+// yet, the block stays incomplete if they cannot be fetched
+func (p *logProcessor) finishBlock() {
+	if p.current == 0 || p.current <= p.completed {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), finishBlockTimeout)
+	defer cancel()
+
+	blockQuery := p.query
+	blockQuery.FromBlock = new(big.Int).SetUint64(p.current)
+	blockQuery.ToBlock = new(big.Int).SetUint64(p.current)
+
+	blockLogs, err := p.filterer.FilterLogs(ctx, blockQuery)
+	if err != nil {
+		return
+	}
+
+	for _, vLog := range blockLogs {
+		p.process(vLog)
+	}
+	p.complete(p.current)
+}
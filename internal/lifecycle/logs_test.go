@@ -0,0 +1,189 @@
+package lifecycle
+
+import (
+	"context"
+	"testing"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+type testSubscription struct {
+	err chan error
+}
+
+func (sub *testSubscription) Unsubscribe()      {}
+func (sub *testSubscription) Err() <-chan error { return sub.err }
+
+// testFilterer returns past logs and delivers live logs on subscription.
+// Pending logs are only returned when a single block is fetched.
+type testFilterer struct {
+	sub       *testSubscription
+	past      []types.Log
+	live      []types.Log
+	pending   []types.Log
+	fromBlock uint64
+}
+
+func (f *testFilterer) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	if q.ToBlock == nil {
+		f.fromBlock = q.FromBlock.Uint64()
+		return f.past, nil
+	}
+
+	var blockLogs []types.Log
+	for _, logs := range [][]types.Log{f.past, f.live, f.pending} {
+		for _, vLog := range logs {
+			if vLog.BlockNumber == q.FromBlock.Uint64() {
+				blockLogs = append(blockLogs, vLog)
+			}
+		}
+	}
+	return blockLogs, nil
+}
+
+func (f *testFilterer) SubscribeFilterLogs(ctx context.Context, q ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	for _, vLog := range f.live {
+		ch <- vLog
+	}
+	return f.sub, nil
+}
+
+func newTestFilterer(past []uint64, live []uint64) *testFilterer {
+	f := &testFilterer{sub: &testSubscription{err: make(chan error, 1)}}
+	f.past = testLogs(past)
+	f.live = testLogs(live)
+	return f
+}
+
+// testLogs returns logs of the blocks, indexed in order within each block
+func testLogs(blocks []uint64) []types.Log {
+	var logs []types.Log
+	var index uint
+	for i, block := range blocks {
+		if i > 0 && blocks[i-1] != block {
+			index = 0
+		}
+		logs = append(logs, types.Log{BlockNumber: block, Index: index})
+		index++
+	}
+	return logs
+}
+
+func TestProcessLogsFinishesCurrentBlock(t *testing.T) {
+	logs := make(chan types.Log, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	filterer := newTestFilterer(nil, []uint64{1, 1, 2})
+	// Second log of block 2 is not delivered before shutdown
+	filterer.pending = []types.Log{{BlockNumber: 2, Index: 1}}
+
+	var handled []types.Log
+	handle := func(vLog types.Log) {
+		handled = append(handled, vLog)
+		if len(handled) == 3 {
+			cancel()
+		}
+	}
+
+	var saved []uint64
+	save := func(block uint64) { saved = append(saved, block) }
+
+	err := ProcessLogs(ctx, filterer, ethereum.FilterQuery{}, logs, 0, handle, save)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(handled) != 4 || handled[3].BlockNumber != 2 || handled[3].Index != 1 {
+		t.Errorf("handled logs %v, want both logs of block 2", handled)
+	}
+	if len(saved) != 1 || saved[0] != 2 {
+		t.Errorf("saved checkpoints %v, want 2", saved)
+	}
+}
+
+func TestProcessLogsSubscriptionError(t *testing.T) {
+	logs := make(chan types.Log, 10)
+	filterer := newTestFilterer(nil, []uint64{5, 6})
+
+	handle := func(vLog types.Log) {
+		if vLog.BlockNumber == 6 {
+			filterer.sub.err <- context.Canceled
+		}
+	}
+
+	var saved []uint64
+	save := func(block uint64) { saved = append(saved, block) }
+
+	err := ProcessLogs(context.Background(), filterer, ethereum.FilterQuery{}, logs, 0, handle, save)
+	if err != context.Canceled {
+		t.Errorf("got error %v", err)
+	}
+	// Block 6 may have more logs
+	if len(saved) != 1 || saved[0] != 5 {
+		t.Errorf("saved checkpoints %v, want 5", saved)
+	}
+}
+
+func TestProcessLogsResumesFromCheckpoint(t *testing.T) {
+	logs := make(chan types.Log, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// Block 7 is both in past logs and delivered by the subscription
+	filterer := newTestFilterer([]uint64{5, 7}, []uint64{7, 8})
+
+	var handled []uint64
+	handle := func(vLog types.Log) {
+		handled = append(handled, vLog.BlockNumber)
+		if vLog.BlockNumber == 8 {
+			cancel()
+		}
+	}
+
+	var saved []uint64
+	save := func(block uint64) { saved = append(saved, block) }
+
+	err := ProcessLogs(ctx, filterer, ethereum.FilterQuery{}, logs, 4, handle, save)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filterer.fromBlock != 5 {
+		t.Errorf("past logs fetched from block %d, want 5", filterer.fromBlock)
+	}
+	if len(handled) != 3 || handled[0] != 5 || handled[1] != 7 || handled[2] != 8 {
+		t.Errorf("handled blocks %v, want 5, 7 and 8", handled)
+	}
+	if len(saved) != 1 || saved[0] != 8 {
+		t.Errorf("saved checkpoints %v, want 8", saved)
+	}
+}
+
+func TestProcessLogsSavesWhileProcessing(t *testing.T) {
+	interval := checkpointInterval
+	checkpointInterval = 0
+	defer func() { checkpointInterval = interval }()
+
+	logs := make(chan types.Log, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	filterer := newTestFilterer(nil, []uint64{1, 2, 2, 3})
+
+	var saved []uint64
+	handle := func(vLog types.Log) {
+		if vLog.BlockNumber == 3 {
+			// Blocks before were saved without waiting for shutdown
+			if len(saved) != 2 || saved[0] != 1 || saved[1] != 2 {
+				t.Errorf("saved checkpoints %v before shutdown, want 1 and 2", saved)
+			}
+			cancel()
+		}
+	}
+	save := func(block uint64) { saved = append(saved, block) }
+
+	err := ProcessLogs(ctx, filterer, ethereum.FilterQuery{}, logs, 0, handle, save)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(saved) != 3 || saved[2] != 3 {
+		t.Errorf("saved checkpoints %v, want 1, 2 and 3", saved)
+	}
+}
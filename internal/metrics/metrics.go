@@ -0,0 +1,177 @@
+package metrics
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Path of the metrics endpoint
+const Path = "/metrics"
+
+// Namespace of all metrics of the exchange
+const Namespace = "cdex"
+
+// Interval between polls of the chain head
+const headPollInterval = 10 * time.Second
+
+var (
+	eventsProcessed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "events_processed_total",
+		Help:      "Contract events processed, by network and event.",
+	}, []string{"network", "event"})
+
+	eventDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Name:      "event_processing_seconds",
+		Help:      "Time taken to process contract events, by network and event.",
+		Buckets:   []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+	}, []string{"network", "event"})
+
+	chainHead = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: Namespace,
+		Name:      "chain_head_block",
+		Help:      "Latest block of the network.",
+	}, []string{"network"})
+
+	blockLag = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: Namespace,
+		Name:      "block_lag",
+		Help:      "Blocks between the head of the network and the block of the last event when it was processed.",
+	}, []string{"network"})
+
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests, by route, method and status code.",
+	}, []string{"route", "method", "code"})
+
+	httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Latency of HTTP requests, by route and method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method"})
+)
+
+func init() {
+	prometheus.MustRegister(eventsProcessed, eventDuration, chainHead, blockLag, httpRequests, httpRequestDuration)
+}
+
+// Wrap serves metrics on Path and passes other requests to next
+func Wrap(next http.Handler) http.Handler {
+	handler := promhttp.Handler()
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == Path {
+			handler.ServeHTTP(w, r)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Instrument records rate and latency of requests by the path template of
+// the route they matched. Websocket upgrades are counted, but their
+// duration is the lifetime of the connection and is not observed.
+func Instrument(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route := "unknown"
+		if current := mux.CurrentRoute(r); current != nil {
+			if template, err := current.GetPathTemplate(); err == nil {
+				route = template
+			}
+		}
+
+		labels := prometheus.Labels{"route": route}
+		handler := next
+		if !websocket.IsWebSocketUpgrade(r) {
+			handler = promhttp.InstrumentHandlerDuration(httpRequestDuration.MustCurryWith(labels), handler)
+		}
+		promhttp.InstrumentHandlerCounter(httpRequests.MustCurryWith(labels), handler).ServeHTTP(w, r)
+	})
+}
+
+// ObserveEvent records an event of the network processed since start
+func ObserveEvent(network, event string, start time.Time) {
+	eventsProcessed.WithLabelValues(network, event).Inc()
+	eventDuration.WithLabelValues(network, event).Observe(time.Since(start).Seconds())
+}
+
+// BlockLag tracks how far behind the head of a network its events are
+// processed
+type BlockLag struct {
+	network string
+	client  *ethclient.Client
+
+	mu   sync.Mutex
+	head uint64
+}
+
+// Processed records the lag of a processed event
+func (lag *BlockLag) Processed(vLog types.Log) {
+	lag.mu.Lock()
+	defer lag.mu.Unlock()
+
+	// Events can be newer than the last polled head
+	if vLog.BlockNumber > lag.head {
+		lag.head = vLog.BlockNumber
+	}
+
+	blockLag.WithLabelValues(lag.network).Set(float64(lag.head - vLog.BlockNumber))
+}
+
+func (lag *BlockLag) poll(ctx context.Context) {
+	headCtx, cancel := context.WithTimeout(ctx, headPollInterval)
+	defer cancel()
+
+	header, err := lag.client.HeaderByNumber(headCtx, nil)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Println("METRICS", lag.network, err)
+		}
+		return
+	}
+
+	head := header.Number.Uint64()
+	chainHead.WithLabelValues(lag.network).Set(float64(head))
+
+	lag.mu.Lock()
+	if head > lag.head {
+		lag.head = head
+	}
+	lag.mu.Unlock()
+}
+
+// WatchBlockLag polls the head of the network to measure lag of its events
+// until ctx is done
+func WatchBlockLag(ctx context.Context, network string, client *ethclient.Client) *BlockLag {
+	lag := &BlockLag{network: network, client: client}
+
+	go func() {
+		ticker := time.NewTicker(headPollInterval)
+		defer ticker.Stop()
+
+		for {
+			lag.poll(ctx)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return lag
+}
@@ -0,0 +1,37 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+func TestInstrumentRecordsRouteTemplate(t *testing.T) {
+	router := mux.NewRouter()
+	router.Use(Instrument)
+	router.HandleFunc("/orders/{hash}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+
+	handler := Wrap(router)
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/orders/0x01", nil))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", Path, nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d", w.Code)
+	}
+
+	body := w.Body.String()
+	for _, want := range []string{
+		`cdex_http_requests_total{code="418",method="get",route="/orders/{hash}"} 1`,
+		`cdex_http_request_duration_seconds_count{method="get",route="/orders/{hash}"} 1`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics do not contain %s", want)
+		}
+	}
+}
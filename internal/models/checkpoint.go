@@ -0,0 +1,31 @@
+package models
+
+import (
+	"hameid.net/cdex/dex/internal/store"
+)
+
+// Checkpoint is the last block of a network whose events are processed
+type Checkpoint struct {
+	Network     string `json:"network"`
+	BlockNumber uint64 `json:"block_number"`
+}
+
+// Save inserts or updates Checkpoint
+func (checkpoint *Checkpoint) Save(store *store.DataStore) error {
+	query := `INSERT INTO event_checkpoints
+		(network, block_number, updated_at)
+		VALUES ($1, $2, now())
+		ON CONFLICT (network) DO UPDATE
+		SET block_number = EXCLUDED.block_number, updated_at = EXCLUDED.updated_at`
+
+	_, err := store.DB.Exec(query, checkpoint.Network, checkpoint.BlockNumber)
+
+	return err
+}
+
+// Get returns checkpoint of the network
+func (checkpoint *Checkpoint) Get(store *store.DataStore) error {
+	query := `SELECT block_number FROM event_checkpoints WHERE network = $1`
+
+	return store.DB.QueryRow(query, checkpoint.Network).Scan(&checkpoint.BlockNumber)
+}
@@ -0,0 +1,106 @@
+package models
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidCursor thrown if cursor cannot be decoded
+var ErrInvalidCursor = errors.New("Invalid cursor")
+
+// Cursor points at the last row of a page. Rows are ordered by time and
+// then by keys, both descending.
+type Cursor struct {
+	// Microseconds since Unix epoch
+	Time int64
+	Keys []string
+}
+
+// Page is a page of results with the cursor of the next page. NextCursor is
+// nil on the last page.
+type Page struct {
+	Items      interface{} `json:"items"`
+	NextCursor *string     `json:"next_cursor"`
+}
+
+// Encode returns the opaque form of the cursor
+func (cursor *Cursor) Encode() string {
+	raw := strconv.FormatInt(cursor.Time, 10) + ":" + strings.Join(cursor.Keys, ":")
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor parses a cursor returned by Encode, expecting the given number of keys
+func DecodeCursor(encoded string, keys int) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	parts := strings.Split(string(raw), ":")
+	if len(parts) != keys+1 {
+		return nil, ErrInvalidCursor
+	}
+
+	t, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	for _, key := range parts[1:] {
+		if !isHexKey(key) {
+			return nil, ErrInvalidCursor
+		}
+	}
+
+	return &Cursor{Time: t, Keys: parts[1:]}, nil
+}
+
+// keysetCondition returns SQL condition selecting rows after the cursor.
+// Placeholders are numbered from firstArg.
+func (cursor *Cursor) keysetCondition(timeColumn string, keyColumns []string, firstArg int) (string, []interface{}) {
+	placeholders := []string{fmt.Sprintf("to_timestamp($%d::double precision / 1000000)", firstArg)}
+	args := []interface{}{cursor.Time}
+
+	for i, key := range cursor.Keys {
+		placeholders = append(placeholders, fmt.Sprintf("$%d", firstArg+1+i))
+		args = append(args, key)
+	}
+
+	columns := append([]string{timeColumn}, keyColumns...)
+
+	return fmt.Sprintf("(%s) < (%s)", strings.Join(columns, ", "), strings.Join(placeholders, ", ")), args
+}
+
+// epochMicros is SQL expression of column as microseconds since Unix epoch
+func epochMicros(column string) string {
+	return fmt.Sprintf("(extract(epoch from %s::timestamp with time zone) * 1000000)::bigint", column)
+}
+
+// newPage builds a page out of up to count+1 fetched rows
+func newPage(items interface{}, fetched int, count int, last *Cursor) *Page {
+	page := &Page{Items: items}
+
+	if fetched > count && last != nil {
+		next := last.Encode()
+		page.NextCursor = &next
+	}
+
+	return page
+}
+
+func isHexKey(key string) bool {
+	if !strings.HasPrefix(key, "0x") || len(key) > 66 {
+		return false
+	}
+
+	for _, c := range key[2:] {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", c) {
+			return false
+		}
+	}
+
+	return true
+}
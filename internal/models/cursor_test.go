@@ -0,0 +1,46 @@
+package models
+
+import (
+	"testing"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	cursor := &Cursor{
+		Time: 1539858000123456,
+		Keys: []string{"0x5c543e7ae0a1104f78406c340e9c64fd9fce5170ac709fcb44a43c35f0da4e31"},
+	}
+
+	decoded, err := DecodeCursor(cursor.Encode(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Time != cursor.Time || decoded.Keys[0] != cursor.Keys[0] {
+		t.Errorf("decoded cursor %+v does not match %+v", decoded, cursor)
+	}
+
+	if _, err := DecodeCursor(cursor.Encode(), 2); err != ErrInvalidCursor {
+		t.Errorf("expected ErrInvalidCursor for wrong number of keys, got %v", err)
+	}
+}
+
+func TestDecodeInvalidCursor(t *testing.T) {
+	for _, encoded := range []string{"", "!!!", (&Cursor{Time: 1, Keys: []string{"1' OR 1=1"}}).Encode()} {
+		if _, err := DecodeCursor(encoded, 1); err != ErrInvalidCursor {
+			t.Errorf("expected ErrInvalidCursor for %q, got %v", encoded, err)
+		}
+	}
+}
+
+func TestKeysetCondition(t *testing.T) {
+	cursor := &Cursor{Time: 10, Keys: []string{"0x01", "0x02"}}
+
+	condition, args := cursor.keysetCondition("traded_at", []string{"tx_hash", "order_hash"}, 4)
+
+	expected := "(traded_at, tx_hash, order_hash) < (to_timestamp($4::double precision / 1000000), $5, $6)"
+	if condition != expected {
+		t.Errorf("got %s", condition)
+	}
+	if len(args) != 3 || args[0] != int64(10) || args[2] != "0x02" {
+		t.Errorf("unexpected args %v", args)
+	}
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"hameid.net/cdex/dex/internal/store"
+	"hameid.net/cdex/dex/internal/wrappers"
+)
+
+// Deposit record
+type Deposit struct {
+	TxHash      *wrappers.Hash    `json:"tx_hash"`
+	Recipient   *wrappers.Address `json:"recipient"`
+	Token       *wrappers.Address `json:"token"`
+	Amount      *wrappers.BigInt  `json:"amount"`
+	DepositedAt uint64            `json:"deposited_at"`
+}
+
+// Save inserts Deposit unless its event is already recorded
+func (deposit *Deposit) Save(store *store.DataStore) error {
+	query := `INSERT INTO deposits
+		(tx_hash, recipient, token, amount, deposited_at)
+		VALUES (LOWER($1), LOWER($2), LOWER($3), $4, to_timestamp($5))
+		ON CONFLICT DO NOTHING`
+
+	_, err := store.DB.Exec(
+		query,
+		deposit.TxHash,
+		deposit.Recipient,
+		deposit.Token,
+		deposit.Amount.String(),
+		deposit.DepositedAt,
+	)
+
+	return err
+}
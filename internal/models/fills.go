@@ -0,0 +1,110 @@
+package models
+
+import (
+	"math/big"
+	"time"
+
+	"hameid.net/cdex/dex/internal/store"
+	"hameid.net/cdex/dex/internal/wrappers"
+)
+
+// OrderFill is a trade that filled part of an order
+type OrderFill struct {
+	CounterpartyOrderHash *wrappers.Hash   `json:"counterparty_order_hash"`
+	Price                 *wrappers.BigInt `json:"price"`
+	Volume                *wrappers.BigInt `json:"volume"`
+	TradedAt              int64            `json:"traded_at"`
+	TxHash                *wrappers.Hash   `json:"tx_hash"`
+}
+
+// OrderFillsResponse lists fills of an order, oldest first. Average price
+// is weighted by filled quantity and is nil if the order has no fills.
+type OrderFillsResponse struct {
+	OrderHash       *wrappers.Hash   `json:"order_hash"`
+	Fills           []OrderFill      `json:"fills"`
+	FilledVolume    *wrappers.BigInt `json:"filled_volume"`
+	RemainingVolume *wrappers.BigInt `json:"remaining_volume"`
+	AveragePrice    *wrappers.BigInt `json:"average_price"`
+}
+
+// GetFills returns trades that filled the order. Order must have been read
+// with Get.
+func (order *Order) GetFills(store *store.DataStore) (*OrderFillsResponse, error) {
+	query := `SELECT CASE WHEN buy_order_hash=LOWER($1) THEN sell_order_hash ELSE buy_order_hash END,
+		price, volume, traded_at, tx_hash
+		FROM trades
+		WHERE buy_order_hash=LOWER($1) OR sell_order_hash=LOWER($1)
+		ORDER BY traded_at ASC, tx_hash ASC`
+
+	rows, err := store.DB.Query(query, order.Hash.Hex())
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	fills := []OrderFill{}
+
+	for rows.Next() {
+		var fill OrderFill
+		var tradedAt time.Time
+
+		err := rows.Scan(
+			&fill.CounterpartyOrderHash,
+			&fill.Price,
+			&fill.Volume,
+			&tradedAt,
+			&fill.TxHash,
+		)
+
+		if err != nil {
+			return nil, err
+		}
+
+		fill.TradedAt = tradedAt.Unix()
+		fills = append(fills, fill)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	resp := &OrderFillsResponse{
+		OrderHash:       order.Hash,
+		Fills:           fills,
+		FilledVolume:    wrappers.WrapBigInt(big.NewInt(0)),
+		RemainingVolume: wrappers.WrapBigInt(new(big.Int).Sub(&order.Volume.Int, &order.VolumeFilled.Int)),
+		AveragePrice:    averageFillPrice(fills),
+	}
+
+	for _, fill := range fills {
+		resp.FilledVolume.Add(&resp.FilledVolume.Int, &fill.Volume.Int)
+	}
+
+	return resp, nil
+}
+
+// averageFillPrice returns total volume over total quantity of the fills,
+// where quantity of a fill is its volume over its price
+func averageFillPrice(fills []OrderFill) *wrappers.BigInt {
+	volume := new(big.Rat)
+	quantity := new(big.Rat)
+
+	for _, fill := range fills {
+		if fill.Price.Int.Sign() == 0 {
+			continue
+		}
+
+		v := new(big.Rat).SetInt(&fill.Volume.Int)
+		volume.Add(volume, v)
+		quantity.Add(quantity, v.Quo(v, new(big.Rat).SetInt(&fill.Price.Int)))
+	}
+
+	if quantity.Sign() == 0 {
+		return nil
+	}
+
+	average := new(big.Rat).Quo(volume, quantity)
+	return wrappers.WrapBigInt(new(big.Int).Quo(average.Num(), average.Denom()))
+}
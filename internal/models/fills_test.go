@@ -0,0 +1,27 @@
+package models
+
+import (
+	"math/big"
+	"testing"
+
+	"hameid.net/cdex/dex/internal/wrappers"
+)
+
+func TestAverageFillPrice(t *testing.T) {
+	fill := func(price, volume int64) OrderFill {
+		return OrderFill{
+			Price:  wrappers.WrapBigInt(big.NewInt(price)),
+			Volume: wrappers.WrapBigInt(big.NewInt(volume)),
+		}
+	}
+
+	if average := averageFillPrice(nil); average != nil {
+		t.Errorf("expected no average without fills, got %s", average.String())
+	}
+
+	// 2 at 10 and 6 at 20: 140 / 8
+	average := averageFillPrice([]OrderFill{fill(10, 20), fill(20, 120)})
+	if average.Int.Cmp(big.NewInt(17)) != 0 {
+		t.Errorf("expected average price 17, got %s", average.String())
+	}
+}
@@ -0,0 +1,126 @@
+package models
+
+import (
+	"math/big"
+	"time"
+
+	"hameid.net/cdex/dex/internal/store"
+	"hameid.net/cdex/dex/internal/wrappers"
+)
+
+// Ticker holds 24 hour statistics of a market. Prices are nil if the
+// market had no trades or has no open orders on that side.
+type Ticker struct {
+	Token         *wrappers.Address `json:"token"`
+	Base          *wrappers.Address `json:"base"`
+	LastPrice     *wrappers.BigInt  `json:"last_price"`
+	OpenPrice     *wrappers.BigInt  `json:"open_price"`
+	PriceChange   *wrappers.BigInt  `json:"price_change"`
+	ChangePercent float64           `json:"price_change_percent"`
+	High          *wrappers.BigInt  `json:"high"`
+	Low           *wrappers.BigInt  `json:"low"`
+	Volume        *wrappers.BigInt  `json:"volume"`
+	Trades        int64             `json:"trades"`
+	BestBid       *wrappers.BigInt  `json:"best_bid"`
+	BestAsk       *wrappers.BigInt  `json:"best_ask"`
+	UpdatedAt     int64             `json:"updated_at"`
+}
+
+// Open price is the last price before the window, or the first price in
+// it for markets that started trading within the window. Best bid and ask
+// consider the same orders as the orderbook.
+const tickersQuery = `
+	WITH pairs AS (
+		SELECT DISTINCT token, base FROM trades
+	)
+	SELECT pairs.token, pairs.base,
+		last_trade.price,
+		coalesce(previous_trade.price, day.open),
+		day.high, day.low, coalesce(day.volume, 0), day.trades,
+		bid.price, ask.price
+	  FROM pairs
+	  LEFT JOIN LATERAL (
+		SELECT price FROM trades
+		WHERE token=pairs.token AND base=pairs.base
+		ORDER BY traded_at DESC LIMIT 1
+	  ) last_trade ON TRUE
+	  LEFT JOIN LATERAL (
+		SELECT price FROM trades
+		WHERE token=pairs.token AND base=pairs.base AND traded_at < now() - interval '24 hours'
+		ORDER BY traded_at DESC LIMIT 1
+	  ) previous_trade ON TRUE
+	  LEFT JOIN LATERAL (
+		SELECT first(price, traded_at) AS open, max(price) AS high, min(price) AS low,
+			sum(volume) AS volume, count(*) AS trades
+		FROM trades
+		WHERE token=pairs.token AND base=pairs.base AND traded_at >= now() - interval '24 hours'
+	  ) day ON TRUE
+	  LEFT JOIN LATERAL (
+		SELECT max(price) AS price FROM orders
+		WHERE created_at > now() - interval '14 days' AND is_open=TRUE AND is_bid=TRUE
+			AND token=pairs.token AND base=pairs.base
+	  ) bid ON TRUE
+	  LEFT JOIN LATERAL (
+		SELECT min(price) AS price FROM orders
+		WHERE created_at > now() - interval '14 days' AND is_open=TRUE AND is_bid=FALSE
+			AND token=pairs.token AND base=pairs.base
+	  ) ask ON TRUE
+	  ORDER BY pairs.token, pairs.base;
+`
+
+// GetTickers returns tickers of every traded market
+func GetTickers(store *store.DataStore) ([]Ticker, error) {
+	rows, err := store.DB.Query(tickersQuery)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	now := time.Now().Unix()
+	tickers := []Ticker{}
+
+	for rows.Next() {
+		var ticker Ticker
+
+		err := rows.Scan(
+			&ticker.Token,
+			&ticker.Base,
+			&ticker.LastPrice,
+			&ticker.OpenPrice,
+			&ticker.High,
+			&ticker.Low,
+			&ticker.Volume,
+			&ticker.Trades,
+			&ticker.BestBid,
+			&ticker.BestAsk,
+		)
+
+		if err != nil {
+			return nil, err
+		}
+
+		ticker.setChange()
+		ticker.UpdatedAt = now
+
+		tickers = append(tickers, ticker)
+	}
+
+	return tickers, rows.Err()
+}
+
+// setChange computes price change since open price
+func (ticker *Ticker) setChange() {
+	if ticker.LastPrice == nil || ticker.OpenPrice == nil {
+		return
+	}
+
+	change := new(big.Int).Sub(&ticker.LastPrice.Int, &ticker.OpenPrice.Int)
+	ticker.PriceChange = wrappers.WrapBigInt(change)
+
+	if ticker.OpenPrice.Int.Sign() != 0 {
+		percent := new(big.Float).Quo(new(big.Float).SetInt(change), new(big.Float).SetInt(&ticker.OpenPrice.Int))
+		ticker.ChangePercent, _ = percent.Mul(percent, big.NewFloat(100)).Float64()
+	}
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"math/big"
+	"testing"
+
+	"hameid.net/cdex/dex/internal/wrappers"
+)
+
+func TestTickerChange(t *testing.T) {
+	ticker := Ticker{
+		LastPrice: wrappers.WrapBigInt(big.NewInt(90)),
+		OpenPrice: wrappers.WrapBigInt(big.NewInt(120)),
+	}
+	ticker.setChange()
+
+	if ticker.PriceChange.Int.Cmp(big.NewInt(-30)) != 0 || ticker.ChangePercent != -25 {
+		t.Errorf("expected change -30 (-25%%), got %s (%v%%)", ticker.PriceChange.String(), ticker.ChangePercent)
+	}
+
+	ticker = Ticker{LastPrice: wrappers.WrapBigInt(big.NewInt(90))}
+	ticker.setChange()
+
+	if ticker.PriceChange != nil {
+		t.Errorf("expected no change without open price, got %s", ticker.PriceChange.String())
+	}
+}
@@ -0,0 +1,295 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"hameid.net/cdex/dex/internal/store"
+	"hameid.net/cdex/dex/internal/wrappers"
+)
+
+// MaxOHLCBuckets is the maximum number of candles returned at once
+const MaxOHLCBuckets = 10000
+
+// Time buckets are aligned to this Monday, like TimescaleDB's time_bucket
+const bucketOrigin = 946857600 // 2000-01-03T00:00:00Z
+
+// OHLCResolutions maps resolution names to candle widths
+var OHLCResolutions = map[string]time.Duration{
+	"1m":  time.Minute,
+	"5m":  5 * time.Minute,
+	"15m": 15 * time.Minute,
+	"1h":  time.Hour,
+	"4h":  4 * time.Hour,
+	"1d":  24 * time.Hour,
+	"1w":  7 * 24 * time.Hour,
+}
+
+// Continuous aggregates holding candles of each resolution
+var candleViews = map[time.Duration]string{
+	time.Minute:        "candles_1m",
+	5 * time.Minute:    "candles_5m",
+	15 * time.Minute:   "candles_15m",
+	time.Hour:          "candles_1h",
+	4 * time.Hour:      "candles_4h",
+	24 * time.Hour:     "candles_1d",
+	7 * 24 * time.Hour: "candles_1w",
+}
+
+// ErrUnknownResolution thrown if there are no candles of the resolution
+var ErrUnknownResolution = errors.New("Unknown resolution")
+
+// ErrTooManyOHLCBuckets thrown if the range holds more than MaxOHLCBuckets candles
+var ErrTooManyOHLCBuckets = fmt.Errorf("Time range holds more than %d candles", MaxOHLCBuckets)
+
+// ErrInvalidTimeRange thrown if range end is not after its start
+var ErrInvalidTimeRange = errors.New("`to` must be after `from`")
+
+// OHLCResponse record
+type OHLCResponse struct {
+	Open   *wrappers.BigInt `json:"open"`
+	High   *wrappers.BigInt `json:"high"`
+	Low    *wrappers.BigInt `json:"low"`
+	Close  *wrappers.BigInt `json:"close"`
+	Volume *wrappers.BigInt `json:"volume"`
+	// Start of the candle in seconds since Unix epoch
+	Time int64 `json:"date"`
+}
+
+// GetOHLCData returns candles of the given resolution over [from, to), oldest
+// first. Candles without trades repeat close price of the previous one.
+func GetOHLCData(store *store.DataStore, token *common.Address, base *common.Address, resolution time.Duration, from time.Time, to time.Time) ([]OHLCResponse, error) {
+	step := int64(resolution / time.Second)
+	start := alignBucket(from.Unix(), step)
+	end := to.Unix()
+
+	if end <= start {
+		return nil, ErrInvalidTimeRange
+	}
+	if (end-start)/step > MaxOHLCBuckets {
+		return nil, ErrTooManyOHLCBuckets
+	}
+
+	view, ok := candleViews[resolution]
+	if !ok {
+		return nil, ErrUnknownResolution
+	}
+
+	query := fmt.Sprintf(`
+	SELECT extract(epoch from bucket::timestamp with time zone)::bigint AS timeinterval,
+		open,
+		close,
+		high,
+		low,
+		volume
+	  FROM %s
+	  WHERE bucket >= to_timestamp($3)
+		AND bucket < to_timestamp($4)
+		AND token=LOWER($1)
+		AND base=LOWER($2)
+	  ORDER BY bucket ASC;
+	`, view)
+	rows, err := store.DB.Query(query, token.Hex(), base.Hex(), start, end)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	candles := []OHLCResponse{}
+
+	for rows.Next() {
+		var candle OHLCResponse
+
+		err := rows.Scan(
+			&candle.Time,
+			&candle.Open,
+			&candle.Close,
+			&candle.High,
+			&candle.Low,
+			&candle.Volume,
+		)
+
+		if err != nil {
+			return nil, err
+		}
+
+		candles = append(candles, candle)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	previousClose, err := getLastTradedPriceBefore(store, token, base, start)
+	if err != nil {
+		return nil, err
+	}
+
+	return fillOHLCGaps(candles, previousClose, start, end, step), nil
+}
+
+// RefreshCandles materializes candles of trades in [from, to), e.g. after
+// trades were replayed. All resolutions are refreshed if resolution is 0.
+func RefreshCandles(store *store.DataStore, resolution time.Duration, from time.Time, to time.Time) error {
+	if !to.After(from) {
+		return ErrInvalidTimeRange
+	}
+
+	resolutions := []time.Duration{resolution}
+	if resolution == 0 {
+		resolutions = sortedResolutions()
+	} else if _, ok := candleViews[resolution]; !ok {
+		return ErrUnknownResolution
+	}
+
+	for _, r := range resolutions {
+		view := candleViews[r]
+		step := int64(r / time.Second)
+
+		// Refresh window must cover whole buckets
+		start := alignBucket(from.Unix(), step)
+		end := alignBucket(to.Unix()+step-1, step)
+
+		fmt.Printf("Refreshing %s from %s to %s...\n", view, time.Unix(start, 0).UTC(), time.Unix(end, 0).UTC())
+
+		// Cannot run in a transaction, so arguments are not bound
+		_, err := store.DB.Exec(fmt.Sprintf(
+			`CALL refresh_continuous_aggregate('%s', to_timestamp(%d)::timestamp, to_timestamp(%d)::timestamp)`,
+			view, start, end,
+		))
+		if err != nil {
+			return fmt.Errorf("Cannot refresh %s: %s", view, err)
+		}
+	}
+
+	return nil
+}
+
+func sortedResolutions() []time.Duration {
+	resolutions := make([]time.Duration, 0, len(candleViews))
+	for r := range candleViews {
+		resolutions = append(resolutions, r)
+	}
+	sort.Slice(resolutions, func(i, j int) bool { return resolutions[i] < resolutions[j] })
+	return resolutions
+}
+
+// fillOHLCGaps adds flat candles at previous close for buckets without
+// trades. Buckets before the first trade are left out.
+func fillOHLCGaps(candles []OHLCResponse, previousClose *wrappers.BigInt, start int64, end int64, step int64) []OHLCResponse {
+	filled := make([]OHLCResponse, 0, (end-start)/step+1)
+
+	next := 0
+	for t := start; t < end; t += step {
+		if next < len(candles) && candles[next].Time == t {
+			filled = append(filled, candles[next])
+			previousClose = candles[next].Close
+			next++
+			continue
+		}
+
+		if previousClose == nil {
+			continue
+		}
+
+		filled = append(filled, OHLCResponse{
+			Open:   previousClose,
+			High:   previousClose,
+			Low:    previousClose,
+			Close:  previousClose,
+			Volume: &wrappers.BigInt{},
+			Time:   t,
+		})
+	}
+
+	return filled
+}
+
+// alignBucket returns start of the bucket t falls in
+func alignBucket(t int64, step int64) int64 {
+	offset := (t - bucketOrigin) % step
+	if offset < 0 {
+		offset += step
+	}
+	return t - offset
+}
+
+func getLastTradedPriceBefore(store *store.DataStore, token *common.Address, base *common.Address, before int64) (*wrappers.BigInt, error) {
+	query := `SELECT price FROM trades
+		WHERE token=LOWER($1) AND base=LOWER($2) AND traded_at < to_timestamp($3)
+		ORDER BY traded_at DESC LIMIT 1;`
+
+	rows, err := store.DB.Query(query, token.Hex(), base.Hex(), before)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	if !rows.Next() {
+		return nil, rows.Err()
+	}
+
+	var price wrappers.BigInt
+	if err := rows.Scan(&price); err != nil {
+		return nil, err
+	}
+
+	return &price, nil
+}
+
+// UDFHistoryResponse is the bar history format of TradingView UDF
+type UDFHistoryResponse struct {
+	Status string    `json:"s"`
+	Error  string    `json:"errmsg,omitempty"`
+	Time   []int64   `json:"t"`
+	Open   []float64 `json:"o"`
+	High   []float64 `json:"h"`
+	Low    []float64 `json:"l"`
+	Close  []float64 `json:"c"`
+	Volume []float64 `json:"v"`
+}
+
+// NewUDFHistoryResponse converts candles to TradingView UDF bars
+func NewUDFHistoryResponse(candles []OHLCResponse) *UDFHistoryResponse {
+	resp := &UDFHistoryResponse{
+		Status: "ok",
+		Time:   make([]int64, len(candles)),
+		Open:   make([]float64, len(candles)),
+		High:   make([]float64, len(candles)),
+		Low:    make([]float64, len(candles)),
+		Close:  make([]float64, len(candles)),
+		Volume: make([]float64, len(candles)),
+	}
+
+	if len(candles) == 0 {
+		resp.Status = "no_data"
+	}
+
+	for i, candle := range candles {
+		resp.Time[i] = candle.Time
+		resp.Open[i] = bigIntToFloat(candle.Open)
+		resp.High[i] = bigIntToFloat(candle.High)
+		resp.Low[i] = bigIntToFloat(candle.Low)
+		resp.Close[i] = bigIntToFloat(candle.Close)
+		resp.Volume[i] = bigIntToFloat(candle.Volume)
+	}
+
+	return resp
+}
+
+func bigIntToFloat(i *wrappers.BigInt) float64 {
+	if i == nil {
+		return 0
+	}
+
+	f, _ := new(big.Float).SetInt(&i.Int).Float64()
+	return f
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"math/big"
+	"testing"
+
+	"hameid.net/cdex/dex/internal/wrappers"
+)
+
+func TestAlignBucket(t *testing.T) {
+	week := int64(7 * 24 * 3600)
+
+	// 2018-10-17 (Wednesday) falls in the week starting 2018-10-15 (Monday)
+	if got := alignBucket(1539777600, week); got != 1539561600 {
+		t.Errorf("expected week bucket 1539561600, got %d", got)
+	}
+	if got := alignBucket(1539777659, 60); got != 1539777600 {
+		t.Errorf("expected minute bucket 1539777600, got %d", got)
+	}
+}
+
+func TestFillOHLCGaps(t *testing.T) {
+	price := func(p int64) *wrappers.BigInt {
+		return &wrappers.BigInt{Int: *big.NewInt(p)}
+	}
+
+	candles := []OHLCResponse{
+		{Open: price(3), High: price(4), Low: price(2), Close: price(4), Volume: price(10), Time: 120},
+	}
+
+	filled := fillOHLCGaps(candles, nil, 0, 240, 60)
+	if len(filled) != 2 || filled[0].Time != 120 || filled[1].Time != 180 {
+		t.Fatalf("expected candles at 120 and 180, got %+v", filled)
+	}
+	if filled[1].Open.Int.Cmp(big.NewInt(4)) != 0 || filled[1].Volume.Int.Sign() != 0 {
+		t.Errorf("expected flat candle at previous close, got %+v", filled[1])
+	}
+
+	filled = fillOHLCGaps(candles, price(1), 0, 240, 60)
+	if len(filled) != 4 || filled[0].Close.Int.Cmp(big.NewInt(1)) != 0 {
+		t.Errorf("expected leading candles at previous close, got %+v", filled)
+	}
+}
@@ -2,8 +2,8 @@ package models
 
 import (
 	"bytes"
-	"errors"
 	"fmt"
+	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
 
@@ -31,23 +31,12 @@ type MatchingOrderResponseItem struct {
 	VolumeLeft *wrappers.BigInt `json:"volume_left"`
 }
 
-type OrderbookResponseItem struct {
-	Price        *wrappers.BigInt `json:"price"`
-	Volume       *wrappers.BigInt `json:"volume"`
-	VolumeFilled *wrappers.BigInt `json:"volume_filled"`
-}
-
-type OrderbookResponse struct {
-	Bids      *[]OrderbookResponseItem `json:"bids"`
-	Asks      *[]OrderbookResponseItem `json:"asks"`
-	LastPrice *wrappers.BigInt         `json:"last_price"`
-}
-
-// Save inserts Order
+// Save inserts Order unless its event is already recorded
 func (order *Order) Save(store *store.DataStore) error {
 	query := `INSERT INTO orders (
 		order_hash, token, base, price, quantity, is_bid, created_at, created_by, volume)
-		VALUES (LOWER($1), LOWER($2), LOWER($3), $4, $5, $6, to_timestamp($7), LOWER($8), $9)`
+		VALUES (LOWER($1), LOWER($2), LOWER($3), $4, $5, $6, to_timestamp($7), LOWER($8), $9)
+		ON CONFLICT DO NOTHING`
 
 	_, err := store.DB.Exec(
 		query,
@@ -136,6 +125,25 @@ func (order *Order) Close(store *store.DataStore) error {
 	return err
 }
 
+// Cancel closes the order and records when it was cancelled and the fee
+// charged on the refund
+func (order *Order) Cancel(store *store.DataStore, cancelledAt uint64, fee *big.Int) error {
+	query := `UPDATE orders SET is_open=FALSE, cancelled_at=to_timestamp($1), cancel_fee=$2 WHERE order_hash=LOWER($3)`
+
+	_, err := store.DB.Exec(
+		query,
+		cancelledAt,
+		fee.String(),
+		order.Hash.Hex(),
+	)
+
+	if err == nil {
+		order.IsOpen = false
+	}
+
+	return err
+}
+
 // NewOrder returns new instance of Order struct
 func NewOrder() *Order {
 	return &Order{}
@@ -181,11 +189,20 @@ func buildWhereConstraintFromParams(params *map[string]interface{}) string {
 	return buffer.String()
 }
 
-// GetOrders returns the list of orders
-func GetOrders(store *store.DataStore, params *map[string]interface{}) ([]Order, error) {
+// GetOrders returns a page of orders, newest first
+func GetOrders(store *store.DataStore, params *map[string]interface{}) (*Page, error) {
+	count := (*params)["count"].(int)
 	constraints := buildWhereConstraintFromParams(params)
-	query := fmt.Sprintf(`SELECT order_hash, token, base, price, quantity, is_bid, trunc(extract(epoch from created_at::timestamp with time zone)), created_by, volume, volume_filled FROM orders WHERE created_at <= to_timestamp($3)%s ORDER BY created_at DESC LIMIT $1 OFFSET $2`, constraints)
-	rows, err := store.DB.Query(query, (*params)["count"], (*params)["start"], (*params)["before"])
+	args := []interface{}{count + 1, (*params)["before"]}
+
+	if cursor, ok := (*params)["cursor"].(*Cursor); ok {
+		condition, cursorArgs := cursor.keysetCondition("created_at", []string{"order_hash"}, len(args)+1)
+		constraints += " AND " + condition
+		args = append(args, cursorArgs...)
+	}
+
+	query := fmt.Sprintf(`SELECT order_hash, token, base, price, quantity, is_bid, trunc(extract(epoch from created_at::timestamp with time zone)), created_by, volume, volume_filled, %s FROM orders WHERE created_at <= to_timestamp($2)%s ORDER BY created_at DESC, order_hash DESC LIMIT $1`, epochMicros("created_at"), constraints)
+	rows, err := store.DB.Query(query, args...)
 
 	if err != nil {
 		return nil, err
@@ -194,9 +211,12 @@ func GetOrders(store *store.DataStore, params *map[string]interface{}) ([]Order,
 	defer rows.Close()
 
 	orders := []Order{}
+	fetched := 0
+	var last *Cursor
 
 	for rows.Next() {
 		var order Order
+		var createdAt int64
 
 		err := rows.Scan(
 			&order.Hash,
@@ -209,91 +229,23 @@ func GetOrders(store *store.DataStore, params *map[string]interface{}) ([]Order,
 			&order.CreatedBy,
 			&order.Volume,
 			&order.VolumeFilled,
+			&createdAt,
 		)
 
 		if err != nil {
 			return nil, err
 		}
 
-		orders = append(orders, order)
-	}
-
-	return orders, nil
-}
-
-func executeOrderbookQuery(store *store.DataStore, query string, token string, base string) (*[]OrderbookResponseItem, error) {
-	rows, err := store.DB.Query(query, token, base)
-
-	if err != nil {
-		return nil, err
-	}
-
-	defer rows.Close()
-
-	orders := []OrderbookResponseItem{}
-
-	for rows.Next() {
-		var order OrderbookResponseItem
-
-		err := rows.Scan(
-			&order.Price,
-			&order.Volume,
-			&order.VolumeFilled,
-		)
-
-		if err != nil {
-			return nil, err
+		fetched++
+		if fetched > count {
+			break
 		}
 
 		orders = append(orders, order)
+		last = &Cursor{Time: createdAt, Keys: []string{order.Hash.Hex()}}
 	}
 
-	return &orders, nil
-}
-
-// GetOrderbook returns the list of buy and sell orders
-func GetOrderbook(store *store.DataStore, params *map[string]interface{}) (*OrderbookResponse, error) {
-
-	token, ok := (*params)["token"].(string)
-	if !ok {
-		return nil, errors.New("`token` parameter is required")
-	}
-
-	base, ok := (*params)["base"].(string)
-	if !ok {
-		return nil, errors.New("`base` parameter is required")
-	}
-
-	buyOrdersQuery := fmt.Sprintf(`SELECT price, sum(volume), sum(volume_filled) FROM orders 
-		WHERE created_at > now() - interval '14 days' AND is_open=TRUE AND is_bid=TRUE AND token=LOWER($1) AND base=LOWER($2)
-		GROUP BY price ORDER BY price DESC LIMIT 20`)
-
-	buyOrders, err := executeOrderbookQuery(store, buyOrdersQuery, token, base)
-	if err != nil {
-		return nil, err
-	}
-
-	sellOrdersQuery := fmt.Sprintf(`SELECT price, sum(volume), sum(volume_filled) FROM orders 
-		WHERE created_at > now() - interval '14 days' AND is_open=TRUE AND is_bid=FALSE AND token=LOWER($1) AND base=LOWER($2)
-		GROUP BY price ORDER BY price ASC LIMIT 20`)
-
-	sellOrders, err := executeOrderbookQuery(store, sellOrdersQuery, token, base)
-	if err != nil {
-		return nil, err
-	}
-
-	lastPrice, err := getLastTradedPrice(store, token, base)
-	if err != nil {
-		return nil, err
-	}
-
-	OrderbookResponse := &OrderbookResponse{
-		Bids:      buyOrders,
-		Asks:      sellOrders,
-		LastPrice: lastPrice,
-	}
-
-	return OrderbookResponse, nil
+	return newPage(orders, fetched, count, last), nil
 }
 
 // GetMatchingOrders returns all matching orders for the given order
@@ -0,0 +1,237 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"hameid.net/cdex/dex/internal/store"
+	"hameid.net/cdex/dex/internal/wrappers"
+)
+
+// DefaultOrderbookDepth is the number of price levels returned per side by default
+const DefaultOrderbookDepth = 20
+
+// MaxOrderbookDepth is the maximum number of price levels returned per side
+const MaxOrderbookDepth = 500
+
+// OrderbookOrder is an open order listed in a level of L3 orderbook
+type OrderbookOrder struct {
+	Hash      *wrappers.Hash    `json:"order_hash"`
+	Price     *wrappers.BigInt  `json:"price"`
+	Quantity  *wrappers.BigInt  `json:"quantity"`
+	Volume    *wrappers.BigInt  `json:"volume"`
+	CreatedBy *wrappers.Address `json:"created_by"`
+	CreatedAt int64             `json:"created_at"`
+}
+
+// OrderbookResponseItem is a price level. Quantity and volume are what is
+// left to fill; cumulative sizes add up levels from the best price.
+type OrderbookResponseItem struct {
+	Price              *wrappers.BigInt `json:"price"`
+	Quantity           *wrappers.BigInt `json:"quantity"`
+	Volume             *wrappers.BigInt `json:"volume"`
+	CumulativeQuantity *wrappers.BigInt `json:"cumulative_quantity"`
+	CumulativeVolume   *wrappers.BigInt `json:"cumulative_volume"`
+	Orders             int64            `json:"orders"`
+	// Open orders at the level in price-time priority, only in L3 mode
+	Entries []OrderbookOrder `json:"entries,omitempty"`
+}
+
+type OrderbookResponse struct {
+	Bids      *[]OrderbookResponseItem `json:"bids"`
+	Asks      *[]OrderbookResponseItem `json:"asks"`
+	LastPrice *wrappers.BigInt         `json:"last_price"`
+}
+
+// Open orders of a side with price rounded to tick, away from the spread so
+// grouped levels never look better than the orders in them. Arguments are
+// token, base and tick.
+const orderbookSideQuery = `
+	WITH open_orders AS (
+		SELECT order_hash, price, %s(price / $3) * $3 AS level,
+			trunc(quantity * (volume - volume_filled) / volume) AS quantity_left,
+			volume - volume_filled AS volume_left,
+			created_by, created_at
+		FROM orders
+		WHERE created_at > now() - interval '14 days' AND is_open=TRUE AND is_bid=%t
+			AND token=LOWER($1) AND base=LOWER($2)
+	)
+`
+
+// GetOrderbook returns price levels of buy and sell orders. Params are
+// token, base, depth, tick (*big.Int) and l3 (bool).
+func GetOrderbook(store *store.DataStore, params *map[string]interface{}) (*OrderbookResponse, error) {
+
+	token, ok := (*params)["token"].(string)
+	if !ok {
+		return nil, errors.New("`token` parameter is required")
+	}
+
+	base, ok := (*params)["base"].(string)
+	if !ok {
+		return nil, errors.New("`base` parameter is required")
+	}
+
+	depth, ok := (*params)["depth"].(int)
+	if !ok {
+		depth = DefaultOrderbookDepth
+	}
+
+	tick, ok := (*params)["tick"].(*big.Int)
+	if !ok {
+		tick = big.NewInt(1)
+	}
+
+	l3, _ := (*params)["l3"].(bool)
+
+	buyOrders, err := getOrderbookSide(store, token, base, true, depth, tick, l3)
+	if err != nil {
+		return nil, err
+	}
+
+	sellOrders, err := getOrderbookSide(store, token, base, false, depth, tick, l3)
+	if err != nil {
+		return nil, err
+	}
+
+	lastPrice, err := getLastTradedPrice(store, token, base)
+	if err != nil {
+		return nil, err
+	}
+
+	OrderbookResponse := &OrderbookResponse{
+		Bids:      buyOrders,
+		Asks:      sellOrders,
+		LastPrice: lastPrice,
+	}
+
+	return OrderbookResponse, nil
+}
+
+func getOrderbookSide(store *store.DataStore, token string, base string, isBid bool, depth int, tick *big.Int, l3 bool) (*[]OrderbookResponseItem, error) {
+	rounding, order := "ceil", "ASC"
+	if isBid {
+		rounding, order = "floor", "DESC"
+	}
+
+	var levels []OrderbookResponseItem
+	var err error
+	if l3 {
+		levels, err = queryOrderbookOrders(store, fmt.Sprintf(orderbookSideQuery, rounding, isBid)+fmt.Sprintf(`,
+		levels AS (SELECT level FROM open_orders GROUP BY level ORDER BY level %s LIMIT $4)
+		SELECT level, order_hash, price, quantity_left, volume_left, created_by, created_at
+		FROM open_orders WHERE level IN (SELECT level FROM levels)
+		ORDER BY level %s, created_at ASC, order_hash ASC`, order, order), token, base, tick.String(), depth)
+	} else {
+		levels, err = queryOrderbookLevels(store, fmt.Sprintf(orderbookSideQuery, rounding, isBid)+fmt.Sprintf(`
+		SELECT level, sum(quantity_left), sum(volume_left), count(*)
+		FROM open_orders GROUP BY level ORDER BY level %s LIMIT $4`, order), token, base, tick.String(), depth)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	accumulateOrderbookLevels(levels)
+
+	return &levels, nil
+}
+
+func queryOrderbookLevels(store *store.DataStore, query string, args ...interface{}) ([]OrderbookResponseItem, error) {
+	rows, err := store.DB.Query(query, args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	levels := []OrderbookResponseItem{}
+
+	for rows.Next() {
+		var level OrderbookResponseItem
+
+		err := rows.Scan(
+			&level.Price,
+			&level.Quantity,
+			&level.Volume,
+			&level.Orders,
+		)
+
+		if err != nil {
+			return nil, err
+		}
+
+		levels = append(levels, level)
+	}
+
+	return levels, rows.Err()
+}
+
+// queryOrderbookOrders groups individual orders into levels
+func queryOrderbookOrders(store *store.DataStore, query string, args ...interface{}) ([]OrderbookResponseItem, error) {
+	rows, err := store.DB.Query(query, args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	levels := []OrderbookResponseItem{}
+
+	for rows.Next() {
+		var levelPrice wrappers.BigInt
+		var order OrderbookOrder
+		var createdAt time.Time
+
+		err := rows.Scan(
+			&levelPrice,
+			&order.Hash,
+			&order.Price,
+			&order.Quantity,
+			&order.Volume,
+			&order.CreatedBy,
+			&createdAt,
+		)
+
+		if err != nil {
+			return nil, err
+		}
+
+		order.CreatedAt = createdAt.Unix()
+
+		if len(levels) == 0 || levels[len(levels)-1].Price.Cmp(&levelPrice) != 0 {
+			levels = append(levels, OrderbookResponseItem{
+				Price:    wrappers.WrapBigInt(&levelPrice.Int),
+				Quantity: wrappers.WrapBigInt(big.NewInt(0)),
+				Volume:   wrappers.WrapBigInt(big.NewInt(0)),
+				Entries:  []OrderbookOrder{},
+			})
+		}
+
+		level := &levels[len(levels)-1]
+		level.Quantity.Add(&level.Quantity.Int, &order.Quantity.Int)
+		level.Volume.Add(&level.Volume.Int, &order.Volume.Int)
+		level.Orders++
+		level.Entries = append(level.Entries, order)
+	}
+
+	return levels, rows.Err()
+}
+
+// accumulateOrderbookLevels sets cumulative sizes of levels ordered from
+// the best price
+func accumulateOrderbookLevels(levels []OrderbookResponseItem) {
+	quantity := big.NewInt(0)
+	volume := big.NewInt(0)
+
+	for i := range levels {
+		quantity.Add(quantity, &levels[i].Quantity.Int)
+		volume.Add(volume, &levels[i].Volume.Int)
+
+		levels[i].CumulativeQuantity = wrappers.WrapBigInt(new(big.Int).Set(quantity))
+		levels[i].CumulativeVolume = wrappers.WrapBigInt(new(big.Int).Set(volume))
+	}
+}
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"math/big"
+	"testing"
+
+	"hameid.net/cdex/dex/internal/wrappers"
+)
+
+func TestAccumulateOrderbookLevels(t *testing.T) {
+	levels := []OrderbookResponseItem{
+		{Quantity: wrappers.WrapBigInt(big.NewInt(2)), Volume: wrappers.WrapBigInt(big.NewInt(20))},
+		{Quantity: wrappers.WrapBigInt(big.NewInt(3)), Volume: wrappers.WrapBigInt(big.NewInt(27))},
+	}
+
+	accumulateOrderbookLevels(levels)
+
+	if levels[0].CumulativeQuantity.Int.Cmp(big.NewInt(2)) != 0 || levels[1].CumulativeQuantity.Int.Cmp(big.NewInt(5)) != 0 {
+		t.Errorf("unexpected cumulative quantities %s, %s", levels[0].CumulativeQuantity.String(), levels[1].CumulativeQuantity.String())
+	}
+	if levels[1].CumulativeVolume.Int.Cmp(big.NewInt(47)) != 0 {
+		t.Errorf("expected cumulative volume 47, got %s", levels[1].CumulativeVolume.String())
+	}
+}
@@ -0,0 +1,172 @@
+package models
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"hameid.net/cdex/dex/internal/store"
+	"hameid.net/cdex/dex/internal/wrappers"
+)
+
+// Fee rates are fractions of 1 ether
+var feeRateUnit = new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+
+// StatementEntry is a change of a token balance of a wallet, of type
+// deposit, withdrawal, trade or fee. A trade makes one entry for each token
+// exchanged, and one for its fee.
+type StatementEntry struct {
+	Time      time.Time         `json:"time"`
+	Kind      string            `json:"type"`
+	Token     *wrappers.Address `json:"token"`
+	Amount    *wrappers.BigInt  `json:"amount"`
+	Balance   *wrappers.BigInt  `json:"balance"`
+	TxHash    *string           `json:"tx_hash"`
+	OrderHash *string           `json:"order_hash"`
+}
+
+// FeeRates charged on trades recorded without fees. Nil rates are 0.
+type FeeRates struct {
+	Take *wrappers.BigInt
+	Make *wrappers.BigInt
+}
+
+func (rates FeeRates) args() (string, string) {
+	rate := func(r *wrappers.BigInt) string {
+		if r == nil {
+			return "0"
+		}
+		return r.String()
+	}
+	return rate(rates.Take), rate(rates.Make)
+}
+
+// CalculateFee returns fee charged on amount at rate, like FeeContract
+func CalculateFee(amount *big.Int, rate *wrappers.BigInt) *big.Int {
+	if rate == nil {
+		return big.NewInt(0)
+	}
+
+	fee := new(big.Int).Mul(amount, &rate.Int)
+	return fee.Quo(fee, feeRateUnit)
+}
+
+// Every balance change of wallet $1. Trades are joined to the orders of the
+// wallet on either side; the buyer pays base and is paid token. Withdraw
+// requests recorded without time are left out.
+const statementEntries = `
+	WITH entries AS (
+		SELECT deposited_at AS time, 'deposit' AS kind, token, amount, tx_hash, NULL AS order_hash
+		FROM deposits WHERE recipient=LOWER($1)
+	  UNION ALL
+		SELECT requested_at, 'withdrawal', token, -amount, tx_hash, NULL
+		FROM withdraw_meta WHERE recipient=LOWER($1) AND requested_at IS NOT NULL
+	  UNION ALL
+		SELECT t.traded_at, 'trade', t.base, -t.volume, t.tx_hash, t.buy_order_hash
+		FROM trades t JOIN orders o ON o.order_hash=t.buy_order_hash WHERE o.created_by=LOWER($1)
+	  UNION ALL
+		SELECT t.traded_at, 'trade', t.token, t.volume, t.tx_hash, t.buy_order_hash
+		FROM trades t JOIN orders o ON o.order_hash=t.buy_order_hash WHERE o.created_by=LOWER($1)
+	  UNION ALL
+		SELECT t.traded_at, 'fee', t.token, -coalesce(t.take_fee, trunc(t.volume * $2 / 1000000000000000000)), t.tx_hash, t.buy_order_hash
+		FROM trades t JOIN orders o ON o.order_hash=t.buy_order_hash WHERE o.created_by=LOWER($1)
+	  UNION ALL
+		SELECT t.traded_at, 'trade', t.token, -t.volume, t.tx_hash, t.sell_order_hash
+		FROM trades t JOIN orders o ON o.order_hash=t.sell_order_hash WHERE o.created_by=LOWER($1)
+	  UNION ALL
+		SELECT t.traded_at, 'trade', t.base, t.volume, t.tx_hash, t.sell_order_hash
+		FROM trades t JOIN orders o ON o.order_hash=t.sell_order_hash WHERE o.created_by=LOWER($1)
+	  UNION ALL
+		SELECT t.traded_at, 'fee', t.base, -coalesce(t.make_fee, trunc(t.volume * $3 / 1000000000000000000)), t.tx_hash, t.sell_order_hash
+		FROM trades t JOIN orders o ON o.order_hash=t.sell_order_hash WHERE o.created_by=LOWER($1)
+	  UNION ALL
+		SELECT cancelled_at, 'fee', CASE WHEN is_bid THEN base ELSE token END, -cancel_fee, NULL, order_hash
+		FROM orders WHERE created_by=LOWER($1) AND cancel_fee > 0
+	)
+`
+
+// StreamStatement calls fn with balance changes of the wallet in [from, to),
+// oldest first. Balances run from the wallet's balance at from, escrow
+// included. Streaming stops at the first error returned by fn.
+func StreamStatement(store *store.DataStore, wallet *common.Address, from time.Time, to time.Time, rates FeeRates, fn func(*StatementEntry) error) error {
+	balances, err := getStatementOpeningBalances(store, wallet, from, rates)
+	if err != nil {
+		return err
+	}
+
+	takeRate, makeRate := rates.args()
+	rows, err := store.DB.Query(statementEntries+`
+		SELECT time, kind, token, amount, tx_hash, order_hash FROM entries
+		WHERE time >= to_timestamp($4) AND time < to_timestamp($5) AND amount <> 0
+		ORDER BY time ASC, tx_hash ASC, kind DESC`,
+		wallet.Hex(), takeRate, makeRate, from.Unix(), to.Unix())
+
+	if err != nil {
+		return err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var entry StatementEntry
+
+		err := rows.Scan(
+			&entry.Time,
+			&entry.Kind,
+			&entry.Token,
+			&entry.Amount,
+			&entry.TxHash,
+			&entry.OrderHash,
+		)
+
+		if err != nil {
+			return err
+		}
+
+		balance, ok := balances[entry.Token.Address]
+		if !ok {
+			balance = new(big.Int)
+			balances[entry.Token.Address] = balance
+		}
+		balance.Add(balance, &entry.Amount.Int)
+
+		entry.Time = entry.Time.UTC()
+		entry.Balance = wrappers.WrapBigInt(new(big.Int).Set(balance))
+
+		if err := fn(&entry); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+func getStatementOpeningBalances(store *store.DataStore, wallet *common.Address, from time.Time, rates FeeRates) (map[common.Address]*big.Int, error) {
+	takeRate, makeRate := rates.args()
+	rows, err := store.DB.Query(statementEntries+`
+		SELECT token, sum(amount) FROM entries
+		WHERE time < to_timestamp($4)
+		GROUP BY token`,
+		wallet.Hex(), takeRate, makeRate, from.Unix())
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	balances := make(map[common.Address]*big.Int)
+
+	for rows.Next() {
+		var token wrappers.Address
+		var balance wrappers.BigInt
+
+		if err := rows.Scan(&token, &balance); err != nil {
+			return nil, err
+		}
+
+		balances[token.Address] = &balance.Int
+	}
+
+	return balances, rows.Err()
+}
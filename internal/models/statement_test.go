@@ -0,0 +1,21 @@
+package models
+
+import (
+	"math/big"
+	"testing"
+
+	"hameid.net/cdex/dex/internal/wrappers"
+)
+
+func TestCalculateFee(t *testing.T) {
+	// 0.25% of 2 ether
+	rate := wrappers.WrapBigInt(big.NewInt(2500000000000000))
+	amount := new(big.Int).Mul(big.NewInt(2), feeRateUnit)
+
+	if fee := CalculateFee(amount, rate); fee.Cmp(big.NewInt(5000000000000000)) != 0 {
+		t.Errorf("expected fee 5000000000000000, got %s", fee.String())
+	}
+	if fee := CalculateFee(amount, nil); fee.Sign() != 0 {
+		t.Errorf("expected no fee without rate, got %s", fee.String())
+	}
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"fmt"
 	"math/big"
 	"time"
 
@@ -19,6 +20,10 @@ type Trade struct {
 	Volume        *wrappers.BigInt  `json:"volume"`
 	TradedAt      uint64            `json:"traded_at"`
 	TxHash        *wrappers.Hash    `json:"tx_hash"`
+	LogIndex      uint              `json:"log_index"`
+	// Fees paid by buyer in token and by seller in base
+	TakeFee *wrappers.BigInt `json:"take_fee"`
+	MakeFee *wrappers.BigInt `json:"make_fee"`
 }
 
 // UserTradeResponse record
@@ -38,21 +43,12 @@ type TradeHistoryResponse struct {
 	Timestamp *time.Time       `json:"traded_at"`
 }
 
-// OHLCResponse record
-type OHLCResponse struct {
-	Open   *wrappers.BigInt `json:"open"`
-	High   *wrappers.BigInt `json:"high"`
-	Low    *wrappers.BigInt `json:"low"`
-	Close  *wrappers.BigInt `json:"close"`
-	Volume *wrappers.BigInt `json:"volume"`
-	Date   *string          `json:"date"`
-}
-
-// Save inserts Trade
+// Save inserts Trade unless its event is already recorded
 func (trade *Trade) Save(store *store.DataStore) error {
 	query := `INSERT INTO trades (
-		buy_order_hash, sell_order_hash, token, base, price, volume, traded_at, tx_hash)
-		VALUES (LOWER($1), LOWER($2), LOWER($3), LOWER($4), $5, $6, to_timestamp($7), LOWER($8))`
+		buy_order_hash, sell_order_hash, token, base, price, volume, traded_at, tx_hash, log_index, take_fee, make_fee)
+		VALUES (LOWER($1), LOWER($2), LOWER($3), LOWER($4), $5, $6, to_timestamp($7), LOWER($8), $9, $10, $11)
+		ON CONFLICT DO NOTHING`
 
 	_, err := store.DB.Exec(
 		query,
@@ -64,39 +60,40 @@ func (trade *Trade) Save(store *store.DataStore) error {
 		trade.Volume.String(),
 		trade.TradedAt,
 		trade.TxHash,
+		trade.LogIndex,
+		trade.TakeFee.String(),
+		trade.MakeFee.String(),
 	)
 
 	return err
 }
 
-// GetTradesOfUser returns the list of trades
-func GetTradesOfUser(store *store.DataStore, token *common.Address, base *common.Address, user *common.Address) ([]UserTradeResponse, error) {
-
-	query := `SELECT 
-		recent_orders.order_hash as order_hash, recent_orders.is_bid as is_buy, 
-		recent_trades.price, recent_trades.volume, 
-		recent_trades.traded_at, 
-		recent_trades.tx_hash
-	FROM
-
-		(SELECT order_hash, is_bid FROM orders 
-			WHERE created_at > now() - interval '3 months'
-			AND volume_filled > 0
-			AND base=LOWER($1) AND token=LOWER($2) AND created_by=LOWER($3) 
-			ORDER BY created_at DESC
-			LIMIT 50) as recent_orders
+// GetTradesOfUser returns a page of trades of user's orders, newest first
+func GetTradesOfUser(store *store.DataStore, token *common.Address, base *common.Address, user *common.Address, count int, cursor *Cursor) (*Page, error) {
+	args := []interface{}{base.Hex(), token.Hex(), user.Hex(), count + 1}
+	constraints := ""
 
-	INNER JOIN 
-		(SELECT buy_order_hash, sell_order_hash, price, volume, traded_at, tx_hash FROM trades 
-			WHERE traded_at > now() - interval '1 month'
-			AND base=LOWER($1) AND token=LOWER($2) 
-			ORDER BY traded_at DESC
-			LIMIT 50) as recent_trades
-
-		ON recent_orders.order_hash IN (recent_trades.buy_order_hash, recent_trades.sell_order_hash) 
-	`
+	if cursor != nil {
+		condition, cursorArgs := cursor.keysetCondition("trades.traded_at", []string{"trades.tx_hash", "orders.order_hash"}, len(args)+1)
+		constraints = " AND " + condition
+		args = append(args, cursorArgs...)
+	}
 
-	rows, err := store.DB.Query(query, base.Hex(), token.Hex(), user.Hex())
+	query := fmt.Sprintf(`SELECT 
+		orders.order_hash, orders.is_bid as is_buy, 
+		trades.price, trades.volume, 
+		trades.traded_at, 
+		trades.tx_hash,
+		%s
+	FROM trades
+	INNER JOIN orders
+		ON orders.order_hash IN (trades.buy_order_hash, trades.sell_order_hash)
+	WHERE trades.base=LOWER($1) AND trades.token=LOWER($2)
+		AND orders.base=LOWER($1) AND orders.token=LOWER($2) AND orders.created_by=LOWER($3)%s
+	ORDER BY trades.traded_at DESC, trades.tx_hash DESC, orders.order_hash DESC
+	LIMIT $4`, epochMicros("trades.traded_at"), constraints)
+
+	rows, err := store.DB.Query(query, args...)
 
 	if err != nil {
 		return nil, err
@@ -105,9 +102,12 @@ func GetTradesOfUser(store *store.DataStore, token *common.Address, base *common
 	defer rows.Close()
 
 	trades := []UserTradeResponse{}
+	fetched := 0
+	var last *Cursor
 
 	for rows.Next() {
 		var trade UserTradeResponse
+		var tradedAt int64
 
 		err := rows.Scan(
 			&trade.OrderHash,
@@ -116,75 +116,43 @@ func GetTradesOfUser(store *store.DataStore, token *common.Address, base *common
 			&trade.Volume,
 			&trade.TradedAt,
 			&trade.TxHash,
+			&tradedAt,
 		)
 
 		if err != nil {
 			return nil, err
 		}
 
+		fetched++
+		if fetched > count {
+			break
+		}
+
 		trades = append(trades, trade)
+		last = &Cursor{Time: tradedAt, Keys: []string{trade.TxHash.Hex(), trade.OrderHash.Hex()}}
 	}
 
-	return trades, nil
+	return newPage(trades, fetched, count, last), nil
 }
 
-// GetOHLCData returns the list of trades
-func GetOHLCData(store *store.DataStore, token *common.Address, base *common.Address) ([]OHLCResponse, error) {
-	query := `
-	SELECT time_bucket('5 minutes', traded_at) AS timeinterval,
-		first(price, traded_at) AS open,
-		last(price, traded_at) AS close,
-		max(price) AS high,
-		min(price) AS low,
-		sum(volume) AS volume
-	  FROM trades
-	  WHERE traded_at >= now() - interval '1 month'
-		AND token=LOWER($1)
-		AND base=LOWER($2)
-	  GROUP BY timeinterval
-	  ORDER BY timeinterval DESC;
-	`
-	rows, err := store.DB.Query(query, token.Hex(), base.Hex())
-
-	if err != nil {
-		return nil, err
-	}
-
-	defer rows.Close()
-
-	trades := []OHLCResponse{}
+// GetTradeHistory returns a page of P/V history of trades, newest first
+func GetTradeHistory(store *store.DataStore, token *common.Address, base *common.Address, count int, cursor *Cursor) (*Page, error) {
+	args := []interface{}{token.Hex(), base.Hex(), count + 1}
+	constraints := ""
 
-	for rows.Next() {
-		var trade OHLCResponse
-
-		err := rows.Scan(
-			&trade.Date,
-			&trade.Open,
-			&trade.Close,
-			&trade.High,
-			&trade.Low,
-			&trade.Volume,
-		)
-
-		if err != nil {
-			return nil, err
-		}
-
-		trades = append(trades, trade)
+	if cursor != nil {
+		condition, cursorArgs := cursor.keysetCondition("traded_at", []string{"tx_hash"}, len(args)+1)
+		constraints = " AND " + condition
+		args = append(args, cursorArgs...)
 	}
 
-	return trades, nil
-}
-
-// GetTradeHistory returns the list of P/V history of recent trades
-func GetTradeHistory(store *store.DataStore, token *common.Address, base *common.Address) ([]TradeHistoryResponse, error) {
-	query := `
-	SELECT traded_at, price, volume FROM trades
-	WHERE token=LOWER($1) AND base=LOWER($2)
-	ORDER BY traded_at DESC
-	LIMIT 20;
-	`
-	rows, err := store.DB.Query(query, token.Hex(), base.Hex())
+	query := fmt.Sprintf(`
+	SELECT traded_at, price, volume, tx_hash, %s FROM trades
+	WHERE token=LOWER($1) AND base=LOWER($2)%s
+	ORDER BY traded_at DESC, tx_hash DESC
+	LIMIT $3;
+	`, epochMicros("traded_at"), constraints)
+	rows, err := store.DB.Query(query, args...)
 
 	if err != nil {
 		return nil, err
@@ -193,24 +161,36 @@ func GetTradeHistory(store *store.DataStore, token *common.Address, base *common
 	defer rows.Close()
 
 	trades := []TradeHistoryResponse{}
+	fetched := 0
+	var last *Cursor
 
 	for rows.Next() {
 		var trade TradeHistoryResponse
+		var txHash string
+		var tradedAt int64
 
 		err := rows.Scan(
 			&trade.Timestamp,
 			&trade.Price,
 			&trade.Volume,
+			&txHash,
+			&tradedAt,
 		)
 
 		if err != nil {
 			return nil, err
 		}
 
+		fetched++
+		if fetched > count {
+			break
+		}
+
 		trades = append(trades, trade)
+		last = &Cursor{Time: tradedAt, Keys: []string{txHash}}
 	}
 
-	return trades, nil
+	return newPage(trades, fetched, count, last), nil
 }
 
 func getLastTradedPrice(store *store.DataStore, token string, base string) (*wrappers.BigInt, error) {
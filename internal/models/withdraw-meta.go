@@ -1,6 +1,9 @@
 package models
 
 import (
+	"database/sql"
+	"time"
+
 	"hameid.net/cdex/dex/internal/store"
 	"hameid.net/cdex/dex/internal/wrappers"
 )
@@ -19,13 +22,16 @@ type WithdrawMeta struct {
 	TxHash    *wrappers.Hash    `json:"tx_hash"`
 	// Message   string            `json:"message_data"`
 	Status int `json:"withdraw_status"`
+	// Block time of the request, 0 if unknown
+	RequestedAt uint64 `json:"requested_at"`
 }
 
-// Save inserts WithdrawMeta
+// Save inserts WithdrawMeta unless its event is already recorded
 func (withdrawMeta *WithdrawMeta) Save(store *store.DataStore) error {
 	query := `INSERT INTO withdraw_meta 
-		(token, recipient, amount, tx_hash, withdraw_status)
-		VALUES (LOWER($1), LOWER($2), $3, LOWER($4), $5)`
+		(token, recipient, amount, tx_hash, withdraw_status, requested_at)
+		VALUES (LOWER($1), LOWER($2), $3, LOWER($4), $5, to_timestamp(NULLIF($6::bigint, 0)))
+		ON CONFLICT DO NOTHING`
 
 	_, err := store.DB.Exec(
 		query,
@@ -35,6 +41,7 @@ func (withdrawMeta *WithdrawMeta) Save(store *store.DataStore) error {
 		withdrawMeta.TxHash,
 		// withdrawMeta.Message,
 		withdrawMeta.Status,
+		withdrawMeta.RequestedAt,
 	)
 
 	return err
@@ -111,6 +118,92 @@ func GetUnprocessedWithdrawRequests(store *store.DataStore, address *wrappers.Ad
 	return requests, nil
 }
 
+// ClaimRelay counts a relay attempt of the signed withdrawal if it is not
+// being relayed already. Only the caller that gets true may submit it, so
+// that a withdrawal is not relayed twice at once.
+func (withdrawMeta *WithdrawMeta) ClaimRelay(store *store.DataStore, retryAfter time.Duration, maxAttempts int) (bool, error) {
+	// Concurrent claims wait for each other and see the relayed_at set by
+	// the first
+	query := `UPDATE withdraw_meta 
+		SET relay_attempts=relay_attempts + 1, relayed_at=now()
+		WHERE tx_hash=LOWER($1) AND withdraw_status=$2 AND relay_attempts < $3
+			AND (relayed_at IS NULL OR relayed_at < now() - $4 * interval '1 second')
+		RETURNING relay_attempts`
+
+	var attempts int
+	err := store.DB.QueryRow(
+		query,
+		withdrawMeta.TxHash,
+		WITHDRAW_STATUS_SIGNED,
+		maxAttempts,
+		int64(retryAfter.Seconds()),
+	).Scan(&attempts)
+
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// RecordRelayAttempt stores the hash of the transaction that relayed the
+// withdrawal to home bridge, after it is claimed with ClaimRelay
+func (withdrawMeta *WithdrawMeta) RecordRelayAttempt(store *store.DataStore, relayTxHash *wrappers.Hash) error {
+	query := `UPDATE withdraw_meta 
+		SET relay_tx_hash=LOWER($2)
+		WHERE tx_hash=LOWER($1)`
+
+	_, err := store.DB.Exec(
+		query,
+		withdrawMeta.TxHash,
+		relayTxHash,
+	)
+
+	return err
+}
+
+// GetStuckWithdrawRequests returns signed withdraw requests that have not been
+// relayed to home bridge within retryAfter
+func GetStuckWithdrawRequests(store *store.DataStore, retryAfter time.Duration, maxAttempts int) ([]WithdrawMeta, error) {
+	rows, err := store.DB.Query(
+		`SELECT token, recipient, amount, tx_hash, withdraw_status 
+		FROM withdraw_meta 
+		WHERE withdraw_status=$1 AND relay_attempts < $2
+			AND (relayed_at IS NULL OR relayed_at < now() - $3 * interval '1 second')`,
+		WITHDRAW_STATUS_SIGNED, maxAttempts, int64(retryAfter.Seconds()))
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	requests := []WithdrawMeta{}
+
+	for rows.Next() {
+		var request WithdrawMeta
+
+		err := rows.Scan(
+			&request.Token,
+			&request.Recipient,
+			&request.Amount,
+			&request.TxHash,
+			&request.Status,
+		)
+
+		if err != nil {
+			return nil, err
+		}
+
+		requests = append(requests, request)
+	}
+
+	return requests, nil
+}
+
 // NewWithdrawMeta creates new instance of withdraw sign
 func NewWithdrawMeta() *WithdrawMeta {
 	return &WithdrawMeta{}
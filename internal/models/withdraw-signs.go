@@ -1,7 +1,10 @@
 package models
 
 import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"hameid.net/cdex/dex/internal/store"
+	"hameid.net/cdex/dex/internal/utils"
 	"hameid.net/cdex/dex/internal/wrappers"
 )
 
@@ -12,6 +15,34 @@ type WithdrawSign struct {
 	Signature string              `json:"message_sign"`
 	Signer    *wrappers.Address   `json:"signer"`
 	SignedAt  *wrappers.Timestamp `json:"signed_at"`
+	Valid     bool                `json:"valid"`
+}
+
+// WithdrawBundle holds the arguments of HomeBridge.withdraw
+type WithdrawBundle struct {
+	TxHash             string           `json:"tx_hash"`
+	Message            hexutil.Bytes    `json:"message"`
+	Vs                 []uint           `json:"vs"`
+	Rs                 []common.Hash    `json:"rs"`
+	Ss                 []common.Hash    `json:"ss"`
+	Signers            []common.Address `json:"signers"`
+	RequiredSignatures uint64           `json:"required_signatures"`
+	Ready              bool             `json:"ready"`
+}
+
+// WithdrawArguments returns bundle in the form expected by HomeBridge binding
+func (bundle *WithdrawBundle) WithdrawArguments() ([]uint8, [][32]byte, [][32]byte, []byte) {
+	vs := make([]uint8, len(bundle.Vs))
+	rs := make([][32]byte, len(bundle.Rs))
+	ss := make([][32]byte, len(bundle.Ss))
+
+	for i := range bundle.Vs {
+		vs[i] = uint8(bundle.Vs[i])
+		rs[i] = bundle.Rs[i]
+		ss[i] = bundle.Ss[i]
+	}
+
+	return vs, rs, ss, bundle.Message
 }
 
 // Save upserts WithdrawSign
@@ -68,6 +99,81 @@ func GetSignsOfWithdrawMessage(store *store.DataStore, txHash *wrappers.Hash) ([
 	return signs, nil
 }
 
+// Verify checks that the signature, plain or typed, recovers to the stored
+// signer and that signer is one of the authorities
+func (withdrawSign *WithdrawSign) Verify(authorities []common.Address) error {
+	if withdrawSign.Signer == nil {
+		return utils.ErrSignerMismatch
+	}
+
+	err := utils.VerifyWithdrawalSignature(
+		common.FromHex(withdrawSign.Message),
+		common.FromHex(withdrawSign.Signature),
+		withdrawSign.Signer.Address,
+	)
+	if err != nil {
+		return err
+	}
+
+	return utils.CheckAuthority(withdrawSign.Signer.Address, authorities)
+}
+
+// VerifyWithdrawSigns sets valid flag of the signatures
+func VerifyWithdrawSigns(signs []WithdrawSign, authorities []common.Address) {
+	for i := range signs {
+		signs[i].Valid = signs[i].Verify(authorities) == nil
+	}
+}
+
+// GetWithdrawBundle collects the signatures of the withdraw request made by
+// authorities. Invalid and duplicate signatures, and signatures of anyone
+// other than authorities, are left out.
+func GetWithdrawBundle(store *store.DataStore, txHash *wrappers.Hash, authorities []common.Address, requiredSignatures uint64) (*WithdrawBundle, error) {
+	signs, err := GetSignsOfWithdrawMessage(store, txHash)
+	if err != nil {
+		return nil, err
+	}
+
+	bundle := &WithdrawBundle{
+		TxHash:             txHash.Hex(),
+		Message:            hexutil.Bytes{},
+		Vs:                 []uint{},
+		Rs:                 []common.Hash{},
+		Ss:                 []common.Hash{},
+		Signers:            []common.Address{},
+		RequiredSignatures: requiredSignatures,
+	}
+
+	signed := make(map[common.Address]bool)
+
+	for _, sign := range signs {
+		message := common.FromHex(sign.Message)
+		if len(bundle.Message) > 0 && string(message) != string(bundle.Message) {
+			continue
+		}
+
+		if err := sign.Verify(authorities); err != nil || signed[sign.Signer.Address] {
+			continue
+		}
+
+		vs, rs, ss, err := utils.SplitSignatures([][]byte{common.FromHex(sign.Signature)})
+		if err != nil {
+			continue
+		}
+
+		signed[sign.Signer.Address] = true
+		bundle.Message = message
+		bundle.Vs = append(bundle.Vs, uint(vs[0]))
+		bundle.Rs = append(bundle.Rs, common.Hash(rs[0]))
+		bundle.Ss = append(bundle.Ss, common.Hash(ss[0]))
+		bundle.Signers = append(bundle.Signers, sign.Signer.Address)
+	}
+
+	bundle.Ready = requiredSignatures > 0 && uint64(len(bundle.Signers)) >= requiredSignatures
+
+	return bundle, nil
+}
+
 // NewWithdrawSign creates new instance of withdraw sign
 func NewWithdrawSign() *WithdrawSign {
 	return &WithdrawSign{}
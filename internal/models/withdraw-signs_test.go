@@ -0,0 +1,54 @@
+package models
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"hameid.net/cdex/dex/internal/utils"
+	"hameid.net/cdex/dex/internal/wrappers"
+)
+
+func TestVerifyTypedWithdrawSign(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	authority := crypto.PubkeyToAddress(key.PublicKey)
+
+	message := &utils.WithdrawalMessage{
+		Version:   utils.WithdrawalMessageV1,
+		ChainID:   big.NewInt(1337),
+		Contract:  common.HexToAddress("0xee457955F8ee9BbFDf357B20c287A470ABB8012F"),
+		Recipient: common.HexToAddress("0x991e501e6fbe1efc2e37ce938f6a39267851231a"),
+		Token:     common.HexToAddress("0xd8912c10681d8b21fd3742244f44658dba12264e"),
+		Amount:    big.NewInt(2500000000000000),
+		TxHash:    common.HexToHash("0x5c543e7ae0a1104f78406c340e9c64fd9fce5170ac709fcb44a43c35f0da4e31"),
+	}
+	data, err := message.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signature, err := utils.SignTypedWithdrawalWithPrivateKey(message, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signs := []WithdrawSign{{
+		Message:   hexutil.Encode(data),
+		Signature: hexutil.Encode(signature.Raw),
+		Signer:    wrappers.WrapAddress(&authority),
+	}}
+
+	VerifyWithdrawSigns(signs, []common.Address{authority})
+	if !signs[0].Valid {
+		t.Errorf("typed signature of authority reported invalid: %v", signs[0].Verify([]common.Address{authority}))
+	}
+
+	if err := signs[0].Verify([]common.Address{}); err != utils.ErrNotAuthority {
+		t.Errorf("expected ErrNotAuthority, got %v", err)
+	}
+}
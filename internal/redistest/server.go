@@ -0,0 +1,327 @@
+// Package redistest provides an in-memory redis server for tests. It knows
+// the few commands the services use and runs scripts registered with
+// HandleScript instead of Lua.
+package redistest
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ScriptFunc runs a script on keys and arguments of EVAL and EVALSHA. It
+// returns nil, string, int64, error or a slice of those.
+type ScriptFunc func(keys []string, args []string) interface{}
+
+type entry struct {
+	value    string
+	expireAt time.Time
+}
+
+// Server is an in-memory redis server listening on a local port
+type Server struct {
+	listener net.Listener
+
+	mu      sync.Mutex
+	data    map[string]entry
+	scripts map[string]ScriptFunc
+	offset  time.Duration
+}
+
+// NewServer starts a server on a random local port
+func NewServer() (*Server, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{
+		listener: listener,
+		data:     make(map[string]entry),
+		scripts:  make(map[string]ScriptFunc),
+	}
+	go s.serve()
+
+	return s, nil
+}
+
+// Addr returns address of the server
+func (s *Server) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// Close stops the server
+func (s *Server) Close() error {
+	return s.listener.Close()
+}
+
+// HandleScript runs fn for the script with the source
+func (s *Server) HandleScript(src string, fn ScriptFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	hash := sha1.Sum([]byte(src))
+	s.scripts[hex.EncodeToString(hash[:])] = fn
+}
+
+// FastForward moves the clock of the server, so that keys expire without
+// waiting
+func (s *Server) FastForward(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.offset += d
+}
+
+// Get returns the value of the key, false if it does not exist
+func (s *Server) Get(key string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.lookup(key)
+	return e.value, ok
+}
+
+// Keys returns keys that exist
+func (s *Server) Keys() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var keys []string
+	for key := range s.data {
+		if _, ok := s.lookup(key); ok {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+func (s *Server) now() time.Time {
+	return time.Now().Add(s.offset)
+}
+
+func (s *Server) lookup(key string) (entry, bool) {
+	e, ok := s.data[key]
+	if ok && !e.expireAt.IsZero() && !s.now().Before(e.expireAt) {
+		delete(s.data, key)
+		return entry{}, false
+	}
+	return e, ok
+}
+
+func (s *Server) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.serveConn(conn)
+	}
+}
+
+func (s *Server) serveConn(conn net.Conn) {
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	w := bufio.NewWriter(conn)
+
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			return
+		}
+
+		writeReply(w, s.exec(args))
+		if err := w.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+func (s *Server) exec(args []string) interface{} {
+	if len(args) == 0 {
+		return errors.New("ERR empty command")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch strings.ToUpper(args[0]) {
+	case "PING":
+		return "PONG"
+
+	case "GET":
+		if len(args) != 2 {
+			return errWrongArgs(args[0])
+		}
+		if e, ok := s.lookup(args[1]); ok {
+			return e.value
+		}
+		return nil
+
+	case "SET":
+		return s.set(args)
+
+	case "DEL":
+		var deleted int64
+		for _, key := range args[1:] {
+			if _, ok := s.lookup(key); ok {
+				delete(s.data, key)
+				deleted++
+			}
+		}
+		return deleted
+
+	case "EVAL", "EVALSHA":
+		return s.eval(args)
+	}
+
+	return fmt.Errorf("ERR unknown command '%s'", args[0])
+}
+
+func (s *Server) set(args []string) interface{} {
+	if len(args) < 3 {
+		return errWrongArgs(args[0])
+	}
+
+	e := entry{value: args[2]}
+	onlyNew := false
+
+	for i := 3; i < len(args); i++ {
+		switch strings.ToUpper(args[i]) {
+		case "NX":
+			onlyNew = true
+		case "EX", "PX":
+			if i+1 >= len(args) {
+				return errors.New("ERR syntax error")
+			}
+			n, err := strconv.ParseInt(args[i+1], 10, 64)
+			if err != nil || n <= 0 {
+				return errors.New("ERR invalid expire time in set")
+			}
+			unit := time.Second
+			if strings.ToUpper(args[i]) == "PX" {
+				unit = time.Millisecond
+			}
+			e.expireAt = s.now().Add(time.Duration(n) * unit)
+			i++
+		default:
+			return errors.New("ERR syntax error")
+		}
+	}
+
+	if _, ok := s.lookup(args[1]); ok && onlyNew {
+		return nil
+	}
+	s.data[args[1]] = e
+
+	return "OK"
+}
+
+func (s *Server) eval(args []string) interface{} {
+	if len(args) < 3 {
+		return errWrongArgs(args[0])
+	}
+
+	hash := args[1]
+	if strings.ToUpper(args[0]) == "EVAL" {
+		sum := sha1.Sum([]byte(args[1]))
+		hash = hex.EncodeToString(sum[:])
+	}
+
+	fn, ok := s.scripts[strings.ToLower(hash)]
+	if !ok {
+		return errors.New("NOSCRIPT No matching script. Please use EVAL.")
+	}
+
+	numKeys, err := strconv.Atoi(args[2])
+	if err != nil || numKeys < 0 || 3+numKeys > len(args) {
+		return errors.New("ERR Number of keys can't be greater than number of args")
+	}
+
+	// Scripts run without the lock, they only see what they are given
+	s.mu.Unlock()
+	defer s.mu.Lock()
+
+	return fn(args[3:3+numKeys], args[3+numKeys:])
+}
+
+func errWrongArgs(command string) error {
+	return fmt.Errorf("ERR wrong number of arguments for '%s' command", strings.ToLower(command))
+}
+
+// readCommand reads an array of bulk strings
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := readLine(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(line) == 0 || line[0] != '*' {
+		return nil, fmt.Errorf("Unexpected command %q", line)
+	}
+
+	n, err := strconv.Atoi(line[1:])
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]string, n)
+	for i := range args {
+		line, err := readLine(r)
+		if err != nil {
+			return nil, err
+		}
+		if len(line) == 0 || line[0] != '$' {
+			return nil, fmt.Errorf("Unexpected argument %q", line)
+		}
+
+		size, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, err
+		}
+
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		args[i] = string(buf[:size])
+	}
+
+	return args, nil
+}
+
+func readLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(line, "\r\n"), nil
+}
+
+func writeReply(w *bufio.Writer, reply interface{}) {
+	switch v := reply.(type) {
+	case nil:
+		w.WriteString("$-1\r\n")
+	case error:
+		fmt.Fprintf(w, "-%s\r\n", v.Error())
+	case int64:
+		fmt.Fprintf(w, ":%d\r\n", v)
+	case string:
+		fmt.Fprintf(w, "$%d\r\n%s\r\n", len(v), v)
+	case []interface{}:
+		fmt.Fprintf(w, "*%d\r\n", len(v))
+		for _, item := range v {
+			writeReply(w, item)
+		}
+	default:
+		fmt.Fprintf(w, "-ERR unsupported reply %T\r\n", reply)
+	}
+}
@@ -2,12 +2,15 @@ package relayer
 
 import (
 	"context"
-	"crypto/ecdsa"
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"log"
 	"math/big"
+	"net/http"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/go-redis/redis"
 
@@ -21,12 +24,17 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"hameid.net/cdex/dex/_abi/DEXChain"
 	"hameid.net/cdex/dex/_abi/HomeBridge"
 	"hameid.net/cdex/dex/_abi/OrderMatchContract"
 	"hameid.net/cdex/dex/_abi/Orderbook"
+	"hameid.net/cdex/dex/internal/config"
+	"hameid.net/cdex/dex/internal/health"
+	"hameid.net/cdex/dex/internal/lifecycle"
+	"hameid.net/cdex/dex/internal/metrics"
+	"hameid.net/cdex/dex/internal/signer"
+	"hameid.net/cdex/dex/internal/transactor"
 	"hameid.net/cdex/dex/internal/utils"
 )
 
@@ -42,6 +50,7 @@ type exchangeRef struct {
 	orderbookABI         *abi.ABI
 	ordermatcherABI      *abi.ABI
 	ordermatcherInstance *OrderMatchContract.OrderMatchContract
+	matcherTransactor    *transactor.Transactor
 }
 
 // Relayer struct
@@ -53,9 +62,14 @@ type Relayer struct {
 	store       *store.DataStore
 	redisClient *redis.Client
 
-	matcherPrivateKey *ecdsa.PrivateKey
-	matcherPublicKey  *ecdsa.PublicKey
-	matcherAddress    *common.Address
+	matcher        signer.Signer
+	matcherAddress *common.Address
+
+	withdrawRelay *withdrawRelay
+
+	// Event loops and periodic tasks
+	wg           sync.WaitGroup
+	healthServer *http.Server
 }
 
 type redisChannelMessage struct {
@@ -65,10 +79,16 @@ type redisChannelMessage struct {
 
 var channelSize = 10000
 
+// Orders cancelled within this many seconds of placing pay cancel fee
+const cancelFeePeriod = 2 * 24 * 60 * 60
+
+// Time to wait for match transactions to be mined
+const matchReceiptTimeout = 10 * time.Minute
+
 // Initialize reads and decodes ABIs to be used for communicating with chain
 func (r *Relayer) Initialize() {
 	fmt.Printf("\nConnecting to %s...\n", r.networks.Bridge.WebSocketProvider)
-	homeClient, err := ethclient.Dial(r.networks.Bridge.WebSocketProvider)
+	homeRPCClient, homeClient, err := transactor.Dial(r.networks.Bridge.WebSocketProvider)
 	if err != nil {
 		log.Panic(err)
 	}
@@ -85,8 +105,21 @@ func (r *Relayer) Initialize() {
 	// r.bridge.instance = bridge
 	r.bridge.abi = &bridgeABI
 
+	if r.withdrawRelay != nil {
+		bridge, err := HomeBridge.NewHomeBridge(r.contracts.Bridge.Address.Address, homeClient)
+		if err != nil {
+			log.Panic(err)
+		}
+		r.withdrawRelay.instance = bridge
+
+		r.withdrawRelay.transactor, err = transactor.NewTransactor(context.Background(), r.withdrawRelay.operator, homeRPCClient, &r.networks.Bridge)
+		if err != nil {
+			log.Panic(err)
+		}
+	}
+
 	fmt.Printf("\nConnecting to %s...\n", r.networks.Exchange.WebSocketProvider)
-	exchangeClient, err := ethclient.Dial(r.networks.Exchange.WebSocketProvider)
+	exchangeRPCClient, exchangeClient, err := transactor.Dial(r.networks.Exchange.WebSocketProvider)
 	if err != nil {
 		log.Panic(err)
 	}
@@ -111,11 +144,17 @@ func (r *Relayer) Initialize() {
 		log.Panic(err)
 	}
 
+	matcherTransactor, err := transactor.NewTransactor(context.Background(), r.matcher, exchangeRPCClient, &r.networks.Exchange.NetworkInfo)
+	if err != nil {
+		log.Panic(err)
+	}
+
 	r.exchange.client = exchangeClient
 	r.exchange.exchangeABI = &exchangeABI
 	r.exchange.orderbookABI = &orderbookABI
 	r.exchange.ordermatcherABI = &ordermatcherABI
 	r.exchange.ordermatcherInstance = ordermatcherInstance
+	r.exchange.matcherTransactor = matcherTransactor
 
 	fmt.Printf("\n")
 	r.store.Initialize()
@@ -123,8 +162,8 @@ func (r *Relayer) Initialize() {
 	fmt.Printf("\n\nRelayer initialization successful :)\n\n")
 }
 
-// RunOnBridgeNetwork runs relayer on the home network
-func (r *Relayer) RunOnBridgeNetwork() {
+// RunOnBridgeNetwork runs relayer on the home network until ctx is done
+func (r *Relayer) RunOnBridgeNetwork(ctx context.Context) {
 	fmt.Printf("Trying to listen events on Bridge contract %s...\n", r.contracts.Bridge.Address.Address.String())
 	query := ethereum.FilterQuery{
 		Addresses: []common.Address{r.contracts.Bridge.Address.Address},
@@ -132,27 +171,31 @@ func (r *Relayer) RunOnBridgeNetwork() {
 	}
 
 	logs := make(chan types.Log, channelSize)
+	checkpoint := r.loadCheckpoint(utils.NetworkBridge)
 
-	sub, err := r.bridge.client.SubscribeFilterLogs(context.Background(), query, logs)
-	if err != nil {
-		log.Panic(err)
-	}
+	lag := metrics.WatchBlockLag(ctx, utils.NetworkBridge, r.bridge.client)
 
+	r.wg.Add(1)
 	go func() {
-		for {
-			select {
-			case err := <-sub.Err():
-				log.Fatal("Home Network Subcription Error:", err)
-
-			case vLog := <-logs:
-				r.bridgeWithdrawCallback(vLog)
-			}
+		defer r.wg.Done()
+
+		err := lifecycle.ProcessLogs(ctx, r.bridge.client, query, logs, checkpoint, func(vLog types.Log) {
+			start := time.Now()
+			lag.Processed(vLog)
+			r.bridgeWithdrawCallback(vLog)
+			metrics.ObserveEvent(utils.NetworkBridge, "Withdraw", start)
+		}, func(block uint64) {
+			r.saveCheckpoint(utils.NetworkBridge, block)
+		})
+		if err != nil {
+			log.Fatal("Home Network Subcription Error:", err)
 		}
 	}()
 }
 
-// RunOnExchangeNetwork runs relayer on the exchange network
-func (r *Relayer) RunOnExchangeNetwork() {
+// RunOnExchangeNetwork runs relayer on the exchange network until ctx is
+// done
+func (r *Relayer) RunOnExchangeNetwork(ctx context.Context) {
 	fmt.Printf("Trying to listen events on Exchange contract %s...\n", r.contracts.Exchange.Address.Address.String())
 	query := ethereum.FilterQuery{
 		Addresses: []common.Address{
@@ -163,6 +206,7 @@ func (r *Relayer) RunOnExchangeNetwork() {
 		Topics: [][]common.Hash{
 			{
 				r.contracts.Exchange.Topics.BalanceUpdate.Hash,
+				r.contracts.Exchange.Topics.Deposit.Hash,
 				r.contracts.Orderbook.Topics.PlaceBuyOrder.Hash,
 				r.contracts.Orderbook.Topics.PlaceSellOrder.Hash,
 				r.contracts.Orderbook.Topics.CancelOrder.Hash,
@@ -175,91 +219,147 @@ func (r *Relayer) RunOnExchangeNetwork() {
 		},
 	}
 	logs := make(chan types.Log, channelSize)
-	sub, err := r.exchange.client.SubscribeFilterLogs(context.Background(), query, logs)
-	if err != nil {
-		log.Panic(err)
-	}
+	checkpoint := r.loadCheckpoint(utils.NetworkExchange)
 
+	lag := metrics.WatchBlockLag(ctx, utils.NetworkExchange, r.exchange.client)
+
+	r.wg.Add(1)
 	go func() {
-		for {
-			select {
-			case err := <-sub.Err():
-				log.Fatal("Foreign Network Subcription Error:", err)
-
-			case vLog := <-logs:
-				for _, topic := range vLog.Topics {
-					switch topic {
-					case r.contracts.Exchange.Topics.BalanceUpdate.Hash:
-						r.balanceUpdateLogCallback(vLog)
-					case r.contracts.Orderbook.Topics.PlaceBuyOrder.Hash:
-						r.placeOrderLogCallback(vLog, true)
-					case r.contracts.Orderbook.Topics.PlaceSellOrder.Hash:
-						r.placeOrderLogCallback(vLog, false)
-					case r.contracts.Orderbook.Topics.CancelOrder.Hash:
-						r.cancelOrderLogCallback(vLog)
-					case r.contracts.OrderMatcher.Topics.Trade.Hash:
-						r.tradeLogCallback(vLog)
-					case r.contracts.OrderMatcher.Topics.OrderFilledVolumeUpdate.Hash:
-						r.updateFilledVolumeLogCallback(vLog)
-					case r.contracts.Exchange.Topics.WithdrawSignatureSubmitted.Hash:
-						r.withdrawSignSubmittedCallback(vLog)
-					case r.contracts.Exchange.Topics.ReadyToWithdraw.Hash:
-						r.readyToWithdrawCallback(vLog)
-					case r.contracts.Exchange.Topics.Withdraw.Hash:
-						r.dexWithdrawCallback(vLog)
-					}
+		defer r.wg.Done()
+
+		err := lifecycle.ProcessLogs(ctx, r.exchange.client, query, logs, checkpoint, func(vLog types.Log) {
+			lag.Processed(vLog)
+			for _, topic := range vLog.Topics {
+				start := time.Now()
+				if event := r.exchangeLogCallback(topic, vLog); event != "" {
+					metrics.ObserveEvent(utils.NetworkExchange, event, start)
 				}
 			}
+		}, func(block uint64) {
+			r.saveCheckpoint(utils.NetworkExchange, block)
+		})
+		if err != nil {
+			log.Fatal("Foreign Network Subcription Error:", err)
 		}
 	}()
 }
 
-// Quit terminates relayer instance
-func (r *Relayer) Quit() {
-	fmt.Printf("\nCleaning up...\n")
-	r.store.Close()
-	fmt.Printf("\nBye bye...\n")
-}
-
-// NewRelayer creates and populates a Relayer struct object
-func NewRelayer(contractsFilePath, networksFilePath, connectionString, redisHostAddress, redisPassword, keystoreFilePath, passwordFilePath string) *Relayer {
-	fmt.Printf("Starting relayer...\n")
-	fmt.Printf("Reading config files...\n")
-	fmt.Printf("Reading %s...\n", contractsFilePath)
-	// Read config files
-	contractsInfo, err := utils.ReadContractsInfo(contractsFilePath)
-	if err != nil {
-		log.Panic(err)
+// saveCheckpoint records the last block of the network whose events are all
+// processed
+func (r *Relayer) saveCheckpoint(network string, block uint64) {
+	if block == 0 {
+		return
 	}
 
-	fmt.Printf("Reading %s...\n", networksFilePath)
-	nwInfo, err := utils.ReadNetworksInfo(networksFilePath)
-	if err != nil {
-		log.Panic(err)
+	checkpoint := models.Checkpoint{Network: network, BlockNumber: block}
+	if err := checkpoint.Save(r.store); err != nil {
+		fmt.Println("CHECKPOINT", err)
+		return
 	}
 
-	fmt.Printf("Decrypting order matcher keystore %s...\n", keystoreFilePath)
-	// Decrypt keystore
-	accountKey, err := utils.DecryptPrivateKeyFromKeystoreWithPasswordFile(keystoreFilePath, passwordFilePath)
-	if err != nil {
+	fmt.Printf("Processed events of %s network up to block %d\n", network, block)
+}
+
+// loadCheckpoint returns the block the relayer stopped at on its last run
+// on the network, zero if there is none. Events of later blocks are replayed.
+func (r *Relayer) loadCheckpoint(network string) uint64 {
+	checkpoint := models.Checkpoint{Network: network}
+	switch err := checkpoint.Get(r.store); err {
+	case nil:
+		fmt.Printf("Resuming events of %s network after block %d\n", network, checkpoint.BlockNumber)
+		return checkpoint.BlockNumber
+	case sql.ErrNoRows:
+		return 0
+	default:
 		log.Panic(err)
+		return 0
 	}
+}
 
-	privateKey := accountKey.PrivateKey
-	publicKey := privateKey.Public()
+// exchangeLogCallback runs the callback of the event with the topic and
+// returns the name of the event, or an empty name if the topic is not an
+// event of the exchange
+func (r *Relayer) exchangeLogCallback(topic common.Hash, vLog types.Log) string {
+	switch topic {
+	case r.contracts.Exchange.Topics.BalanceUpdate.Hash:
+		r.balanceUpdateLogCallback(vLog)
+		return "BalanceUpdate"
+	case r.contracts.Exchange.Topics.Deposit.Hash:
+		r.depositLogCallback(vLog)
+		return "Deposit"
+	case r.contracts.Orderbook.Topics.PlaceBuyOrder.Hash:
+		r.placeOrderLogCallback(vLog, true)
+		return "PlaceBuyOrder"
+	case r.contracts.Orderbook.Topics.PlaceSellOrder.Hash:
+		r.placeOrderLogCallback(vLog, false)
+		return "PlaceSellOrder"
+	case r.contracts.Orderbook.Topics.CancelOrder.Hash:
+		r.cancelOrderLogCallback(vLog)
+		return "CancelOrder"
+	case r.contracts.OrderMatcher.Topics.Trade.Hash:
+		r.tradeLogCallback(vLog)
+		return "Trade"
+	case r.contracts.OrderMatcher.Topics.OrderFilledVolumeUpdate.Hash:
+		r.updateFilledVolumeLogCallback(vLog)
+		return "OrderFilledVolumeUpdate"
+	case r.contracts.Exchange.Topics.WithdrawSignatureSubmitted.Hash:
+		r.withdrawSignSubmittedCallback(vLog)
+		return "WithdrawSignatureSubmitted"
+	case r.contracts.Exchange.Topics.ReadyToWithdraw.Hash:
+		r.readyToWithdrawCallback(vLog)
+		return "ReadyToWithdraw"
+	case r.contracts.Exchange.Topics.Withdraw.Hash:
+		r.dexWithdrawCallback(vLog)
+		return "Withdraw"
+	}
+
+	return ""
+}
 
-	publicKeyECDSA, ok := publicKey.(*ecdsa.PublicKey)
-	if !ok {
-		log.Fatal("error casting public key to ECDSA")
+// ServeHealth serves health checks of the database, redis and both chains
+// and metrics on the port. Relayer must be initialized.
+func (r *Relayer) ServeHealth(port uint64, maxBlockAge time.Duration) {
+	relayerHealth := health.NewHealth(config.ServiceRelayer)
+	relayerHealth.AddCheck("postgres", health.Postgres(r.store))
+	relayerHealth.AddCheck("redis", health.Redis(r.redisClient))
+	relayerHealth.AddCheck("bridge", health.Chain(r.bridge.client, maxBlockAge))
+	relayerHealth.AddCheck("exchange", health.Chain(r.exchange.client, maxBlockAge))
+	r.healthServer = relayerHealth.Serve(port, metrics.Wrap(http.NotFoundHandler()))
+}
+
+// Quit waits for event loops and periodic tasks to stop, then terminates
+// relayer instance. Their context must be done.
+func (r *Relayer) Quit() {
+	ctx, cancel := lifecycle.ShutdownContext()
+	defer cancel()
+
+	fmt.Printf("\nWaiting for event handlers to finish...\n")
+	if err := lifecycle.Wait(ctx, &r.wg); err != nil {
+		fmt.Println("Event handlers did not finish:", err)
 	}
 
-	fromAddress := crypto.PubkeyToAddress(*publicKeyECDSA)
+	fmt.Printf("\nCleaning up...\n")
+	if r.healthServer != nil {
+		r.healthServer.Shutdown(ctx)
+	}
+	r.redisClient.Close()
+	r.bridge.client.Close()
+	r.exchange.client.Close()
+	r.store.Close()
+	fmt.Printf("\nBye bye...\n")
+}
+
+// NewRelayer creates and populates a Relayer struct object
+func NewRelayer(cfg *config.Config, matcher signer.Signer) *Relayer {
+	fmt.Printf("Starting relayer...\n")
+
+	fromAddress := matcher.Address()
 
 	fmt.Printf("Order matcher account address: %s\n\n", fromAddress.String())
 
 	return &Relayer{
-		networks:  nwInfo,
-		contracts: contractsInfo,
+		networks:  cfg.Networks,
+		contracts: cfg.Contracts,
 		bridge: &bridgeRef{
 			client: nil,
 			// instance: nil,
@@ -272,11 +372,10 @@ func NewRelayer(contractsFilePath, networksFilePath, connectionString, redisHost
 			ordermatcherABI:      nil,
 			ordermatcherInstance: nil,
 		},
-		store:             store.NewDataStore(connectionString),
-		redisClient:       store.NewRedisClient(redisHostAddress, redisPassword),
-		matcherPrivateKey: privateKey,
-		matcherPublicKey:  publicKeyECDSA,
-		matcherAddress:    &fromAddress,
+		store:          store.NewDataStore(cfg.Database.ConnectionString),
+		redisClient:    store.NewRedisClient(cfg.Redis.Host, cfg.Redis.Password),
+		matcher:        matcher,
+		matcherAddress: &fromAddress,
 	}
 }
 
@@ -302,9 +401,51 @@ func (r *Relayer) balanceUpdateLogCallback(vLog types.Log) {
 	if err != nil {
 		log.Fatal("Commit: ", err)
 	}
+	r.publishToWallet(wallet.Address, "BALANCE_UPDATE", wallet)
+
 	fmt.Printf("\n\nUpdated %s token balance of wallet %s\n", buEvent.Token.Hex(), buEvent.User.Hex())
 }
 
+func (r *Relayer) depositLogCallback(vLog types.Log) {
+	depositEvent := struct {
+		Recipient       common.Address
+		Token           common.Address
+		Value           *big.Int
+		TransactionHash common.Hash
+	}{}
+	err := r.exchange.exchangeABI.Unpack(&depositEvent, "Deposit", vLog.Data)
+	if err != nil {
+		log.Fatal("Unpack: ", err)
+		return
+	}
+
+	deposit := models.Deposit{
+		TxHash:      wrappers.WrapHash(&depositEvent.TransactionHash),
+		Recipient:   wrappers.WrapAddress(&depositEvent.Recipient),
+		Token:       wrappers.WrapAddress(&depositEvent.Token),
+		Amount:      wrappers.WrapBigInt(depositEvent.Value),
+		DepositedAt: r.blockTime(vLog),
+	}
+	if err := deposit.Save(r.store); err != nil {
+		log.Fatal("Commit: ", err)
+	}
+
+	r.publishToWallet(deposit.Recipient, "DEPOSIT", deposit)
+
+	fmt.Printf("\n\nDeposited %s of token %s to wallet %s\n", depositEvent.Value.String(), depositEvent.Token.Hex(), depositEvent.Recipient.Hex())
+}
+
+// blockTime returns timestamp of the block of the log on exchange network.
+// Records are keyed on it, so a replayed event must get the same time.
+func (r *Relayer) blockTime(vLog types.Log) uint64 {
+	header, err := r.exchange.client.HeaderByNumber(context.Background(), new(big.Int).SetUint64(vLog.BlockNumber))
+	if err != nil {
+		log.Fatal("BLOCK_TIME", err)
+	}
+
+	return header.Time.Uint64()
+}
+
 func (r *Relayer) placeOrderLogCallback(vLog types.Log, isBid bool) {
 	placeOrderEvent := struct {
 		OrderHash common.Hash
@@ -353,6 +494,7 @@ func (r *Relayer) placeOrderLogCallback(vLog types.Log, isBid bool) {
 		fmt.Println("MARSHAL:", err)
 	}
 	r.redisClient.Publish(channelKey, marshalledResp)
+	r.publishToWallet(order.CreatedBy, "NEW_ORDER", order)
 
 	fmt.Printf("\n\nReceived order at %s for pair %s/%s\n", placeOrderEvent.Timestamp.String(), placeOrderEvent.Token.Hex(), placeOrderEvent.Base.Hex())
 
@@ -372,15 +514,22 @@ func (r *Relayer) cancelOrderLogCallback(vLog types.Log) {
 		Hash: wrappers.WrapHash(&cancelOrderEvent.OrderHash),
 	}
 
-	err = order.Close(r.store)
+	err = order.Get(r.store)
 	if err != nil {
-		log.Fatal("Commit: ", err)
+		log.Fatal("Cannot get order: ", err)
 		return
 	}
 
-	err = order.Get(r.store)
+	cancelledAt := r.blockTime(vLog)
+	fee := big.NewInt(0)
+	if cancelledAt < order.CreatedAt.Uint64()+cancelFeePeriod {
+		volumeLeft := new(big.Int).Sub(&order.Volume.Int, &order.VolumeFilled.Int)
+		fee = models.CalculateFee(volumeLeft, r.networks.Exchange.CancelFee)
+	}
+
+	err = order.Cancel(r.store, cancelledAt, fee)
 	if err != nil {
-		log.Fatal("Cannot get order: ", err)
+		log.Fatal("Commit: ", err)
 		return
 	}
 
@@ -394,6 +543,7 @@ func (r *Relayer) cancelOrderLogCallback(vLog types.Log) {
 		fmt.Println("MARSHAL:", err)
 	}
 	r.redisClient.Publish(channelKey, marshalledResp)
+	r.publishToWallet(order.CreatedBy, "CANCEL_ORDER", order)
 
 	fmt.Printf("\n\nOrder cancelled/filled %s\n", cancelOrderEvent.OrderHash.Hex())
 }
@@ -434,9 +584,12 @@ func (r *Relayer) tradeLogCallback(vLog types.Log) {
 		Volume:        wrappers.WrapBigInt(tradeEvent.Volume),
 		TradedAt:      (*(tradeEvent.Timestamp)).Uint64(),
 		TxHash:        wrappers.WrapHash(&vLog.TxHash),
+		LogIndex:      vLog.Index,
 		Token:         sellOrder.Token,
 		Base:          sellOrder.Base,
 		Price:         sellOrder.Price,
+		TakeFee:       wrappers.WrapBigInt(models.CalculateFee(tradeEvent.Volume, r.networks.Exchange.TakeFee)),
+		MakeFee:       wrappers.WrapBigInt(models.CalculateFee(tradeEvent.Volume, r.networks.Exchange.MakeFee)),
 	}
 	err = trade.Save(r.store)
 	if err != nil {
@@ -489,6 +642,7 @@ func (r *Relayer) updateFilledVolumeLogCallback(vLog types.Log) {
 		fmt.Println("MARSHAL:", err)
 	}
 	r.redisClient.Publish(channelKey, marshalledResp)
+	r.publishToWallet(order.CreatedBy, "ORDER_FILL", order)
 
 	fmt.Printf("\n\nUpdate filled volume of order %s to %s\n", updateFilledVolumeEvent.OrderHash.Hex(), updateFilledVolumeEvent.Volume.String())
 }
@@ -512,8 +666,12 @@ func (r *Relayer) withdrawSignSubmittedCallback(vLog types.Log) {
 	withdrawSign.Signer = wrappers.WrapAddress(&withdrawSignEvent.Authority)
 	withdrawSign.SignedAt = wrappers.WrapTimestamp((*withdrawSignEvent.Timestamp).Uint64())
 
-	_, _, _, txHash := utils.DeserializeMessage(withdrawSignEvent.Message)
-	withdrawSign.TxHash = txHash.Hex()
+	message, err := utils.DecodeWithdrawalMessage(withdrawSignEvent.Message)
+	if err != nil {
+		fmt.Println("DECODE", err)
+		return
+	}
+	withdrawSign.TxHash = message.TxHash.Hex()
 
 	if err := withdrawSign.Save(r.store); err != nil {
 		log.Fatal("COMMIT", err)
@@ -533,9 +691,13 @@ func (r *Relayer) readyToWithdrawCallback(vLog types.Log) {
 		return
 	}
 
-	_, _, _, txHash := utils.DeserializeMessage(withdrawEvent.Message)
+	message, err := utils.DecodeWithdrawalMessage(withdrawEvent.Message)
+	if err != nil {
+		fmt.Println("DECODE", err)
+		return
+	}
 	withdraw := models.NewWithdrawMeta()
-	withdraw.TxHash = wrappers.WrapHash(txHash)
+	withdraw.TxHash = wrappers.WrapHash(&message.TxHash)
 	// withdraw.Message = common.Bytes2Hex(withdrawEvent.Message)
 
 	if err := withdraw.Get(r.store); err != nil {
@@ -547,8 +709,16 @@ func (r *Relayer) readyToWithdrawCallback(vLog types.Log) {
 	withdraw.Status = models.WITHDRAW_STATUS_SIGNED
 
 	withdraw.UpdateStatus(r.store)
+	r.publishToWallet(withdraw.Recipient, "WITHDRAW_STATUS", withdraw)
 
 	fmt.Printf("\n\nWithdraw request %s is ready to be processed\n", withdraw.TxHash.Hex())
+
+	if r.withdrawRelay != nil {
+		if err := r.relayWithdraw(withdraw); err != nil {
+			// Will be retried by RunWithdrawRelay
+			fmt.Printf("Failed to relay withdraw request %s: %s\n", withdraw.TxHash.Hex(), err)
+		}
+	}
 }
 
 func (r *Relayer) dexWithdrawCallback(vLog types.Log) {
@@ -577,12 +747,15 @@ func (r *Relayer) dexWithdrawCallback(vLog types.Log) {
 	withdraw.TxHash = wrappers.WrapHash(&vLog.TxHash)
 	// withdraw.Message = common.Bytes2Hex(message)
 	withdraw.Status = models.WITHDRAW_STATUS_REQUESTED
+	withdraw.RequestedAt = r.blockTime(vLog)
 
 	if err := withdraw.Save(r.store); err != nil {
 		log.Fatal("COMMIT", err)
 		return
 	}
 
+	r.publishToWallet(withdraw.Recipient, "WITHDRAW_REQUEST", withdraw)
+
 	fmt.Printf("\n\nCreated new withdraw request for wallet %s from tx %s\n", withdraw.Recipient.Hex(), withdraw.TxHash.Hex())
 }
 
@@ -621,6 +794,7 @@ func (r *Relayer) bridgeWithdrawCallback(vLog types.Log) {
 
 	withdraw.Status = models.WITHDRAW_STATUS_PROCESSED
 	withdraw.UpdateStatus(r.store)
+	r.publishToWallet(withdraw.Recipient, "WITHDRAW_STATUS", withdraw)
 
 	fmt.Println("Withdraw processed: ", withdrawEvent.TransactionHash.Hex())
 	fmt.Println("--------------------")
@@ -645,6 +819,7 @@ func (r *Relayer) tryOrderMatching(order *models.Order) {
 				continue
 			}
 		}
+		matchesAttempted.Inc()
 		if order.IsBid {
 			err = r.submitMatchedOrder(
 				orderHash,
@@ -690,19 +865,38 @@ func (r *Relayer) tryOrderMatching(order *models.Order) {
 // 	return a
 // }
 
-func (r *Relayer) submitMatchedOrder(buyOrderHash [32]byte, sellOrderHash [32]byte) error {
-	nonce, err := r.exchange.client.PendingNonceAt(context.Background(), *r.matcherAddress)
+// watchMatch waits for the match transaction to be mined and counts whether
+// it succeeded
+func (r *Relayer) watchMatch(tx *types.Transaction) {
+	ctx, cancel := context.WithTimeout(context.Background(), matchReceiptTimeout)
+	defer cancel()
+
+	receipt, err := bind.WaitMined(ctx, r.exchange.client, tx)
 	if err != nil {
-		log.Fatal("MATCHER_NONCE", err)
-		return err
+		fmt.Println("MATCH_RECEIPT", tx.Hash().Hex(), err)
+		return
 	}
 
-	auth := bind.NewKeyedTransactor(r.matcherPrivateKey)
+	if receipt.Status == types.ReceiptStatusFailed {
+		fmt.Println("MATCH_REVERTED", tx.Hash().Hex())
+		matchesReverted.Inc()
+		return
+	}
 
-	auth.Nonce = big.NewInt(int64(nonce))
-	auth.Value = big.NewInt(0)
-	auth.GasLimit = uint64(500000)
-	auth.GasPrice = big.NewInt(0) // gasPrice
+	matchesSucceeded.Inc()
+}
+
+func (r *Relayer) submitMatchedOrder(buyOrderHash [32]byte, sellOrderHash [32]byte) error {
+	auth, err := r.exchange.matcherTransactor.Opts(
+		context.Background(),
+		r.contracts.OrderMatcher.Address.Address,
+		r.exchange.ordermatcherABI,
+		"matchOrders",
+		buyOrderHash, sellOrderHash,
+	)
+	if err != nil {
+		return err
+	}
 
 	tx, err := r.exchange.ordermatcherInstance.MatchOrders(
 		auth,
@@ -710,11 +904,14 @@ func (r *Relayer) submitMatchedOrder(buyOrderHash [32]byte, sellOrderHash [32]by
 		sellOrderHash,
 	)
 	if err != nil {
+		r.exchange.matcherTransactor.ResyncNonce()
 		return err
 	}
 
 	fmt.Println("tx match:", tx.Hash().Hex())
 
+	go r.watchMatch(tx)
+
 	// if receipt, err := r.exchange.client.TransactionReceipt(context.Background(), tx.Hash()); err != nil {
 	// 	return err
 	// } else if receipt.Status == 0 {
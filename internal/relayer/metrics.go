@@ -0,0 +1,33 @@
+package relayer
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"hameid.net/cdex/dex/internal/metrics"
+)
+
+var (
+	matchesAttempted = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "relayer",
+		Name:      "matches_attempted_total",
+		Help:      "Matches of orders submitted or failed to be submitted.",
+	})
+
+	matchesSucceeded = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "relayer",
+		Name:      "matches_succeeded_total",
+		Help:      "Match transactions mined successfully.",
+	})
+
+	matchesReverted = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "relayer",
+		Name:      "matches_reverted_total",
+		Help:      "Match transactions reverted.",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(matchesAttempted, matchesSucceeded, matchesReverted)
+}
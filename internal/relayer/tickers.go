@@ -0,0 +1,67 @@
+package relayer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"hameid.net/cdex/dex/internal/models"
+)
+
+// Interval of ticker updates published to socket channels
+const tickerInterval = 10 * time.Second
+
+// Channel with tickers of all markets
+const marketsChannelKey = "markets"
+
+// RunTickerPublisher periodically publishes ticker of each market on its
+// channel, and tickers of all markets on the markets channel, until ctx is
+// done
+func (r *Relayer) RunTickerPublisher(ctx context.Context) {
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+
+		ticker := time.NewTicker(tickerInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				r.publishTickers()
+			}
+		}
+	}()
+}
+
+func (r *Relayer) publishTickers() {
+	tickers, err := models.GetTickers(r.store)
+	if err != nil {
+		fmt.Println("TICKERS:", err)
+		return
+	}
+
+	for _, ticker := range tickers {
+		channelKey := strings.ToLower(ticker.Token.Hex() + "/" + ticker.Base.Hex())
+		r.publish(channelKey, "TICKER", ticker)
+	}
+
+	r.publish(marketsChannelKey, "TICKERS", tickers)
+}
+
+func (r *Relayer) publish(channelKey string, messageType string, payload interface{}) {
+	marshalledResp, err := json.Marshal(&redisChannelMessage{
+		MessageType: messageType,
+		Payload:     payload,
+	})
+	if err != nil {
+		fmt.Println("MARSHAL:", err)
+		return
+	}
+
+	r.redisClient.Publish(channelKey, marshalledResp)
+}
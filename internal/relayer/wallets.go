@@ -0,0 +1,15 @@
+package relayer
+
+import (
+	"strings"
+
+	"hameid.net/cdex/dex/internal/wrappers"
+)
+
+// Prefix of channels with updates of a wallet, only served to its sessions
+const walletChannelPrefix = "wallet/"
+
+// publishToWallet publishes message on the channel of the wallet
+func (r *Relayer) publishToWallet(address *wrappers.Address, messageType string, payload interface{}) {
+	r.publish(walletChannelPrefix+strings.ToLower(address.Hex()), messageType, payload)
+}
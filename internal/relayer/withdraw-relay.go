@@ -0,0 +1,136 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"hameid.net/cdex/dex/_abi/HomeBridge"
+	"hameid.net/cdex/dex/internal/models"
+	"hameid.net/cdex/dex/internal/signer"
+	"hameid.net/cdex/dex/internal/transactor"
+	"hameid.net/cdex/dex/internal/wrappers"
+)
+
+const (
+	// Time to wait for a relayed withdrawal to be processed before retrying
+	withdrawRelayRetryAfter = 10 * time.Minute
+
+	// Interval of checks for stuck withdrawals
+	withdrawRelayCheckInterval = time.Minute
+
+	// Give up after these many attempts
+	withdrawRelayMaxAttempts = 5
+)
+
+var errNotEnoughWithdrawSigns = errors.New("Not enough valid signatures for withdraw request")
+
+// withdrawRelay submits fully signed withdrawals to home bridge on behalf of users
+type withdrawRelay struct {
+	operator        signer.Signer
+	operatorAddress common.Address
+	instance        *HomeBridge.HomeBridge
+	transactor      *transactor.Transactor
+}
+
+// EnableWithdrawRelay makes relayer submit fully signed withdrawals to
+// home bridge from operator account. Must be called before Initialize.
+func (r *Relayer) EnableWithdrawRelay(operator signer.Signer) {
+	fmt.Printf("Withdraw relay operator account address: %s\n\n", operator.Address().String())
+
+	r.withdrawRelay = &withdrawRelay{
+		operator:        operator,
+		operatorAddress: operator.Address(),
+	}
+}
+
+// RunWithdrawRelay periodically retries withdrawals that are signed but not
+// processed yet, until ctx is done
+func (r *Relayer) RunWithdrawRelay(ctx context.Context) {
+	if r.withdrawRelay == nil {
+		return
+	}
+
+	fmt.Printf("Relaying signed withdrawals to Bridge contract %s...\n", r.contracts.Bridge.Address.Address.String())
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+
+		ticker := time.NewTicker(withdrawRelayCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			withdrawals, err := models.GetStuckWithdrawRequests(r.store, withdrawRelayRetryAfter, withdrawRelayMaxAttempts)
+			if err != nil {
+				fmt.Println("WITHDRAW_RELAY", err)
+				continue
+			}
+
+			for i := range withdrawals {
+				if err := r.relayWithdraw(&withdrawals[i]); err != nil {
+					fmt.Printf("Failed to relay withdraw request %s: %s\n", withdrawals[i].TxHash.Hex(), err)
+				}
+			}
+		}
+	}()
+}
+
+// relayWithdraw collects valid signatures of the withdraw request and submits
+// HomeBridge.withdraw, unless it is being relayed already
+func (r *Relayer) relayWithdraw(withdraw *models.WithdrawMeta) error {
+	bundle, err := models.GetWithdrawBundle(r.store, withdraw.TxHash, r.networks.Authorities, r.networks.Bridge.RequiredSignatures)
+	if err != nil {
+		return err
+	}
+
+	if !bundle.Ready {
+		return errNotEnoughWithdrawSigns
+	}
+
+	// Relayed from both the ticker and ReadyToWithdraw events
+	claimed, err := withdraw.ClaimRelay(r.store, withdrawRelayRetryAfter, withdrawRelayMaxAttempts)
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		return nil
+	}
+
+	vs, rs, ss, message := bundle.WithdrawArguments()
+
+	auth, err := r.withdrawRelay.transactor.Opts(
+		context.Background(),
+		r.contracts.Bridge.Address.Address,
+		r.bridge.abi,
+		"withdraw",
+		vs, rs, ss, message,
+	)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.withdrawRelay.instance.Withdraw(auth, vs, rs, ss, message)
+	if err != nil {
+		r.withdrawRelay.transactor.ResyncNonce()
+		return err
+	}
+
+	txHash := tx.Hash()
+	if err := withdraw.RecordRelayAttempt(r.store, wrappers.WrapHash(&txHash)); err != nil {
+		log.Println("Cannot record withdraw relay attempt:", err)
+	}
+
+	fmt.Printf("Relayed withdraw request %s to home network: %s\n", withdraw.TxHash.Hex(), txHash.Hex())
+
+	return nil
+}
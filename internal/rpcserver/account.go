@@ -0,0 +1,209 @@
+package rpcserver
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"hameid.net/cdex/dex/api/marketdata"
+	"hameid.net/cdex/dex/internal/auth"
+	"hameid.net/cdex/dex/internal/models"
+	"hameid.net/cdex/dex/internal/wrappers"
+)
+
+var errMissingSession = status.Error(codes.Unauthenticated, "`authorization` metadata with a session token is required")
+var errInvalidSession = status.Error(codes.Unauthenticated, auth.ErrInvalidSession.Error())
+var errInvalidTxHash = status.Error(codes.InvalidArgument, "`tx_hash` must be a hex hash")
+var errWithdrawRequestNotFound = status.Error(codes.NotFound, "Withdraw request not found")
+
+var txHashPattern = regexp.MustCompile("^0x[0-9A-Fa-f]{64}$")
+
+// accountService implements marketdata.AccountServer
+type accountService struct {
+	*RPCServer
+}
+
+// wallet returns the wallet of the session token in metadata
+func (service *accountService) wallet(ctx context.Context) (*common.Address, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	var token string
+	for _, value := range md.Get("authorization") {
+		if strings.HasPrefix(value, "Bearer ") {
+			token = strings.TrimSpace(strings.TrimPrefix(value, "Bearer "))
+		}
+	}
+	if token == "" {
+		return nil, errMissingSession
+	}
+
+	address, err := service.sessions.Verify(token)
+	switch err {
+	case nil:
+		return &address, nil
+	case auth.ErrInvalidSession:
+		return nil, errInvalidSession
+	default:
+		return nil, internalError("Verify", err)
+	}
+}
+
+func (service *accountService) GetBalances(ctx context.Context, req *marketdata.BalancesRequest) (*marketdata.Balances, error) {
+	wallet, err := service.wallet(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	balances, err := models.GetTokenBalancesForWallet(service.store, wrappers.WrapAddress(wallet))
+	if err != nil && err != sql.ErrNoRows {
+		return nil, internalError("GetBalances", err)
+	}
+
+	resp := &marketdata.Balances{
+		Wallet:   strings.ToLower(wallet.Hex()),
+		Balances: make([]*marketdata.Balance, 0, len(balances)),
+	}
+	for i := range balances {
+		resp.Balances = append(resp.Balances, toBalance(&balances[i]))
+	}
+
+	return resp, nil
+}
+
+func (service *accountService) GetWithdrawRequests(ctx context.Context, req *marketdata.WithdrawRequestsRequest) (*marketdata.WithdrawRequests, error) {
+	wallet, err := service.wallet(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	requests, err := models.GetUnprocessedWithdrawRequests(service.store, wrappers.WrapAddress(wallet))
+	if err != nil && err != sql.ErrNoRows {
+		return nil, internalError("GetWithdrawRequests", err)
+	}
+
+	resp := &marketdata.WithdrawRequests{Requests: make([]*marketdata.WithdrawRequest, 0, len(requests))}
+	for i := range requests {
+		resp.Requests = append(resp.Requests, toWithdrawRequest(&requests[i]))
+	}
+
+	return resp, nil
+}
+
+func (service *accountService) GetWithdrawStatus(ctx context.Context, req *marketdata.WithdrawStatusRequest) (*marketdata.WithdrawRequest, error) {
+	wallet, err := service.wallet(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !txHashPattern.MatchString(req.TxHash) {
+		return nil, errInvalidTxHash
+	}
+
+	txHash := common.HexToHash(req.TxHash)
+	withdraw := models.NewWithdrawMeta()
+	withdraw.TxHash = wrappers.WrapHash(&txHash)
+
+	switch err := withdraw.Get(service.store); err {
+	case nil:
+	case sql.ErrNoRows:
+		return nil, errWithdrawRequestNotFound
+	default:
+		return nil, internalError("GetWithdrawStatus", err)
+	}
+
+	// Requests of other wallets are not disclosed
+	if withdraw.Recipient.Address != *wallet {
+		return nil, errWithdrawRequestNotFound
+	}
+
+	return toWithdrawRequest(withdraw), nil
+}
+
+func (service *accountService) StreamAccount(req *marketdata.AccountStreamRequest, stream marketdata.Account_StreamAccountServer) error {
+	ctx := stream.Context()
+
+	wallet, err := service.wallet(ctx)
+	if err != nil {
+		return err
+	}
+
+	pubsub, err := service.subscribe(walletChannelKey(wallet))
+	if err != nil {
+		return internalError("StreamAccount", err)
+	}
+	defer pubsub.Close()
+
+	messages := pubsub.Channel()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-service.closing:
+			return errShuttingDown
+		case msg := <-messages:
+			message := decodeMessage(msg)
+			if message == nil {
+				continue
+			}
+
+			var event *marketdata.AccountEvent
+
+			switch message.MessageType {
+			case "BALANCE_UPDATE":
+				var balance models.Wallet
+				if !message.decode(&balance) {
+					continue
+				}
+				event = &marketdata.AccountEvent{
+					Event: &marketdata.AccountEvent_Balance{Balance: toBalance(&balance)},
+				}
+
+			case "WITHDRAW_REQUEST", "WITHDRAW_STATUS":
+				var withdraw models.WithdrawMeta
+				if !message.decode(&withdraw) {
+					continue
+				}
+				event = &marketdata.AccountEvent{
+					Event: &marketdata.AccountEvent_WithdrawRequest{WithdrawRequest: toWithdrawRequest(&withdraw)},
+				}
+
+			default:
+				continue
+			}
+
+			// Streams end with the session
+			if _, err := service.wallet(ctx); err != nil {
+				return err
+			}
+
+			if err := stream.Send(event); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func toBalance(wallet *models.Wallet) *marketdata.Balance {
+	return &marketdata.Balance{
+		Token:   addressString(wallet.Token),
+		Balance: bigIntString(wallet.Balance),
+		Escrow:  bigIntString(wallet.EscrowBalance),
+	}
+}
+
+func toWithdrawRequest(withdraw *models.WithdrawMeta) *marketdata.WithdrawRequest {
+	return &marketdata.WithdrawRequest{
+		TxHash:      hashString(withdraw.TxHash),
+		Token:       addressString(withdraw.Token),
+		Recipient:   addressString(withdraw.Recipient),
+		Amount:      bigIntString(withdraw.Amount),
+		Status:      marketdata.WithdrawRequest_Status(withdraw.Status),
+		RequestedAt: int64(withdraw.RequestedAt),
+	}
+}
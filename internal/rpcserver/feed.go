@@ -0,0 +1,62 @@
+package rpcserver
+
+import (
+	"encoding/json"
+	"log"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/go-redis/redis"
+)
+
+// Prefix of channels the relayer publishes updates of a wallet on
+const walletChannelPrefix = "wallet/"
+
+// channelMessage is a message the relayer publishes on redis channels
+type channelMessage struct {
+	MessageType string          `json:"messageType"`
+	Payload     json.RawMessage `json:"messageContent"`
+}
+
+func pairChannelKey(token, base *common.Address) string {
+	return strings.ToLower(token.Hex() + "/" + base.Hex())
+}
+
+func walletChannelKey(wallet *common.Address) string {
+	return walletChannelPrefix + strings.ToLower(wallet.Hex())
+}
+
+// subscribe subscribes to the channel and waits for redis to confirm, so
+// no message published after it returns is missed
+func (rpcServer *RPCServer) subscribe(channelKey string) (*redis.PubSub, error) {
+	pubsub := rpcServer.redisClient.Subscribe(channelKey)
+
+	if _, err := pubsub.Receive(); err != nil {
+		pubsub.Close()
+		return nil, err
+	}
+
+	return pubsub, nil
+}
+
+// decodeMessage returns the message of the relayer, or nil if it is not
+// valid
+func decodeMessage(msg *redis.Message) *channelMessage {
+	var message channelMessage
+	if err := json.Unmarshal([]byte(msg.Payload), &message); err != nil {
+		log.Println("RPC_FEED", err)
+		return nil
+	}
+
+	return &message
+}
+
+// decode unmarshals payload into v, logging invalid payloads
+func (message *channelMessage) decode(v interface{}) bool {
+	if err := json.Unmarshal(message.Payload, v); err != nil {
+		log.Println("RPC_FEED", message.MessageType, err)
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,46 @@
+package rpcserver
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"hameid.net/cdex/dex/internal/health"
+)
+
+// healthService implements the standard gRPC health service. The server
+// and each of its services are serving when all checks pass.
+type healthService struct {
+	*RPCServer
+}
+
+func (service *healthService) Check(ctx context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	switch req.Service {
+	case "", "marketdata.MarketData", "marketdata.Account":
+	default:
+		return nil, status.Error(codes.NotFound, "Unknown service")
+	}
+
+	report := service.health.Ready(ctx)
+	if report.Status != health.StatusOK {
+		var failed []string
+		for name, result := range report.Checks {
+			if result.Status != health.StatusOK {
+				failed = append(failed, name+": "+result.Error)
+			}
+		}
+		log.Printf("HEALTH %s", strings.Join(failed, ", "))
+
+		return &healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_NOT_SERVING}, nil
+	}
+
+	return &healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING}, nil
+}
+
+// Watch is not supported, clients poll Check instead
+func (service *healthService) Watch(req *healthpb.HealthCheckRequest, stream healthpb.Health_WatchServer) error {
+	return status.Error(codes.Unimplemented, "Watch is not supported")
+}
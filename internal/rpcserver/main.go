@@ -0,0 +1,174 @@
+package rpcserver
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/go-redis/redis"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"hameid.net/cdex/dex/api/marketdata"
+	"hameid.net/cdex/dex/internal/auth"
+	"hameid.net/cdex/dex/internal/config"
+	"hameid.net/cdex/dex/internal/health"
+	"hameid.net/cdex/dex/internal/lifecycle"
+	"hameid.net/cdex/dex/internal/metrics"
+	"hameid.net/cdex/dex/internal/store"
+)
+
+var errInternal = status.Error(codes.Internal, "Internal error")
+var errShuttingDown = status.Error(codes.Unavailable, "Server is shutting down")
+
+// RPCServer serves market data and accounts over gRPC
+type RPCServer struct {
+	server      *grpc.Server
+	port        string
+	metricsPort uint64
+	store       *store.DataStore
+	redisClient *redis.Client
+	sessions    *auth.Sessions
+	health      *health.Health
+
+	// Closed when the server shuts down, to end streams
+	closing      chan struct{}
+	healthServer *http.Server
+
+	settingsMu  sync.RWMutex
+	markets     map[[2]common.Address]config.MarketConfig
+	pageSize    int
+	maxPageSize int
+}
+
+// Run serves gRPC until ctx is done, then ends streams and waits for
+// in-flight calls
+func (rpcServer *RPCServer) Run(ctx context.Context) error {
+	rpcServer.store.Initialize()
+
+	listener, err := net.Listen("tcp", rpcServer.port)
+	if err != nil {
+		return err
+	}
+
+	marketdata.RegisterMarketDataServer(rpcServer.server, &marketDataService{rpcServer})
+	marketdata.RegisterAccountServer(rpcServer.server, &accountService{rpcServer})
+	healthpb.RegisterHealthServer(rpcServer.server, &healthService{rpcServer})
+
+	rpcServer.healthServer = rpcServer.health.Serve(rpcServer.metricsPort, metrics.Wrap(http.NotFoundHandler()))
+
+	fmt.Printf("Running gRPC server on address %s\n", rpcServer.port)
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- rpcServer.server.Serve(listener)
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+
+	return rpcServer.shutdown()
+}
+
+// shutdown stops accepting calls, ends streams and waits for other calls.
+// Calls still running when the shutdown timeout expires are cancelled.
+func (rpcServer *RPCServer) shutdown() error {
+	ctx, cancel := lifecycle.ShutdownContext()
+	defer cancel()
+
+	fmt.Println("Draining gRPC server...")
+	close(rpcServer.closing)
+
+	stopped := make(chan struct{})
+	go func() {
+		rpcServer.server.GracefulStop()
+		close(stopped)
+	}()
+
+	var err error
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		err = ctx.Err()
+		rpcServer.server.Stop()
+	}
+
+	rpcServer.healthServer.Shutdown(ctx)
+	rpcServer.redisClient.Close()
+	rpcServer.store.Close()
+
+	return err
+}
+
+// Reload applies reloadable settings of the config
+func (rpcServer *RPCServer) Reload(cfg *config.Config) {
+	markets := make(map[[2]common.Address]config.MarketConfig, len(cfg.Markets))
+	for _, market := range cfg.Markets {
+		markets[[2]common.Address{market.Token, market.Base}] = market
+	}
+
+	rpcServer.settingsMu.Lock()
+	rpcServer.markets = markets
+	rpcServer.pageSize = int(cfg.App.PageSize)
+	rpcServer.maxPageSize = int(cfg.App.MaxPageSize)
+	rpcServer.settingsMu.Unlock()
+
+	fmt.Printf("gRPC server settings: %d markets\n", len(markets))
+}
+
+// pageSizes returns default and maximum number of items in a page
+func (rpcServer *RPCServer) pageSizes() (int, int) {
+	rpcServer.settingsMu.RLock()
+	defer rpcServer.settingsMu.RUnlock()
+
+	return rpcServer.pageSize, rpcServer.maxPageSize
+}
+
+// isMarketAvailable tells if the pair is served. All pairs are served if
+// no market is configured.
+func (rpcServer *RPCServer) isMarketAvailable(token, base *common.Address) bool {
+	rpcServer.settingsMu.RLock()
+	defer rpcServer.settingsMu.RUnlock()
+
+	if len(rpcServer.markets) == 0 {
+		return true
+	}
+
+	market, ok := rpcServer.markets[[2]common.Address{*token, *base}]
+	return ok && !market.Disabled
+}
+
+// internalError logs err and returns the error reported to clients
+func internalError(method string, err error) error {
+	log.Printf("ERROR %s: %s", method, err)
+	return errInternal
+}
+
+// NewRPCServer creates new instance of RPCServer
+func NewRPCServer(cfg *config.Config) *RPCServer {
+	redisClient := store.NewRedisClient(cfg.Redis.Host, cfg.Redis.Password)
+
+	rpcServer := &RPCServer{
+		server:      grpc.NewServer(),
+		port:        fmt.Sprintf(":%d", cfg.RPCServer.Port),
+		metricsPort: cfg.RPCServer.MetricsPort,
+		store:       store.NewDataStore(cfg.Database.ConnectionString),
+		redisClient: redisClient,
+		sessions:    auth.NewSessions(redisClient, cfg.Auth.SessionTTL, cfg.Auth.NonceTTL),
+		health:      health.NewHealth(config.ServiceRPCServer),
+		closing:     make(chan struct{}),
+	}
+	rpcServer.health.AddCheck("postgres", health.Postgres(rpcServer.store))
+	rpcServer.health.AddCheck("redis", health.Redis(redisClient))
+	rpcServer.Reload(cfg)
+
+	return rpcServer
+}
@@ -0,0 +1,325 @@
+package rpcserver
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"hameid.net/cdex/dex/api/marketdata"
+	"hameid.net/cdex/dex/internal/models"
+	"hameid.net/cdex/dex/internal/wrappers"
+)
+
+var errMarketRequired = status.Error(codes.InvalidArgument, "`market` with token and base addresses is required")
+var errMarketNotListed = status.Error(codes.NotFound, "Market is not listed")
+var errInvalidDepth = status.Error(codes.InvalidArgument, fmt.Sprintf("`depth` must be at most %d", models.MaxOrderbookDepth))
+var errInvalidCursor = status.Error(codes.InvalidArgument, "Invalid `cursor`")
+var errInvalidResolution = status.Error(codes.InvalidArgument, "`resolution` must be one of 1m, 5m, 15m, 1h, 4h, 1d and 1w")
+var errFromRequired = status.Error(codes.InvalidArgument, "`from` is required")
+
+// marketDataService implements marketdata.MarketDataServer
+type marketDataService struct {
+	*RPCServer
+}
+
+// market returns token and base of a listed market
+func (service *marketDataService) market(market *marketdata.Market) (*common.Address, *common.Address, error) {
+	if market == nil || !isHexAddress(market.Token) || !isHexAddress(market.Base) {
+		return nil, nil, errMarketRequired
+	}
+
+	token := common.HexToAddress(market.Token)
+	base := common.HexToAddress(market.Base)
+
+	if !service.isMarketAvailable(&token, &base) {
+		return nil, nil, errMarketNotListed
+	}
+
+	return &token, &base, nil
+}
+
+func (service *marketDataService) orderbook(token, base *common.Address, depth uint32) (*marketdata.Orderbook, error) {
+	params := map[string]interface{}{
+		"token": token.Hex(),
+		"base":  base.Hex(),
+	}
+	if depth > 0 {
+		params["depth"] = int(depth)
+	}
+
+	orderbook, err := models.GetOrderbook(service.store, &params)
+	if err != nil {
+		return nil, internalError("GetOrderbook", err)
+	}
+
+	return &marketdata.Orderbook{
+		Market:    toMarket(token, base),
+		Bids:      toOrderbookLevels(orderbook.Bids),
+		Asks:      toOrderbookLevels(orderbook.Asks),
+		LastPrice: bigIntString(orderbook.LastPrice),
+	}, nil
+}
+
+func (service *marketDataService) GetOrderbook(ctx context.Context, req *marketdata.OrderbookRequest) (*marketdata.Orderbook, error) {
+	token, base, err := service.market(req.Market)
+	if err != nil {
+		return nil, err
+	}
+	if req.Depth > models.MaxOrderbookDepth {
+		return nil, errInvalidDepth
+	}
+
+	return service.orderbook(token, base, req.Depth)
+}
+
+func (service *marketDataService) StreamOrderbook(req *marketdata.OrderbookRequest, stream marketdata.MarketData_StreamOrderbookServer) error {
+	token, base, err := service.market(req.Market)
+	if err != nil {
+		return err
+	}
+	if req.Depth > models.MaxOrderbookDepth {
+		return errInvalidDepth
+	}
+
+	pubsub, err := service.subscribe(pairChannelKey(token, base))
+	if err != nil {
+		return internalError("StreamOrderbook", err)
+	}
+	defer pubsub.Close()
+
+	messages := pubsub.Channel()
+	ctx := stream.Context()
+
+	for {
+		orderbook, err := service.orderbook(token, base, req.Depth)
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(orderbook); err != nil {
+			return err
+		}
+
+		// Wait for a change of orders, then skip changes already queued as
+		// the next snapshot holds them
+		changed := false
+		for !changed {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-service.closing:
+				return errShuttingDown
+			case msg := <-messages:
+				message := decodeMessage(msg)
+				changed = message != nil && isOrderChange(message.MessageType)
+			}
+		}
+		for queued := true; queued; {
+			select {
+			case <-messages:
+			default:
+				queued = false
+			}
+		}
+	}
+}
+
+func isOrderChange(messageType string) bool {
+	switch messageType {
+	case "NEW_ORDER", "CANCEL_ORDER", "ORDER_FILL":
+		return true
+	}
+	return false
+}
+
+func (service *marketDataService) GetTrades(ctx context.Context, req *marketdata.TradesRequest) (*marketdata.TradesPage, error) {
+	token, base, err := service.market(req.Market)
+	if err != nil {
+		return nil, err
+	}
+
+	count, maxCount := service.pageSizes()
+	if req.Count > 0 {
+		count = int(req.Count)
+		if count > maxCount {
+			count = maxCount
+		}
+	}
+
+	var cursor *models.Cursor
+	if req.Cursor != "" {
+		if cursor, err = models.DecodeCursor(req.Cursor, 1); err != nil {
+			return nil, errInvalidCursor
+		}
+	}
+
+	page, err := models.GetTradeHistory(service.store, token, base, count, cursor)
+	if err != nil {
+		return nil, internalError("GetTrades", err)
+	}
+
+	history := page.Items.([]models.TradeHistoryResponse)
+	trades := make([]*marketdata.Trade, 0, len(history))
+	for _, trade := range history {
+		trades = append(trades, &marketdata.Trade{
+			Market:   toMarket(token, base),
+			Price:    bigIntString(trade.Price),
+			Volume:   bigIntString(trade.Volume),
+			TradedAt: trade.Timestamp.Unix(),
+		})
+	}
+
+	resp := &marketdata.TradesPage{Trades: trades}
+	if page.NextCursor != nil {
+		resp.NextCursor = *page.NextCursor
+	}
+
+	return resp, nil
+}
+
+func (service *marketDataService) StreamTrades(req *marketdata.Market, stream marketdata.MarketData_StreamTradesServer) error {
+	token, base, err := service.market(req)
+	if err != nil {
+		return err
+	}
+
+	pubsub, err := service.subscribe(pairChannelKey(token, base))
+	if err != nil {
+		return internalError("StreamTrades", err)
+	}
+	defer pubsub.Close()
+
+	messages := pubsub.Channel()
+	ctx := stream.Context()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-service.closing:
+			return errShuttingDown
+		case msg := <-messages:
+			message := decodeMessage(msg)
+			if message == nil || message.MessageType != "TRADE" {
+				continue
+			}
+
+			var trade models.Trade
+			if !message.decode(&trade) {
+				continue
+			}
+
+			err := stream.Send(&marketdata.Trade{
+				Market:        toMarket(token, base),
+				Price:         bigIntString(trade.Price),
+				Volume:        bigIntString(trade.Volume),
+				TradedAt:      int64(trade.TradedAt),
+				BuyOrderHash:  hashString(trade.BuyOrderHash),
+				SellOrderHash: hashString(trade.SellOrderHash),
+				TxHash:        hashString(trade.TxHash),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (service *marketDataService) GetCandles(ctx context.Context, req *marketdata.CandlesRequest) (*marketdata.Candles, error) {
+	token, base, err := service.market(req.Market)
+	if err != nil {
+		return nil, err
+	}
+
+	resolution, ok := models.OHLCResolutions[req.Resolution]
+	if !ok {
+		return nil, errInvalidResolution
+	}
+	if req.From <= 0 {
+		return nil, errFromRequired
+	}
+
+	to := time.Now()
+	if req.To > 0 {
+		to = time.Unix(req.To, 0)
+	}
+
+	candles, err := models.GetOHLCData(service.store, token, base, resolution, time.Unix(req.From, 0), to)
+	switch err {
+	case nil:
+	case models.ErrTooManyOHLCBuckets, models.ErrInvalidTimeRange:
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	default:
+		return nil, internalError("GetCandles", err)
+	}
+
+	resp := &marketdata.Candles{Candles: make([]*marketdata.Candle, 0, len(candles))}
+	for _, candle := range candles {
+		resp.Candles = append(resp.Candles, &marketdata.Candle{
+			Time:   candle.Time,
+			Open:   bigIntString(candle.Open),
+			High:   bigIntString(candle.High),
+			Low:    bigIntString(candle.Low),
+			Close:  bigIntString(candle.Close),
+			Volume: bigIntString(candle.Volume),
+		})
+	}
+
+	return resp, nil
+}
+
+func toMarket(token, base *common.Address) *marketdata.Market {
+	return &marketdata.Market{
+		Token: strings.ToLower(token.Hex()),
+		Base:  strings.ToLower(base.Hex()),
+	}
+}
+
+func toOrderbookLevels(items *[]models.OrderbookResponseItem) []*marketdata.OrderbookLevel {
+	if items == nil {
+		return nil
+	}
+
+	levels := make([]*marketdata.OrderbookLevel, 0, len(*items))
+	for _, item := range *items {
+		levels = append(levels, &marketdata.OrderbookLevel{
+			Price:              bigIntString(item.Price),
+			Quantity:           bigIntString(item.Quantity),
+			Volume:             bigIntString(item.Volume),
+			CumulativeQuantity: bigIntString(item.CumulativeQuantity),
+			CumulativeVolume:   bigIntString(item.CumulativeVolume),
+			Orders:             item.Orders,
+		})
+	}
+
+	return levels
+}
+
+func isHexAddress(s string) bool {
+	return strings.HasPrefix(s, "0x") && common.IsHexAddress(s)
+}
+
+// bigIntString returns i in base 10, or empty string if it is nil
+func bigIntString(i *wrappers.BigInt) string {
+	if i == nil {
+		return ""
+	}
+	return i.String()
+}
+
+func addressString(address *wrappers.Address) string {
+	if address == nil {
+		return ""
+	}
+	return strings.ToLower(address.Hex())
+}
+
+func hashString(hash *wrappers.Hash) string {
+	if hash == nil {
+		return ""
+	}
+	return strings.ToLower(hash.Hex())
+}
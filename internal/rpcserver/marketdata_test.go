@@ -0,0 +1,55 @@
+package rpcserver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"hameid.net/cdex/dex/api/marketdata"
+	"hameid.net/cdex/dex/internal/config"
+)
+
+func TestMarketValidation(t *testing.T) {
+	token := common.HexToAddress("0xd8912c10681d8b21fd3742244f44658dba12264e")
+	base := common.Address{}
+
+	rpcServer := &RPCServer{}
+	rpcServer.Reload(&config.Config{Markets: []config.MarketConfig{{Token: token, Base: base}}})
+	service := &marketDataService{rpcServer}
+
+	tests := []struct {
+		market *marketdata.Market
+		code   codes.Code
+	}{
+		{nil, codes.InvalidArgument},
+		{&marketdata.Market{Token: "d8912c10681d8b21fd3742244f44658dba12264e", Base: base.Hex()}, codes.InvalidArgument},
+		{&marketdata.Market{Token: base.Hex(), Base: token.Hex()}, codes.NotFound},
+		{&marketdata.Market{Token: token.Hex(), Base: base.Hex()}, codes.OK},
+	}
+
+	for _, test := range tests {
+		_, _, err := service.market(test.market)
+		if code := status.Code(err); code != test.code {
+			t.Errorf("market %v: got %s, want %s", test.market, code, test.code)
+		}
+	}
+
+	_, err := service.GetOrderbook(context.Background(), &marketdata.OrderbookRequest{
+		Market: &marketdata.Market{Token: token.Hex(), Base: base.Hex()},
+		Depth:  100000,
+	})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("depth over maximum: got %v", err)
+	}
+}
+
+func TestAccountRequiresSession(t *testing.T) {
+	service := &accountService{&RPCServer{}}
+
+	_, err := service.GetBalances(context.Background(), &marketdata.BalancesRequest{})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("got %v, want Unauthenticated", err)
+	}
+}
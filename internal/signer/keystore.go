@@ -0,0 +1,55 @@
+package signer
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"hameid.net/cdex/dex/internal/utils"
+)
+
+// KeystoreSigner signs with a private key decrypted from a keystore file
+type KeystoreSigner struct {
+	privateKey *ecdsa.PrivateKey
+	address    common.Address
+}
+
+// Address returns the account of the signer
+func (s *KeystoreSigner) Address() common.Address {
+	return s.address
+}
+
+// SignMessage signs keccak256 hash of message
+func (s *KeystoreSigner) SignMessage(message []byte) (utils.Signature, error) {
+	return utils.SignMessageWithPrivateKey(message, s.privateKey)
+}
+
+// SignTypedWithdrawal signs EIP-712 typed data of a withdrawal message
+func (s *KeystoreSigner) SignTypedWithdrawal(message *utils.WithdrawalMessage) (utils.Signature, error) {
+	return utils.SignTypedWithdrawalWithPrivateKey(message, s.privateKey)
+}
+
+// SignTx signs a transaction
+func (s *KeystoreSigner) SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return types.SignTx(tx, txSigner(chainID), s.privateKey)
+}
+
+// NewKeystoreSigner decrypts the keystore with the password stored in passwordFilePath
+func NewKeystoreSigner(keystoreFilePath, passwordFilePath string) (*KeystoreSigner, error) {
+	accountKey, err := utils.DecryptPrivateKeyFromKeystoreWithPasswordFile(keystoreFilePath, passwordFilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewPrivateKeySigner(accountKey.PrivateKey), nil
+}
+
+// NewPrivateKeySigner creates a signer from an already decrypted key
+func NewPrivateKeySigner(privateKey *ecdsa.PrivateKey) *KeystoreSigner {
+	return &KeystoreSigner{
+		privateKey: privateKey,
+		address:    crypto.PubkeyToAddress(privateKey.PublicKey),
+	}
+}
@@ -0,0 +1,67 @@
+package signer
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"hameid.net/cdex/dex/internal/utils"
+)
+
+// MockSigner signs with a throwaway key and remembers everything it signed.
+// Meant for tests only.
+type MockSigner struct {
+	mu         sync.Mutex
+	privateKey *ecdsa.PrivateKey
+
+	Messages     [][]byte
+	Transactions []*types.Transaction
+}
+
+// Address returns the account of the signer
+func (s *MockSigner) Address() common.Address {
+	return crypto.PubkeyToAddress(s.privateKey.PublicKey)
+}
+
+// SignMessage signs keccak256 hash of message
+func (s *MockSigner) SignMessage(message []byte) (utils.Signature, error) {
+	s.mu.Lock()
+	s.Messages = append(s.Messages, message)
+	s.mu.Unlock()
+
+	return utils.SignMessageWithPrivateKey(message, s.privateKey)
+}
+
+// SignTypedWithdrawal signs EIP-712 typed data of a withdrawal message
+func (s *MockSigner) SignTypedWithdrawal(message *utils.WithdrawalMessage) (utils.Signature, error) {
+	return utils.SignTypedWithdrawalWithPrivateKey(message, s.privateKey)
+}
+
+// SignTx signs a transaction
+func (s *MockSigner) SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	signedTx, err := types.SignTx(tx, txSigner(chainID), s.privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.Transactions = append(s.Transactions, signedTx)
+	s.mu.Unlock()
+
+	return signedTx, nil
+}
+
+// NewMockSigner creates a signer with a freshly generated key
+func NewMockSigner() *MockSigner {
+	privateKey, err := crypto.GenerateKey()
+	if err != nil {
+		panic(err)
+	}
+
+	return &MockSigner{
+		privateKey: privateKey,
+	}
+}
@@ -0,0 +1,212 @@
+package signer
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+	"hameid.net/cdex/dex/internal/utils"
+)
+
+// Content type of `account_signData` requests. Unlike clef's `data/plain`,
+// the message is hashed with keccak256 without the EIP-191 prefix, which is
+// what the bridge contracts verify.
+const rawDataContentType = "data/raw"
+
+var errInvalidRemoteSignature = errors.New("Remote signer returned an invalid signature")
+
+var errNoRemoteAccount = errors.New("Remote signer has no accounts")
+
+// RemoteSigner delegates signing to a separate process that speaks a
+// clef-like JSON-RPC protocol over HTTP or a Unix socket:
+//
+//	account_list() -> [address]
+//	account_signData(contentType, address, data) -> signature
+//	account_signTypedData(address, typedData) -> signature
+//	account_signTransaction(txArgs) -> {raw, tx}
+type RemoteSigner struct {
+	client  *rpc.Client
+	address common.Address
+}
+
+type remoteTxArgs struct {
+	From     common.Address  `json:"from"`
+	To       *common.Address `json:"to"`
+	Gas      hexutil.Uint64  `json:"gas"`
+	GasPrice hexutil.Big     `json:"gasPrice"`
+	Value    hexutil.Big     `json:"value"`
+	Nonce    hexutil.Uint64  `json:"nonce"`
+	Data     hexutil.Bytes   `json:"data"`
+	ChainID  *hexutil.Big    `json:"chainId,omitempty"`
+}
+
+type remoteSignTxResult struct {
+	Raw hexutil.Bytes `json:"raw"`
+}
+
+// Address returns the account of the signer
+func (s *RemoteSigner) Address() common.Address {
+	return s.address
+}
+
+// SignMessage asks the remote signer to sign keccak256 hash of message
+func (s *RemoteSigner) SignMessage(message []byte) (utils.Signature, error) {
+	var signature hexutil.Bytes
+
+	err := s.client.Call(&signature, "account_signData", rawDataContentType, s.address, hexutil.Bytes(message))
+	if err != nil {
+		return utils.Signature{}, err
+	}
+
+	return s.checkSignature(crypto.Keccak256(message), signature)
+}
+
+// SignTypedWithdrawal asks the remote signer to sign EIP-712 typed data of a
+// withdrawal message
+func (s *RemoteSigner) SignTypedWithdrawal(message *utils.WithdrawalMessage) (utils.Signature, error) {
+	hash, err := message.TypedDataHash()
+	if err != nil {
+		return utils.Signature{}, err
+	}
+
+	typedData := map[string]interface{}{
+		"types": map[string]interface{}{
+			"EIP712Domain": []map[string]string{
+				{"name": "name", "type": "string"},
+				{"name": "version", "type": "string"},
+				{"name": "chainId", "type": "uint256"},
+				{"name": "verifyingContract", "type": "address"},
+			},
+			"Withdrawal": []map[string]string{
+				{"name": "recipient", "type": "address"},
+				{"name": "token", "type": "address"},
+				{"name": "amount", "type": "uint256"},
+				{"name": "transactionHash", "type": "bytes32"},
+			},
+		},
+		"primaryType": "Withdrawal",
+		"domain": map[string]interface{}{
+			"name":              utils.WithdrawalDomainName,
+			"version":           utils.WithdrawalDomainVersion,
+			"chainId":           message.ChainID.String(),
+			"verifyingContract": message.Contract,
+		},
+		"message": map[string]interface{}{
+			"recipient":       message.Recipient,
+			"token":           message.Token,
+			"amount":          message.Amount.String(),
+			"transactionHash": message.TxHash,
+		},
+	}
+
+	var signature hexutil.Bytes
+
+	if err := s.client.Call(&signature, "account_signTypedData", s.address, typedData); err != nil {
+		return utils.Signature{}, err
+	}
+
+	return s.checkSignature(hash, signature)
+}
+
+// checkSignature makes sure the remote end signed hash with the expected account
+func (s *RemoteSigner) checkSignature(hash []byte, signature []byte) (utils.Signature, error) {
+	if len(signature) != 65 {
+		return utils.Signature{}, errInvalidRemoteSignature
+	}
+
+	// clef returns V as 27/28, keep it in [R || S || V] form used by crypto.Sign
+	if signature[64] >= 27 {
+		signature[64] -= 27
+	}
+
+	pubKey, err := crypto.SigToPub(hash, signature)
+	if err != nil {
+		return utils.Signature{}, err
+	}
+	if crypto.PubkeyToAddress(*pubKey) != s.address {
+		return utils.Signature{}, errInvalidRemoteSignature
+	}
+
+	return utils.NewSignature(hash, signature), nil
+}
+
+// SignTx asks the remote signer to sign the transaction
+func (s *RemoteSigner) SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	args := remoteTxArgs{
+		From:     s.address,
+		To:       tx.To(),
+		Gas:      hexutil.Uint64(tx.Gas()),
+		GasPrice: hexutil.Big(*tx.GasPrice()),
+		Value:    hexutil.Big(*tx.Value()),
+		Nonce:    hexutil.Uint64(tx.Nonce()),
+		Data:     tx.Data(),
+	}
+	if chainID != nil {
+		args.ChainID = (*hexutil.Big)(chainID)
+	}
+
+	var result remoteSignTxResult
+	if err := s.client.Call(&result, "account_signTransaction", args); err != nil {
+		return nil, err
+	}
+
+	signedTx := new(types.Transaction)
+	if err := rlp.DecodeBytes(result.Raw, signedTx); err != nil {
+		return nil, err
+	}
+
+	sender, err := types.Sender(txSigner(chainID), signedTx)
+	if err != nil {
+		return nil, err
+	}
+	if sender != s.address || !sameTransaction(tx, signedTx) {
+		return nil, errInvalidRemoteSignature
+	}
+
+	return signedTx, nil
+}
+
+// sameTransaction checks that the remote signer did not alter the transaction
+func sameTransaction(a, b *types.Transaction) bool {
+	if (a.To() == nil) != (b.To() == nil) || (a.To() != nil && *a.To() != *b.To()) {
+		return false
+	}
+
+	return a.Nonce() == b.Nonce() &&
+		a.Gas() == b.Gas() &&
+		a.GasPrice().Cmp(b.GasPrice()) == 0 &&
+		a.Value().Cmp(b.Value()) == 0 &&
+		bytes.Equal(a.Data(), b.Data())
+}
+
+// NewRemoteSigner connects to the remote signer. endpoint is either an
+// HTTP URL or a path to a Unix socket. If address is zero, the first account
+// of the remote signer is used.
+func NewRemoteSigner(endpoint string, address common.Address) (*RemoteSigner, error) {
+	client, err := rpc.Dial(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	if address == (common.Address{}) {
+		var accounts []common.Address
+		if err := client.Call(&accounts, "account_list"); err != nil {
+			return nil, err
+		}
+		if len(accounts) == 0 {
+			return nil, errNoRemoteAccount
+		}
+		address = accounts[0]
+	}
+
+	return &RemoteSigner{
+		client:  client,
+		address: address,
+	}, nil
+}
@@ -0,0 +1,272 @@
+package signer
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+	"hameid.net/cdex/dex/internal/utils"
+)
+
+// SignTxArgs are the arguments of account_signTransaction
+type SignTxArgs struct {
+	From     common.Address  `json:"from"`
+	To       *common.Address `json:"to"`
+	Gas      hexutil.Uint64  `json:"gas"`
+	GasPrice hexutil.Big     `json:"gasPrice"`
+	Value    hexutil.Big     `json:"value"`
+	Nonce    hexutil.Uint64  `json:"nonce"`
+	Data     hexutil.Bytes   `json:"data"`
+	ChainID  *hexutil.Big    `json:"chainId"`
+}
+
+// SignTxResult is the result of account_signTransaction
+type SignTxResult struct {
+	Raw hexutil.Bytes `json:"raw"`
+}
+
+// AccountService fakes the account_ methods of a clef-like signer
+type AccountService struct {
+	key *ecdsa.PrivateKey
+
+	// Changes the transaction before it is signed
+	tamper func(args *SignTxArgs)
+}
+
+func (s *AccountService) List() []common.Address {
+	return []common.Address{crypto.PubkeyToAddress(s.key.PublicKey)}
+}
+
+func (s *AccountService) SignData(contentType string, address common.Address, data hexutil.Bytes) (hexutil.Bytes, error) {
+	if contentType != rawDataContentType {
+		return nil, fmt.Errorf("unexpected content type %s", contentType)
+	}
+	return s.sign(crypto.Keccak256(data))
+}
+
+func (s *AccountService) SignTypedData(address common.Address, typedData map[string]interface{}) (hexutil.Bytes, error) {
+	domain, _ := typedData["domain"].(map[string]interface{})
+	values, _ := typedData["message"].(map[string]interface{})
+	if domain == nil || values == nil {
+		return nil, errors.New("typed data has no domain or message")
+	}
+
+	chainID, _ := new(big.Int).SetString(fmt.Sprint(domain["chainId"]), 10)
+	amount, _ := new(big.Int).SetString(fmt.Sprint(values["amount"]), 10)
+	if chainID == nil || amount == nil {
+		return nil, errors.New("typed data has no chain ID or amount")
+	}
+
+	message := &utils.WithdrawalMessage{
+		Version:   1,
+		ChainID:   chainID,
+		Contract:  common.HexToAddress(fmt.Sprint(domain["verifyingContract"])),
+		Recipient: common.HexToAddress(fmt.Sprint(values["recipient"])),
+		Token:     common.HexToAddress(fmt.Sprint(values["token"])),
+		Amount:    amount,
+		TxHash:    common.HexToHash(fmt.Sprint(values["transactionHash"])),
+	}
+
+	hash, err := message.TypedDataHash()
+	if err != nil {
+		return nil, err
+	}
+	return s.sign(hash)
+}
+
+func (s *AccountService) SignTransaction(args SignTxArgs) (*SignTxResult, error) {
+	if s.tamper != nil {
+		s.tamper(&args)
+	}
+
+	var chainID *big.Int
+	if args.ChainID != nil {
+		chainID = (*big.Int)(args.ChainID)
+	}
+
+	tx := types.NewTransaction(uint64(args.Nonce), *args.To, (*big.Int)(&args.Value), uint64(args.Gas), (*big.Int)(&args.GasPrice), args.Data)
+	signedTx, err := types.SignTx(tx, txSigner(chainID), s.key)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := rlp.EncodeToBytes(signedTx)
+	if err != nil {
+		return nil, err
+	}
+	return &SignTxResult{Raw: raw}, nil
+}
+
+// sign signs the hash with V as 27/28, like clef
+func (s *AccountService) sign(hash []byte) (hexutil.Bytes, error) {
+	signature, err := crypto.Sign(hash, s.key)
+	if err != nil {
+		return nil, err
+	}
+	signature[64] += 27
+	return signature, nil
+}
+
+// newTestRemoteSigner serves a signer holding key and connects to it over
+// HTTP. address is the account the remote signer is expected to sign with,
+// the first remote account if zero.
+func newTestRemoteSigner(t *testing.T, service *AccountService, address common.Address) (*RemoteSigner, func()) {
+	server := rpc.NewServer()
+	if err := server.RegisterName("account", service); err != nil {
+		t.Fatal(err)
+	}
+	httpServer := httptest.NewServer(server)
+
+	s, err := NewRemoteSigner(httpServer.URL, address)
+	if err != nil {
+		httpServer.Close()
+		t.Fatal(err)
+	}
+
+	return s, func() {
+		s.client.Close()
+		httpServer.Close()
+		server.Stop()
+	}
+}
+
+func newTestKey(t *testing.T) *ecdsa.PrivateKey {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func testWithdrawalMessage() *utils.WithdrawalMessage {
+	return &utils.WithdrawalMessage{
+		Version:   1,
+		ChainID:   big.NewInt(1337),
+		Contract:  common.HexToAddress("0x6a2afde0a78d818651faef53c65e73f77d596e2c"),
+		Recipient: common.HexToAddress("0x1b4e6a1ea2bcd7b2a1b7e3d3d5f4c5d9b2a8e3f1"),
+		Token:     common.HexToAddress("0x0000000000000000000000000000000000000000"),
+		Amount:    big.NewInt(1000000000000000000),
+		TxHash:    common.HexToHash("0x8f5f4d9a07d8bcd4b2e1d1c2a4e8f3b2c1d0e9f8a7b6c5d4e3f2a1b0c9d8e7f6"),
+	}
+}
+
+func TestRemoteSignerSignMessage(t *testing.T) {
+	key := newTestKey(t)
+	s, cleanup := newTestRemoteSigner(t, &AccountService{key: key}, common.Address{})
+	defer cleanup()
+
+	if s.Address() != crypto.PubkeyToAddress(key.PublicKey) {
+		t.Fatalf("got address %s, want the first remote account", s.Address().Hex())
+	}
+
+	message := []byte("withdraw")
+	signature, err := s.SignMessage(message)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected, err := utils.SignMessageWithPrivateKey(message, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(signature.Raw, expected.Raw) || !bytes.Equal(signature.Hash, expected.Hash) {
+		t.Errorf("got signature %x, want %x", signature.Raw, expected.Raw)
+	}
+}
+
+func TestRemoteSignerSignTypedWithdrawal(t *testing.T) {
+	key := newTestKey(t)
+	s, cleanup := newTestRemoteSigner(t, &AccountService{key: key}, common.Address{})
+	defer cleanup()
+
+	message := testWithdrawalMessage()
+	signature, err := s.SignTypedWithdrawal(message)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected, err := utils.SignTypedWithdrawalWithPrivateKey(message, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(signature.Raw, expected.Raw) || !bytes.Equal(signature.Hash, expected.Hash) {
+		t.Errorf("got signature %x, want %x", signature.Raw, expected.Raw)
+	}
+}
+
+func TestRemoteSignerRejectsOtherAccount(t *testing.T) {
+	otherKey := newTestKey(t)
+	address := crypto.PubkeyToAddress(newTestKey(t).PublicKey)
+
+	s, cleanup := newTestRemoteSigner(t, &AccountService{key: otherKey}, address)
+	defer cleanup()
+
+	if _, err := s.SignMessage([]byte("withdraw")); err != errInvalidRemoteSignature {
+		t.Errorf("signData: got error %v, want %v", err, errInvalidRemoteSignature)
+	}
+
+	if _, err := s.SignTypedWithdrawal(testWithdrawalMessage()); err != errInvalidRemoteSignature {
+		t.Errorf("signTypedData: got error %v, want %v", err, errInvalidRemoteSignature)
+	}
+
+	to := common.HexToAddress("0x6a2afde0a78d818651faef53c65e73f77d596e2c")
+	tx := types.NewTransaction(3, to, big.NewInt(0), 21000, big.NewInt(1), nil)
+	if _, err := s.SignTx(tx, big.NewInt(1337)); err != errInvalidRemoteSignature {
+		t.Errorf("signTransaction: got error %v, want %v", err, errInvalidRemoteSignature)
+	}
+}
+
+func TestRemoteSignerSignTx(t *testing.T) {
+	to := common.HexToAddress("0x6a2afde0a78d818651faef53c65e73f77d596e2c")
+	other := common.HexToAddress("0x1b4e6a1ea2bcd7b2a1b7e3d3d5f4c5d9b2a8e3f1")
+
+	cases := []struct {
+		name   string
+		tamper func(args *SignTxArgs)
+	}{
+		{"unchanged", nil},
+		{"to", func(args *SignTxArgs) { args.To = &other }},
+		{"value", func(args *SignTxArgs) { args.Value = hexutil.Big(*big.NewInt(1)) }},
+		{"nonce", func(args *SignTxArgs) { args.Nonce++ }},
+	}
+
+	for _, c := range cases {
+		key := newTestKey(t)
+		s, cleanup := newTestRemoteSigner(t, &AccountService{key: key, tamper: c.tamper}, common.Address{})
+
+		tx := types.NewTransaction(3, to, big.NewInt(0), 21000, big.NewInt(1), []byte{1, 2, 3})
+		signedTx, err := s.SignTx(tx, big.NewInt(1337))
+		cleanup()
+
+		if c.tamper != nil {
+			if err != errInvalidRemoteSignature {
+				t.Errorf("%s changed: got error %v, want %v", c.name, err, errInvalidRemoteSignature)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("%s: %s", c.name, err)
+		}
+		if !sameTransaction(tx, signedTx) {
+			t.Errorf("%s: signed transaction differs", c.name)
+		}
+		sender, err := types.Sender(types.NewEIP155Signer(big.NewInt(1337)), signedTx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sender != s.Address() {
+			t.Errorf("got sender %s, want %s", sender.Hex(), s.Address().Hex())
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package signer
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"hameid.net/cdex/dex/internal/utils"
+)
+
+// ErrUnauthorizedAccount thrown if asked to sign for some other account
+var ErrUnauthorizedAccount = errors.New("Not authorized to sign for this account")
+
+// Signer signs withdrawal messages and transactions for a single account
+type Signer interface {
+	// Address returns the account of the signer
+	Address() common.Address
+
+	// SignMessage signs keccak256 hash of message, as expected by the
+	// bridge contracts
+	SignMessage(message []byte) (utils.Signature, error)
+
+	// SignTypedWithdrawal signs EIP-712 typed data of a withdrawal message
+	SignTypedWithdrawal(message *utils.WithdrawalMessage) (utils.Signature, error)
+
+	// SignTx signs a transaction. A nil chainID produces a pre-EIP-155
+	// signature.
+	SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+}
+
+// NewTransactor creates transaction options that sign with the given signer
+// for chainID. Signing type passed by bindings is ignored as it is always
+// pre-EIP-155.
+func NewTransactor(s Signer, chainID *big.Int) *bind.TransactOpts {
+	return &bind.TransactOpts{
+		From: s.Address(),
+		Signer: func(_ types.Signer, address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			if address != s.Address() {
+				return nil, ErrUnauthorizedAccount
+			}
+			return s.SignTx(tx, chainID)
+		},
+	}
+}
+
+// NewSigner returns a remote signer if signerURL is set, else it decrypts
+// the keystore
+func NewSigner(keystoreFilePath, passwordFilePath, signerURL, signerAddress string) (Signer, error) {
+	if signerURL != "" {
+		return NewRemoteSigner(signerURL, common.HexToAddress(signerAddress))
+	}
+
+	return NewKeystoreSigner(keystoreFilePath, passwordFilePath)
+}
+
+func txSigner(chainID *big.Int) types.Signer {
+	if chainID == nil {
+		return types.HomesteadSigner{}
+	}
+	return types.NewEIP155Signer(chainID)
+}
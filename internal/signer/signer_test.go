@@ -0,0 +1,62 @@
+package signer
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestMockSignerMessage(t *testing.T) {
+	s := NewMockSigner()
+
+	signature, err := s.SignMessage([]byte("withdraw"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pubKey, err := crypto.SigToPub(signature.Hash, signature.Raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if crypto.PubkeyToAddress(*pubKey) != s.Address() {
+		t.Error("signature does not recover to signer address")
+	}
+
+	if len(s.Messages) != 1 {
+		t.Errorf("expected 1 signed message, got %d", len(s.Messages))
+	}
+}
+
+func TestTransactor(t *testing.T) {
+	s := NewMockSigner()
+	chainID := big.NewInt(1337)
+	auth := NewTransactor(s, chainID)
+
+	to := common.HexToAddress("0x6a2afde0a78d818651faef53c65e73f77d596e2c")
+	tx := types.NewTransaction(0, to, big.NewInt(0), 21000, big.NewInt(1), nil)
+
+	signedTx, err := auth.Signer(types.HomesteadSigner{}, s.Address(), tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if signedTx.ChainId().Cmp(chainID) != 0 {
+		t.Errorf("expected chain ID %s, got %s", chainID, signedTx.ChainId())
+	}
+
+	sender, err := types.Sender(types.NewEIP155Signer(chainID), signedTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sender != s.Address() {
+		t.Errorf("expected sender %s, got %s", s.Address().Hex(), sender.Hex())
+	}
+
+	if _, err := auth.Signer(types.HomesteadSigner{}, to, tx); err != ErrUnauthorizedAccount {
+		t.Errorf("expected ErrUnauthorizedAccount, got %v", err)
+	}
+}
@@ -1,7 +1,11 @@
 package socketserver
 
 import (
+	"context"
+	"sync"
+
 	"github.com/go-redis/redis"
+	"hameid.net/cdex/dex/internal/lifecycle"
 )
 
 // Hub maintains the set of active clients and broadcasts messages to the
@@ -16,38 +20,81 @@ type Hub struct {
 	// Unregister requests from clients.
 	unregister chan *Client
 
+	pubsub              *redis.PubSub
 	redisMessageChannel <-chan *redis.Message
+
+	// Closed to disconnect all clients and stop the hub.
+	stop chan struct{}
+
+	// Closed when the hub has stopped.
+	done chan struct{}
+
+	// Write pumps of registered clients, which send the close frame.
+	writers sync.WaitGroup
 }
 
-func newHub(redisMessageChannel <-chan *redis.Message) *Hub {
+func newHub(pubsub *redis.PubSub) *Hub {
 	return &Hub{
 		register:            make(chan *Client),
 		unregister:          make(chan *Client),
 		clients:             make(map[*Client]bool),
-		redisMessageChannel: redisMessageChannel,
+		pubsub:              pubsub,
+		redisMessageChannel: pubsub.Channel(),
+		stop:                make(chan struct{}),
+		done:                make(chan struct{}),
 	}
 }
 
 func (h *Hub) run() {
+	defer close(h.done)
+
 	for {
 		select {
 		case client := <-h.register:
 			h.clients[client] = true
+			h.writers.Add(1)
+			clientsGauge.Inc()
 		case client := <-h.unregister:
 			if _, ok := h.clients[client]; ok {
 				delete(h.clients, client)
 				close(client.send)
+				clientsGauge.Dec()
 			}
 		case message := <-h.redisMessageChannel:
 			payload := []byte(message.Payload)
 			for client := range h.clients {
 				select {
 				case client.send <- payload:
+					messagesFannedOut.Inc()
 				default:
 					close(client.send)
 					delete(h.clients, client)
+					clientsGauge.Dec()
+					clientsDropped.Inc()
 				}
 			}
+		case <-h.stop:
+			for client := range h.clients {
+				close(client.send)
+				delete(h.clients, client)
+				clientsGauge.Dec()
+			}
+			h.pubsub.Close()
+			return
 		}
 	}
 }
+
+// close disconnects all clients with a close frame and waits until they are
+// sent or ctx is done
+func (h *Hub) close(ctx context.Context) error {
+	close(h.stop)
+
+	select {
+	case <-h.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	return lifecycle.Wait(ctx, &h.writers)
+}
@@ -1,12 +1,20 @@
 package socketserver
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
 	"strings"
+	"sync"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/go-redis/redis"
+	"hameid.net/cdex/dex/internal/auth"
+	"hameid.net/cdex/dex/internal/config"
+	"hameid.net/cdex/dex/internal/health"
+	"hameid.net/cdex/dex/internal/lifecycle"
+	"hameid.net/cdex/dex/internal/metrics"
 	"hameid.net/cdex/dex/internal/store"
 
 	"github.com/gorilla/mux"
@@ -16,23 +24,34 @@ import (
 
 var channelSize = 10000
 
+// Channel the relayer publishes tickers of all markets on
+const marketsChannelKey = "markets"
+
+// Prefix of channels the relayer publishes updates of a wallet on
+const walletChannelPrefix = "wallet/"
+
 // SocketServer struct
 type SocketServer struct {
 	server      *http.Server
 	webappHost  string
 	hubs        map[string]*Hub
+	hubsMu      sync.Mutex
 	redisClient *redis.Client
+	sessions    *auth.Sessions
+	health      *health.Health
+	settingsMu  sync.RWMutex
 }
 
-// Start starts websocket server
-func (socketServer *SocketServer) Start() {
+// Run serves websockets until ctx is done, then sends clients a close frame
+// and closes connections
+func (socketServer *SocketServer) Run(ctx context.Context) error {
 
 	upgrader := websocket.Upgrader{
 		ReadBufferSize:  1024,
 		WriteBufferSize: 1024,
 	}
 	upgrader.CheckOrigin = func(r *http.Request) bool {
-		return r.Header.Get("Origin") == socketServer.webappHost
+		return r.Header.Get("Origin") == socketServer.getWebappHost()
 	}
 	upgrader.EnableCompression = true
 
@@ -42,7 +61,7 @@ func (socketServer *SocketServer) Start() {
 		vars := mux.Vars(r)
 
 		header := w.Header()
-		header.Set("Content-Security-Policy", fmt.Sprintf("content-src: '%s'", socketServer.webappHost))
+		header.Set("Content-Security-Policy", fmt.Sprintf("content-src: '%s'", socketServer.getWebappHost()))
 
 		// trimmedToken := strings.TrimPrefix(vars["token"], "0x")
 		// trimmedBase := strings.TrimPrefix(vars["base"], "0x")
@@ -66,42 +85,143 @@ func (socketServer *SocketServer) Start() {
 
 		fmt.Println(channelKey)
 
-		hub, ok := socketServer.hubs[channelKey]
+		hub := socketServer.getHub(channelKey)
+
+		serveWsToConnection(hub, conn)
+	})
 
-		if !ok {
-			pubsub := socketServer.redisClient.Subscribe(channelKey)
-			// pubsub.Receive
-			hub = newHub(pubsub.Channel())
-			socketServer.hubs[channelKey] = hub
-			go hub.run()
+	// Tickers of all markets
+	router.HandleFunc("/ws/markets", func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			log.Println("CONN", err)
+			return
 		}
 
-		serveWsToConnection(hub, conn)
+		serveWsToConnection(socketServer.getHub(marketsChannelKey), conn)
 	})
 
-	socketServer.server.Handler = router
+	// Balances, orders and withdrawals of a wallet. Browsers cannot set
+	// headers on websockets, so the session token is passed in the query.
+	router.HandleFunc("/ws/wallets/{address:0x[0-9A-Za-z]{40}}", func(w http.ResponseWriter, r *http.Request) {
+		address, err := socketServer.sessions.Verify(r.URL.Query().Get("token"))
+		if err == auth.ErrInvalidSession {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if err != nil {
+			log.Println("AUTH", err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+		if address != common.HexToAddress(mux.Vars(r)["address"]) {
+			http.Error(w, "Session is not of this wallet", http.StatusForbidden)
+			return
+		}
 
-	go func() {
-		if err := socketServer.server.ListenAndServe(); err != nil {
-			log.Panic(err)
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			log.Println("CONN", err)
+			return
 		}
+
+		serveWsToConnection(socketServer.getHub(walletChannelPrefix+strings.ToLower(address.Hex())), conn)
+	})
+
+	socketServer.server.Handler = socketServer.health.Wrap(metrics.Wrap(router))
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- socketServer.server.ListenAndServe()
 	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+
+	return socketServer.shutdown()
 }
 
-// Shutdown terminates the process and cleans up
-func (socketServer *SocketServer) Shutdown() {
-	socketServer.redisClient.ShutdownNoSave()
-	socketServer.server.Shutdown(nil)
+// getHub returns hub of the redis channel, subscribing on first use
+func (socketServer *SocketServer) getHub(channelKey string) *Hub {
+	socketServer.hubsMu.Lock()
+	defer socketServer.hubsMu.Unlock()
+
+	hub, ok := socketServer.hubs[channelKey]
+
+	if !ok {
+		pubsub := socketServer.redisClient.Subscribe(channelKey)
+		// pubsub.Receive
+		hub = newHub(pubsub)
+		socketServer.hubs[channelKey] = hub
+		hubsGauge.Inc()
+		go hub.run()
+	}
+
+	return hub
+}
+
+// Reload applies reloadable settings of the config
+func (socketServer *SocketServer) Reload(cfg *config.Config) {
+	socketServer.settingsMu.Lock()
+	socketServer.webappHost = cfg.SocketServer.WebappHost
+	socketServer.settingsMu.Unlock()
+
+	fmt.Printf("Accepting websocket connections from %s\n", cfg.SocketServer.WebappHost)
+}
+
+func (socketServer *SocketServer) getWebappHost() string {
+	socketServer.settingsMu.RLock()
+	defer socketServer.settingsMu.RUnlock()
+
+	return socketServer.webappHost
+}
+
+// shutdown stops accepting connections, disconnects clients of all hubs and
+// closes the connection to redis
+func (socketServer *SocketServer) shutdown() error {
+	ctx, cancel := lifecycle.ShutdownContext()
+	defer cancel()
+
+	fmt.Println("Draining websocket server...")
+	err := socketServer.server.Shutdown(ctx)
+
+	socketServer.hubsMu.Lock()
+	var wg sync.WaitGroup
+	for channelKey, hub := range socketServer.hubs {
+		wg.Add(1)
+		go func(channelKey string, hub *Hub) {
+			defer wg.Done()
+			if err := hub.close(ctx); err != nil {
+				log.Println("HUB", channelKey, err)
+			}
+		}(channelKey, hub)
+	}
+	wg.Wait()
+	socketServer.hubsMu.Unlock()
+
+	socketServer.redisClient.Close()
+
+	return err
 }
 
 // NewSocketServer creates new instance of SocketServer
-func NewSocketServer(port uint64, webappHost string, redisHostAddress string, redisPassword string) *SocketServer {
-	return &SocketServer{
+func NewSocketServer(cfg *config.Config) *SocketServer {
+	redisClient := store.NewRedisClient(cfg.Redis.Host, cfg.Redis.Password)
+
+	socketServer := &SocketServer{
 		server: &http.Server{
-			Addr: fmt.Sprintf(":%d", port),
+			Addr: fmt.Sprintf(":%d", cfg.SocketServer.Port),
 		},
-		webappHost:  webappHost,
+		webappHost:  cfg.SocketServer.WebappHost,
 		hubs:        make(map[string]*Hub),
-		redisClient: store.NewRedisClient(redisHostAddress, redisPassword),
+		redisClient: redisClient,
+		sessions:    auth.NewSessions(redisClient, cfg.Auth.SessionTTL, cfg.Auth.NonceTTL),
+		health:      health.NewHealth(config.ServiceSocketServer),
 	}
+	socketServer.health.AddCheck("redis", health.Redis(redisClient))
+
+	return socketServer
 }
@@ -0,0 +1,40 @@
+package socketserver
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"hameid.net/cdex/dex/internal/metrics"
+)
+
+var (
+	hubsGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "socketserver",
+		Name:      "hubs",
+		Help:      "Hubs of subscribed redis channels.",
+	})
+
+	clientsGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "socketserver",
+		Name:      "clients",
+		Help:      "Connected websocket clients.",
+	})
+
+	clientsDropped = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "socketserver",
+		Name:      "clients_dropped_total",
+		Help:      "Clients dropped for not keeping up with messages.",
+	})
+
+	messagesFannedOut = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "socketserver",
+		Name:      "messages_fanned_out_total",
+		Help:      "Messages queued to clients.",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(hubsGauge, clientsGauge, clientsDropped, messagesFannedOut)
+}
@@ -1,6 +1,7 @@
 package store
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
@@ -14,7 +15,8 @@ type DataStore struct {
 	connectionString string
 }
 
-// Initialize tries to connect to DB server
+// Initialize connects to DB server. Connection string is checked by
+// pinging the server, as sql.Open does not connect.
 func (store *DataStore) Initialize() {
 	// connectionString := os.Getenv("CDEX_DB_CONNECTION_STRING")
 
@@ -26,6 +28,17 @@ func (store *DataStore) Initialize() {
 	if err != nil {
 		log.Fatal(err)
 	}
+
+	if err := store.DB.Ping(); err != nil {
+		log.Fatalf("Cannot connect to database: %s", err)
+	}
+
+	fmt.Println("Database connection established")
+}
+
+// Ping checks that DB server answers
+func (store *DataStore) Ping(ctx context.Context) error {
+	return store.DB.PingContext(ctx)
 }
 
 // Close terminates connection to DB server
@@ -0,0 +1,239 @@
+package transactor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"hameid.net/cdex/dex/internal/signer"
+	"hameid.net/cdex/dex/internal/utils"
+)
+
+const defaultLimitHeadroom = 20
+
+// ErrUnknownGasStrategy thrown if gas strategy of a network is not supported
+var ErrUnknownGasStrategy = errors.New("Unknown gas strategy")
+
+// Transactor prepares EIP-155 signed transactions of an account on a network
+type Transactor struct {
+	signer    signer.Signer
+	client    *ethclient.Client
+	rpcClient *rpc.Client
+	chainID   *big.Int
+	gas       utils.GasConfig
+
+	// Next nonce of the account, fetched from the node when unknown so that
+	// concurrent senders do not get the same pending nonce
+	nonceMu    sync.Mutex
+	nonce      uint64
+	nonceKnown bool
+}
+
+// Dial connects to the network and returns RPC and eth clients
+func Dial(url string) (*rpc.Client, *ethclient.Client, error) {
+	rpcClient, err := rpc.Dial(url)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return rpcClient, ethclient.NewClient(rpcClient), nil
+}
+
+// ChainID returns chain ID of the network, as configured or as reported by the node
+func ChainID(ctx context.Context, rpcClient *rpc.Client, network *utils.NetworkInfo) (*big.Int, error) {
+	if network.ChainID != 0 {
+		return new(big.Int).SetUint64(network.ChainID), nil
+	}
+
+	var chainID hexutil.Big
+	if err := rpcClient.CallContext(ctx, &chainID, "eth_chainId"); err == nil {
+		return (*big.Int)(&chainID), nil
+	}
+
+	// Nodes without eth_chainId use network ID as chain ID
+	return ethclient.NewClient(rpcClient).NetworkID(ctx)
+}
+
+// NewTransactor creates a transactor of the signer on the network
+func NewTransactor(ctx context.Context, s signer.Signer, rpcClient *rpc.Client, network *utils.NetworkInfo) (*Transactor, error) {
+	switch network.Gas.Strategy {
+	case "", utils.GasStrategyFixed, utils.GasStrategySuggest, utils.GasStrategyEIP1559:
+	default:
+		return nil, ErrUnknownGasStrategy
+	}
+
+	chainID, err := ChainID(ctx, rpcClient, network)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Transactor{
+		signer:    s,
+		client:    ethclient.NewClient(rpcClient),
+		rpcClient: rpcClient,
+		chainID:   chainID,
+		gas:       network.Gas,
+	}, nil
+}
+
+// ChainID returns chain ID transactions are signed for
+func (t *Transactor) ChainID() *big.Int {
+	return t.chainID
+}
+
+// Opts returns options to call method of contract with the given parameters.
+// Nonce, gas price and gas limit are set.
+func (t *Transactor) Opts(ctx context.Context, contract common.Address, contractABI *abi.ABI, method string, params ...interface{}) (*bind.TransactOpts, error) {
+	from := t.signer.Address()
+
+	gasPrice, err := t.GasPrice(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	gasLimit := t.gas.Limit
+	if gasLimit == 0 {
+		data, err := contractABI.Pack(method, params...)
+		if err != nil {
+			return nil, err
+		}
+
+		gasLimit, err = t.EstimateGas(ctx, ethereum.CallMsg{
+			From:     from,
+			To:       &contract,
+			GasPrice: gasPrice,
+			Value:    big.NewInt(0),
+			Data:     data,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Cannot estimate gas of %s: %s", method, err)
+		}
+	}
+
+	// Taken last, failures above do not leave a gap
+	nonce, err := t.nextNonce(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	auth := signer.NewTransactor(t.signer, t.chainID)
+	auth.Nonce = new(big.Int).SetUint64(nonce)
+	auth.Value = big.NewInt(0)
+	auth.GasPrice = gasPrice
+	auth.GasLimit = gasLimit
+	auth.Context = ctx
+
+	return auth, nil
+}
+
+// ResyncNonce makes the next transaction take its nonce from the node. Must be
+// called when a transaction prepared with Opts could not be sent.
+func (t *Transactor) ResyncNonce() {
+	t.nonceMu.Lock()
+	defer t.nonceMu.Unlock()
+
+	t.nonceKnown = false
+}
+
+// nextNonce returns the nonce of the next transaction and counts it as used
+func (t *Transactor) nextNonce(ctx context.Context) (uint64, error) {
+	t.nonceMu.Lock()
+	defer t.nonceMu.Unlock()
+
+	if !t.nonceKnown {
+		nonce, err := t.client.PendingNonceAt(ctx, t.signer.Address())
+		if err != nil {
+			return 0, err
+		}
+		t.nonce, t.nonceKnown = nonce, true
+	}
+
+	nonce := t.nonce
+	t.nonce++
+
+	return nonce, nil
+}
+
+// EstimateGas estimates gas used by the call and adds the configured headroom
+func (t *Transactor) EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error) {
+	estimate, err := t.client.EstimateGas(ctx, msg)
+	if err != nil {
+		return 0, err
+	}
+
+	headroom := t.gas.LimitHeadroom
+	if headroom == 0 {
+		headroom = defaultLimitHeadroom
+	}
+
+	return estimate + estimate*headroom/100, nil
+}
+
+// GasPrice returns gas price according to the strategy of the network
+func (t *Transactor) GasPrice(ctx context.Context) (*big.Int, error) {
+	switch t.gas.Strategy {
+	case "", utils.GasStrategyFixed:
+		if t.gas.Price == nil {
+			return big.NewInt(0), nil
+		}
+		return new(big.Int).Set(&t.gas.Price.Int), nil
+
+	case utils.GasStrategySuggest:
+		price, err := t.client.SuggestGasPrice(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if t.gas.MaxPrice != nil && price.Cmp(&t.gas.MaxPrice.Int) > 0 {
+			price = new(big.Int).Set(&t.gas.MaxPrice.Int)
+		}
+		return price, nil
+
+	case utils.GasStrategyEIP1559:
+		return t.baseFeeGasPrice(ctx)
+	}
+
+	return nil, ErrUnknownGasStrategy
+}
+
+// baseFeeGasPrice returns base fee of the latest block plus priority fee,
+// capped at max fee. It is the gas price of a legacy transaction, not a fee
+// cap: EIP-1559 chains charge all of it and nothing is refunded when the base
+// fee drops, and the transaction stays pending while the base fee is above it.
+func (t *Transactor) baseFeeGasPrice(ctx context.Context) (*big.Int, error) {
+	var head struct {
+		BaseFee *hexutil.Big `json:"baseFeePerGas"`
+	}
+	if err := t.rpcClient.CallContext(ctx, &head, "eth_getBlockByNumber", "latest", false); err != nil {
+		return nil, err
+	}
+
+	var price *big.Int
+	if head.BaseFee == nil {
+		// Chain has no base fee yet
+		suggested, err := t.client.SuggestGasPrice(ctx)
+		if err != nil {
+			return nil, err
+		}
+		price = suggested
+	} else {
+		price = new(big.Int).Set((*big.Int)(head.BaseFee))
+		if t.gas.MaxPriorityFeePerGas != nil {
+			price.Add(price, &t.gas.MaxPriorityFeePerGas.Int)
+		}
+	}
+
+	if t.gas.MaxFeePerGas != nil && price.Cmp(&t.gas.MaxFeePerGas.Int) > 0 {
+		price = new(big.Int).Set(&t.gas.MaxFeePerGas.Int)
+	}
+
+	return price, nil
+}
@@ -0,0 +1,265 @@
+package transactor
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"hameid.net/cdex/dex/internal/signer"
+	"hameid.net/cdex/dex/internal/utils"
+	"hameid.net/cdex/dex/internal/wrappers"
+)
+
+const testABI = `[{"type":"function","name":"ping","inputs":[],"outputs":[]}]`
+
+// EthService fakes eth_ methods of a node
+type EthService struct {
+	mu         sync.Mutex
+	gasPrice   *big.Int
+	baseFee    *big.Int
+	estimate   uint64
+	nonce      uint64
+	nonceCalls int
+	estimates  int
+}
+
+func (s *EthService) GasPrice() *hexutil.Big {
+	return (*hexutil.Big)(s.gasPrice)
+}
+
+func (s *EthService) GetBlockByNumber(number string, full bool) map[string]interface{} {
+	block := map[string]interface{}{"number": "0x1"}
+	if s.baseFee != nil {
+		block["baseFeePerGas"] = (*hexutil.Big)(s.baseFee)
+	}
+	return block
+}
+
+func (s *EthService) EstimateGas(args map[string]interface{}) hexutil.Uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.estimates++
+	return hexutil.Uint64(s.estimate)
+}
+
+func (s *EthService) GetTransactionCount(address common.Address, block string) hexutil.Uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nonceCalls++
+	return hexutil.Uint64(s.nonce)
+}
+
+// ChainIDService fakes eth_chainId, which older nodes do not have
+type ChainIDService struct {
+	chainID *big.Int
+}
+
+func (s *ChainIDService) ChainId() *hexutil.Big {
+	return (*hexutil.Big)(s.chainID)
+}
+
+// NetService fakes net_version
+type NetService struct{}
+
+func (s *NetService) Version() string {
+	return "99"
+}
+
+// newTestNode serves eth on an in-process RPC server, with eth_chainId if
+// chainID is set
+func newTestNode(t *testing.T, eth *EthService, chainID *big.Int) *rpc.Client {
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", eth); err != nil {
+		t.Fatal(err)
+	}
+	if err := server.RegisterName("net", &NetService{}); err != nil {
+		t.Fatal(err)
+	}
+	if chainID != nil {
+		if err := server.RegisterName("eth", &ChainIDService{chainID}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return rpc.DialInProc(server)
+}
+
+func newTestTransactor(t *testing.T, eth *EthService, gas utils.GasConfig) *Transactor {
+	network := &utils.NetworkInfo{ChainID: 1337, Gas: gas}
+
+	transactor, err := NewTransactor(context.Background(), signer.NewMockSigner(), newTestNode(t, eth, nil), network)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return transactor
+}
+
+func TestChainID(t *testing.T) {
+	cases := []struct {
+		configured uint64
+		reported   *big.Int
+		chainID    int64
+	}{
+		{5, big.NewInt(1337), 5},
+		{0, big.NewInt(1337), 1337},
+		// Network ID without eth_chainId
+		{0, nil, 99},
+	}
+
+	for _, c := range cases {
+		rpcClient := newTestNode(t, &EthService{}, c.reported)
+
+		chainID, err := ChainID(context.Background(), rpcClient, &utils.NetworkInfo{ChainID: c.configured})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if chainID.Int64() != c.chainID {
+			t.Errorf("got chain ID %s, want %d", chainID, c.chainID)
+		}
+	}
+}
+
+func TestGasPrice(t *testing.T) {
+	wei := func(n int64) *wrappers.BigInt {
+		return wrappers.WrapBigInt(big.NewInt(n))
+	}
+
+	cases := []struct {
+		name    string
+		gas     utils.GasConfig
+		baseFee *big.Int
+		price   int64
+	}{
+		{"fixed", utils.GasConfig{Strategy: utils.GasStrategyFixed, Price: wei(7)}, nil, 7},
+		{"fixed by default", utils.GasConfig{Price: wei(7)}, nil, 7},
+		{"fixed without price", utils.GasConfig{}, nil, 0},
+		{"suggest", utils.GasConfig{Strategy: utils.GasStrategySuggest}, nil, 50},
+		{"suggest under max", utils.GasConfig{Strategy: utils.GasStrategySuggest, MaxPrice: wei(60)}, nil, 50},
+		{"suggest over max", utils.GasConfig{Strategy: utils.GasStrategySuggest, MaxPrice: wei(40)}, nil, 40},
+		{"base fee plus tip", utils.GasConfig{Strategy: utils.GasStrategyEIP1559, MaxFeePerGas: wei(200), MaxPriorityFeePerGas: wei(2)}, big.NewInt(100), 102},
+		{"base fee over max fee", utils.GasConfig{Strategy: utils.GasStrategyEIP1559, MaxFeePerGas: wei(101), MaxPriorityFeePerGas: wei(2)}, big.NewInt(100), 101},
+		{"no base fee", utils.GasConfig{Strategy: utils.GasStrategyEIP1559, MaxFeePerGas: wei(200)}, nil, 50},
+		{"no base fee over max fee", utils.GasConfig{Strategy: utils.GasStrategyEIP1559, MaxFeePerGas: wei(30)}, nil, 30},
+	}
+
+	for _, c := range cases {
+		transactor := newTestTransactor(t, &EthService{gasPrice: big.NewInt(50), baseFee: c.baseFee}, c.gas)
+
+		price, err := transactor.GasPrice(context.Background())
+		if err != nil {
+			t.Fatalf("%s: %s", c.name, err)
+		}
+		if price.Int64() != c.price {
+			t.Errorf("%s: got gas price %s, want %d", c.name, price, c.price)
+		}
+	}
+}
+
+func TestNewTransactorRejectsUnknownGasStrategy(t *testing.T) {
+	network := &utils.NetworkInfo{ChainID: 1337, Gas: utils.GasConfig{Strategy: "cheapest"}}
+
+	if _, err := NewTransactor(context.Background(), signer.NewMockSigner(), newTestNode(t, &EthService{}, nil), network); err != ErrUnknownGasStrategy {
+		t.Errorf("got error %v, want %v", err, ErrUnknownGasStrategy)
+	}
+}
+
+func TestOptsGasLimit(t *testing.T) {
+	contractABI, err := abi.JSON(strings.NewReader(testABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	contract := common.HexToAddress("0x6a2afde0a78d818651faef53c65e73f77d596e2c")
+
+	cases := []struct {
+		name  string
+		gas   utils.GasConfig
+		limit uint64
+	}{
+		{"default headroom", utils.GasConfig{}, 120000},
+		{"configured headroom", utils.GasConfig{LimitHeadroom: 50}, 150000},
+		{"configured limit", utils.GasConfig{Limit: 30000}, 30000},
+	}
+
+	for _, c := range cases {
+		eth := &EthService{estimate: 100000}
+		transactor := newTestTransactor(t, eth, c.gas)
+
+		auth, err := transactor.Opts(context.Background(), contract, &contractABI, "ping")
+		if err != nil {
+			t.Fatalf("%s: %s", c.name, err)
+		}
+		if auth.GasLimit != c.limit {
+			t.Errorf("%s: got gas limit %d, want %d", c.name, auth.GasLimit, c.limit)
+		}
+		if c.gas.Limit != 0 && eth.estimates != 0 {
+			t.Errorf("%s: gas estimated with a configured limit", c.name)
+		}
+	}
+}
+
+func TestOptsNonces(t *testing.T) {
+	contractABI, err := abi.JSON(strings.NewReader(testABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	contract := common.HexToAddress("0x6a2afde0a78d818651faef53c65e73f77d596e2c")
+
+	eth := &EthService{nonce: 5}
+	transactor := newTestTransactor(t, eth, utils.GasConfig{Limit: 30000})
+
+	const senders = 10
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		nonces = make(map[uint64]bool)
+	)
+	for i := 0; i < senders; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			auth, err := transactor.Opts(context.Background(), contract, &contractABI, "ping")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+
+			mu.Lock()
+			nonces[auth.Nonce.Uint64()] = true
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	for nonce := uint64(5); nonce < 5+senders; nonce++ {
+		if !nonces[nonce] {
+			t.Errorf("nonce %d not used, got %v", nonce, nonces)
+		}
+	}
+	if eth.nonceCalls != 1 {
+		t.Errorf("pending nonce fetched %d times, want once", eth.nonceCalls)
+	}
+
+	// A transaction was not sent, the node knows the next nonce
+	eth.mu.Lock()
+	eth.nonce = 9
+	eth.mu.Unlock()
+	transactor.ResyncNonce()
+
+	auth, err := transactor.Opts(context.Background(), contract, &contractABI, "ping")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if auth.Nonce.Uint64() != 9 {
+		t.Errorf("got nonce %d after resync, want 9", auth.Nonce.Uint64())
+	}
+}
@@ -3,18 +3,71 @@ package utils
 import (
 	"encoding/json"
 	"os"
+	"strings"
 
+	"github.com/ethereum/go-ethereum/common"
 	"hameid.net/cdex/dex/internal/wrappers"
 )
 
+// Gas price strategies
+const (
+	GasStrategyFixed   = "fixed"
+	GasStrategySuggest = "suggest"
+	GasStrategyEIP1559 = "eip1559"
+)
+
+// GasConfig tells how gas price and gas limit of transactions are chosen
+type GasConfig struct {
+	// Strategy is one of fixed, suggest or eip1559. Defaults to fixed.
+	Strategy string `json:"strategy"`
+
+	// Price is the gas price of the fixed strategy
+	Price *wrappers.BigInt `json:"price"`
+
+	// MaxPrice caps the price returned by eth_gasPrice
+	MaxPrice *wrappers.BigInt `json:"maxPrice"`
+
+	// Fees of the eip1559 strategy. It prices legacy transactions at base fee
+	// of the latest block plus MaxPriorityFeePerGas, capped at MaxFeePerGas.
+	// The whole price is paid, transactions are not sent as EIP-1559 fee cap
+	// transactions.
+	MaxFeePerGas         *wrappers.BigInt `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas *wrappers.BigInt `json:"maxPriorityFeePerGas"`
+
+	// Limit is used as gas limit if set, else the limit is estimated
+	Limit uint64 `json:"limit"`
+
+	// LimitHeadroom is added to estimated gas limits, in percent. Defaults to 20.
+	LimitHeadroom uint64 `json:"limitHeadroom"`
+}
+
+// NetworkInfo holds connection details of a chain
+type NetworkInfo struct {
+	Provider           string    `json:"provider"`
+	WebSocketProvider  string    `json:"wsProvider"`
+	RequiredSignatures uint64    `json:"requiredSignatures"`
+	ChainID            uint64    `json:"chainId"`
+	Gas                GasConfig `json:"gas"`
+}
+
+// Names of the networks in metrics and checkpoints
+const (
+	NetworkBridge   = "bridge"
+	NetworkExchange = "exchange"
+)
+
+// NetworksInfo holds chains, authorities and fee settings of the exchange
 type NetworksInfo struct {
-	Bridge struct {
-		WebSocketProvider string `json:"wsProvider"`
-	} `json:"bridge"`
+	Bridge   NetworkInfo `json:"bridge"`
 	Exchange struct {
-		WebSocketProvider string `json:"wsProvider"`
+		NetworkInfo
+		MakeFee   *wrappers.BigInt `json:"makeFee"`
+		TakeFee   *wrappers.BigInt `json:"takeFee"`
+		CancelFee *wrappers.BigInt `json:"cancelFee"`
 	} `json:"exchange"`
-	// Authorities []string `json:"authorities"`
+	Authorities  []common.Address `json:"authorities"`
+	FeeAccount   common.Address   `json:"feeAccount"`
+	OrderMatcher common.Address   `json:"orderMatcher"`
 }
 
 type ContractsInfo struct {
@@ -93,3 +146,59 @@ func ReadContractsInfo(filePath string) (*ContractsInfo, error) {
 
 	return &conf, nil
 }
+
+// TokenLimits caps the amount of a token that can be moved across the bridge.
+// A nil field means there is no limit of that kind.
+type TokenLimits struct {
+	MaxPerTransaction    *wrappers.BigInt `json:"maxPerTransaction"`
+	MaxDailyVolume       *wrappers.BigInt `json:"maxDailyVolume"`
+	MaxDailyPerRecipient *wrappers.BigInt `json:"maxDailyPerRecipient"`
+}
+
+// TransferLimits holds the limits of one transfer direction
+type TransferLimits struct {
+	Default *TokenLimits            `json:"default"`
+	Tokens  map[string]*TokenLimits `json:"tokens"`
+}
+
+// ForToken returns limits of the token, falling back to the default ones
+func (limits *TransferLimits) ForToken(token *common.Address) *TokenLimits {
+	if tokenLimits, ok := limits.Tokens[strings.ToLower(token.Hex())]; ok {
+		return tokenLimits
+	}
+	return limits.Default
+}
+
+// LimitsInfo holds deposit and withdrawal limits applied by validators
+type LimitsInfo struct {
+	Deposit  TransferLimits `json:"deposit"`
+	Withdraw TransferLimits `json:"withdraw"`
+}
+
+func ReadLimitsInfo(filePath string) (*LimitsInfo, error) {
+	file, err := os.Open(filePath)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	var limits LimitsInfo
+	decoder := json.NewDecoder(file)
+	err = decoder.Decode(&limits)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, transferLimits := range []*TransferLimits{&limits.Deposit, &limits.Withdraw} {
+		tokens := make(map[string]*TokenLimits, len(transferLimits.Tokens))
+		for token, tokenLimits := range transferLimits.Tokens {
+			tokens[strings.ToLower(token)] = tokenLimits
+		}
+		transferLimits.Tokens = tokens
+	}
+
+	return &limits, nil
+}
@@ -1,86 +1,143 @@
 package utils
 
 import (
-	"bytes"
 	"crypto/ecdsa"
+	"errors"
 	"fmt"
-	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 )
 
-func SerializeWithdrawalMessage(recepient *common.Address, token *common.Address, amount *big.Int, transactionHash *common.Hash) ([]byte, error) {
-	byteSlice := make([]byte, 104)
+type Signature struct {
+	Raw  []byte
+	Hash []byte
+	R    [32]byte
+	S    [32]byte
+	V    uint8
+}
 
-	paddedAmount, amountErr := leftPadByteSlice(amount.Bytes(), 32, 0)
-	if amountErr != nil {
-		return nil, amountErr
+func SignMessageWithPrivateKey(message []byte, p *ecdsa.PrivateKey) (Signature, error) {
+	hashRaw := crypto.Keccak256(message)
+	signature, err := crypto.Sign(hashRaw, p)
+	if err != nil {
+		return Signature{}, err
 	}
 
-	// paddedGasPrice, gasErr := leftPadByteSlice(gasPrice.Bytes(), 32, 0)
-	// if gasErr != nil {
-	// 	return nil, gasErr
-	// }
+	return NewSignature(hashRaw, signature), nil
+}
+
+// NewSignature splits a 65-byte [R || S || V] signature of hash into its parts
+func NewSignature(hash []byte, signature []byte) Signature {
+	return Signature{
+		signature,
+		hash,
+		ByteSliceToByte32(signature[:32]),
+		ByteSliceToByte32(signature[32:64]),
+		uint8(int(signature[64])) + 27, // Yes add 27, weird Ethereum quirk
+	}
+}
 
-	copy(byteSlice[0:20], recepient.Bytes()[:])
-	copy(byteSlice[20:40], token.Bytes()[:])
-	copy(byteSlice[40:72], paddedAmount)
-	copy(byteSlice[72:104], transactionHash.Bytes()[:])
+// SplitSignatures splits 65-byte [R || S || V] signatures into the v, r and
+// s arrays expected by HomeBridge.withdraw
+func SplitSignatures(signatures [][]byte) ([]uint8, [][32]byte, [][32]byte, error) {
+	vs := make([]uint8, len(signatures))
+	rs := make([][32]byte, len(signatures))
+	ss := make([][32]byte, len(signatures))
+
+	for i, signature := range signatures {
+		if len(signature) != 65 {
+			return nil, nil, nil, fmt.Errorf("Invalid signature length: %d", len(signature))
+		}
+
+		vs[i] = signature[64]
+		if vs[i] < 27 {
+			vs[i] += 27
+		}
+		rs[i] = ByteSliceToByte32(signature[:32])
+		ss[i] = ByteSliceToByte32(signature[32:64])
+	}
 
-	return byteSlice, nil
+	return vs, rs, ss, nil
 }
 
-func DeserializeMessage(message []byte) (recepient *common.Address, token *common.Address, amount *big.Int, transactionHash *common.Hash) {
-	r := common.BytesToAddress(message[0:20])
-	t := common.BytesToAddress(message[20:40])
-	a := big.NewInt(0)
-	a.SetBytes(message[40:72])
-	h := common.BytesToHash(message[72:104])
-	return &r, &t, a, &h
+// RecoverSigner returns the address that signed keccak256 hash of message
+func RecoverSigner(message []byte, signature []byte) (common.Address, error) {
+	return recoverHashSigner(crypto.Keccak256(message), signature)
 }
 
-func leftPadByteSlice(data []byte, blockLen int, padValue int) ([]byte, error) {
-	if blockLen == len(data) {
-		return data, nil
+func recoverHashSigner(hash []byte, signature []byte) (common.Address, error) {
+	if len(signature) != 65 {
+		return common.Address{}, fmt.Errorf("Invalid signature length: %d", len(signature))
 	}
 
-	if blockLen <= 0 {
-		return nil, fmt.Errorf("Invalid block size: %d", blockLen)
+	sig := make([]byte, 65)
+	copy(sig, signature)
+	if sig[64] >= 27 {
+		sig[64] -= 27
 	}
 
-	padLen := blockLen - len(data)
+	pubKey, err := crypto.SigToPub(hash, sig)
+	if err != nil {
+		return common.Address{}, err
+	}
 
-	pad := bytes.Repeat([]byte{byte(padLen)}, padValue)
-	slice := make([]byte, blockLen)
-	copy(slice[0:padLen], pad)
-	copy(slice[padLen:blockLen], data)
+	return crypto.PubkeyToAddress(*pubKey), nil
+}
 
-	return slice, nil
+// PersonalMessageHash returns the hash signed by personal_sign (EIP-191) of
+// message
+func PersonalMessageHash(message []byte) []byte {
+	prefix := fmt.Sprintf("\x19Ethereum Signed Message:\n%d", len(message))
+	return crypto.Keccak256([]byte(prefix), message)
 }
 
-type Signature struct {
-	Raw  []byte
-	Hash []byte
-	R    [32]byte
-	S    [32]byte
-	V    uint8
+// RecoverPersonalSigner returns the address that signed message with
+// personal_sign (EIP-191)
+func RecoverPersonalSigner(message []byte, signature []byte) (common.Address, error) {
+	return recoverHashSigner(PersonalMessageHash(message), signature)
 }
 
-func SignMessageWithPrivateKey(message []byte, p *ecdsa.PrivateKey) (Signature, error) {
-	hashRaw := crypto.Keccak256(message)
-	signature, err := crypto.Sign(hashRaw, p)
+// ErrSignerMismatch thrown if signature does not recover to the claimed signer
+var ErrSignerMismatch = errors.New("Signature does not recover to the claimed signer")
+
+// ErrNotAuthority thrown if signer is not one of the authorities
+var ErrNotAuthority = errors.New("Signer is not an authority")
+
+// VerifyMessageSignature checks that signature was made by signer with
+// SignMessageWithPrivateKey
+func VerifyMessageSignature(message []byte, signature []byte, signer common.Address) error {
+	recovered, err := RecoverSigner(message, signature)
 	if err != nil {
-		return Signature{}, err
+		return err
 	}
 
-	return Signature{
-		signature,
-		hashRaw,
-		ByteSliceToByte32(signature[:32]),
-		ByteSliceToByte32(signature[32:64]),
-		uint8(int(signature[64])) + 27, // Yes add 27, weird Ethereum quirk
-	}, nil
+	if recovered != signer {
+		return ErrSignerMismatch
+	}
+
+	return nil
+}
+
+// VerifyAuthoritySignature checks that signature was made by signer and that
+// signer is one of the authorities
+func VerifyAuthoritySignature(message []byte, signature []byte, signer common.Address, authorities []common.Address) error {
+	if err := VerifyMessageSignature(message, signature, signer); err != nil {
+		return err
+	}
+
+	return CheckAuthority(signer, authorities)
+}
+
+// CheckAuthority checks that signer is one of the authorities
+func CheckAuthority(signer common.Address, authorities []common.Address) error {
+	for _, authority := range authorities {
+		if authority == signer {
+			return nil
+		}
+	}
+
+	return ErrNotAuthority
 }
 
 func ByteSliceToByte32(s []byte) [32]byte {
@@ -0,0 +1,102 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestRecoverSigner(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	message := []byte("withdraw")
+	signature, err := SignMessageWithPrivateKey(message, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signer, err := RecoverSigner(message, signature.Raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signer != crypto.PubkeyToAddress(key.PublicKey) {
+		t.Errorf("recovered %s, expected %s", signer.Hex(), crypto.PubkeyToAddress(key.PublicKey).Hex())
+	}
+
+	vs, rs, ss, err := SplitSignatures([][]byte{signature.Raw})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vs[0] != signature.V || rs[0] != signature.R || ss[0] != signature.S {
+		t.Error("split signature does not match its parts")
+	}
+
+	if _, err := RecoverSigner(message, signature.Raw[:64]); err == nil {
+		t.Error("expected error on short signature")
+	}
+}
+
+func TestVerifyAuthoritySignature(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signer := crypto.PubkeyToAddress(key.PublicKey)
+	otherAddress := crypto.PubkeyToAddress(other.PublicKey)
+
+	message := []byte("withdraw")
+	signature, err := SignMessageWithPrivateKey(message, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := VerifyAuthoritySignature(message, signature.Raw, signer, []common.Address{otherAddress, signer}); err != nil {
+		t.Errorf("expected valid signature, got %v", err)
+	}
+	if err := VerifyAuthoritySignature(message, signature.Raw, otherAddress, []common.Address{otherAddress}); err != ErrSignerMismatch {
+		t.Errorf("expected ErrSignerMismatch, got %v", err)
+	}
+	if err := VerifyAuthoritySignature(message, signature.Raw, signer, []common.Address{otherAddress}); err != ErrNotAuthority {
+		t.Errorf("expected ErrNotAuthority, got %v", err)
+	}
+	if err := VerifyMessageSignature([]byte("other"), signature.Raw, signer); err != ErrSignerMismatch {
+		t.Errorf("expected ErrSignerMismatch for other message, got %v", err)
+	}
+}
+
+func TestRecoverPersonalSigner(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	message := []byte("Sign in with nonce 1234")
+	signature, err := crypto.Sign(PersonalMessageHash(message), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Wallets return v as 27 or 28
+	signature[64] += 27
+
+	signer, err := RecoverPersonalSigner(message, signature)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signer != crypto.PubkeyToAddress(key.PublicKey) {
+		t.Errorf("recovered %s, expected %s", signer.Hex(), crypto.PubkeyToAddress(key.PublicKey).Hex())
+	}
+
+	// Not the plain keccak256 of message
+	if signer, _ := RecoverSigner(message, signature); signer == crypto.PubkeyToAddress(key.PublicKey) {
+		t.Error("personal signature recovered as plain message signature")
+	}
+}
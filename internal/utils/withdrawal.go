@@ -0,0 +1,196 @@
+package utils
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Withdrawal message versions
+const (
+	// WithdrawalMessageLegacy is the bare recipient|token|amount|txHash
+	// layout understood by the deployed contracts
+	WithdrawalMessageLegacy uint8 = 0
+
+	// WithdrawalMessageV1 prefixes the legacy layout with a version byte,
+	// chain ID and bridge contract address
+	WithdrawalMessageV1 uint8 = 1
+)
+
+const (
+	legacyWithdrawalMessageLength = 104
+	v1WithdrawalMessageLength     = 1 + 32 + 20 + legacyWithdrawalMessageLength
+)
+
+// EIP-712 domain of withdrawal messages
+const (
+	WithdrawalDomainName    = "CDEX Bridge"
+	WithdrawalDomainVersion = "1"
+)
+
+var (
+	eip712DomainTypeHash = crypto.Keccak256([]byte("EIP712Domain(string name,string version,uint256 chainId,address verifyingContract)"))
+	withdrawalTypeHash   = crypto.Keccak256([]byte("Withdrawal(address recipient,address token,uint256 amount,bytes32 transactionHash)"))
+)
+
+// ErrInvalidWithdrawalMessage thrown if message cannot be decoded
+var ErrInvalidWithdrawalMessage = errors.New("Invalid withdrawal message")
+
+// ErrMissingWithdrawalDomain thrown if chain ID or contract is required but not set
+var ErrMissingWithdrawalDomain = errors.New("Withdrawal message has no chain ID or contract address")
+
+// WithdrawalMessage is the message authorities sign to release funds from home bridge
+type WithdrawalMessage struct {
+	Version   uint8
+	ChainID   *big.Int
+	Contract  common.Address
+	Recipient common.Address
+	Token     common.Address
+	Amount    *big.Int
+	TxHash    common.Hash
+}
+
+// Encode serializes the message in its version's layout
+func (m *WithdrawalMessage) Encode() ([]byte, error) {
+	if m.Amount == nil || m.Amount.Sign() < 0 || m.Amount.BitLen() > 256 {
+		return nil, fmt.Errorf("Invalid withdrawal amount: %v", m.Amount)
+	}
+
+	legacy := make([]byte, legacyWithdrawalMessageLength)
+	copy(legacy[0:20], m.Recipient.Bytes())
+	copy(legacy[20:40], m.Token.Bytes())
+	copy(legacy[40:72], math.PaddedBigBytes(m.Amount, 32))
+	copy(legacy[72:104], m.TxHash.Bytes())
+
+	switch m.Version {
+	case WithdrawalMessageLegacy:
+		return legacy, nil
+
+	case WithdrawalMessageV1:
+		if err := m.checkDomain(); err != nil {
+			return nil, err
+		}
+
+		message := make([]byte, v1WithdrawalMessageLength)
+		message[0] = WithdrawalMessageV1
+		copy(message[1:33], math.PaddedBigBytes(m.ChainID, 32))
+		copy(message[33:53], m.Contract.Bytes())
+		copy(message[53:], legacy)
+		return message, nil
+	}
+
+	return nil, fmt.Errorf("Unsupported withdrawal message version: %d", m.Version)
+}
+
+// TypedDataHash returns the EIP-712 digest of the message
+func (m *WithdrawalMessage) TypedDataHash() ([]byte, error) {
+	if err := m.checkDomain(); err != nil {
+		return nil, err
+	}
+	if m.Amount == nil || m.Amount.Sign() < 0 || m.Amount.BitLen() > 256 {
+		return nil, fmt.Errorf("Invalid withdrawal amount: %v", m.Amount)
+	}
+
+	domainSeparator := crypto.Keccak256(
+		eip712DomainTypeHash,
+		crypto.Keccak256([]byte(WithdrawalDomainName)),
+		crypto.Keccak256([]byte(WithdrawalDomainVersion)),
+		math.PaddedBigBytes(m.ChainID, 32),
+		common.LeftPadBytes(m.Contract.Bytes(), 32),
+	)
+
+	structHash := crypto.Keccak256(
+		withdrawalTypeHash,
+		common.LeftPadBytes(m.Recipient.Bytes(), 32),
+		common.LeftPadBytes(m.Token.Bytes(), 32),
+		math.PaddedBigBytes(m.Amount, 32),
+		m.TxHash.Bytes(),
+	)
+
+	return crypto.Keccak256([]byte{0x19, 0x01}, domainSeparator, structHash), nil
+}
+
+func (m *WithdrawalMessage) checkDomain() error {
+	if m.ChainID == nil || m.ChainID.Sign() <= 0 || m.ChainID.BitLen() > 256 || m.Contract == (common.Address{}) {
+		return ErrMissingWithdrawalDomain
+	}
+	return nil
+}
+
+// DecodeWithdrawalMessage parses a legacy or versioned withdrawal message
+func DecodeWithdrawalMessage(data []byte) (*WithdrawalMessage, error) {
+	m := &WithdrawalMessage{}
+
+	switch {
+	case len(data) == legacyWithdrawalMessageLength:
+		m.Version = WithdrawalMessageLegacy
+
+	case len(data) == v1WithdrawalMessageLength && data[0] == WithdrawalMessageV1:
+		m.Version = WithdrawalMessageV1
+		m.ChainID = new(big.Int).SetBytes(data[1:33])
+		m.Contract = common.BytesToAddress(data[33:53])
+		data = data[53:]
+
+	default:
+		return nil, ErrInvalidWithdrawalMessage
+	}
+
+	m.Recipient = common.BytesToAddress(data[0:20])
+	m.Token = common.BytesToAddress(data[20:40])
+	m.Amount = new(big.Int).SetBytes(data[40:72])
+	m.TxHash = common.BytesToHash(data[72:104])
+
+	return m, nil
+}
+
+// SignTypedWithdrawalWithPrivateKey signs EIP-712 digest of the message
+func SignTypedWithdrawalWithPrivateKey(m *WithdrawalMessage, p *ecdsa.PrivateKey) (Signature, error) {
+	hash, err := m.TypedDataHash()
+	if err != nil {
+		return Signature{}, err
+	}
+
+	signature, err := crypto.Sign(hash, p)
+	if err != nil {
+		return Signature{}, err
+	}
+
+	return NewSignature(hash, signature), nil
+}
+
+// VerifyWithdrawalSignature checks that signature was made by signer over
+// keccak256 of the message or, for messages that carry their domain, over
+// the EIP-712 digest of the message
+func VerifyWithdrawalSignature(message []byte, signature []byte, signer common.Address) error {
+	err := VerifyMessageSignature(message, signature, signer)
+	if err != ErrSignerMismatch {
+		return err
+	}
+
+	// Legacy messages do not carry the domain of typed data
+	m, decodeErr := DecodeWithdrawalMessage(message)
+	if decodeErr != nil || m.Version == WithdrawalMessageLegacy {
+		return err
+	}
+
+	hash, err := m.TypedDataHash()
+	if err != nil {
+		return err
+	}
+
+	recovered, err := recoverHashSigner(hash, signature)
+	if err != nil {
+		return err
+	}
+
+	if recovered != signer {
+		return ErrSignerMismatch
+	}
+
+	return nil
+}
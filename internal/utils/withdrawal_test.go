@@ -0,0 +1,133 @@
+package utils
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func newTestWithdrawalMessage(version uint8) *WithdrawalMessage {
+	return &WithdrawalMessage{
+		Version:   version,
+		ChainID:   big.NewInt(1337),
+		Contract:  common.HexToAddress("0xee457955F8ee9BbFDf357B20c287A470ABB8012F"),
+		Recipient: common.HexToAddress("0x991e501e6fbe1efc2e37ce938f6a39267851231a"),
+		Token:     common.HexToAddress("0xd8912c10681d8b21fd3742244f44658dba12264e"),
+		Amount:    big.NewInt(2500000000000000),
+		TxHash:    common.HexToHash("0x5c543e7ae0a1104f78406c340e9c64fd9fce5170ac709fcb44a43c35f0da4e31"),
+	}
+}
+
+func TestWithdrawalMessageRoundTrip(t *testing.T) {
+	for _, version := range []uint8{WithdrawalMessageLegacy, WithdrawalMessageV1} {
+		message := newTestWithdrawalMessage(version)
+
+		data, err := message.Encode()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		decoded, err := DecodeWithdrawalMessage(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if decoded.Version != version ||
+			decoded.Recipient != message.Recipient ||
+			decoded.Token != message.Token ||
+			decoded.Amount.Cmp(message.Amount) != 0 ||
+			decoded.TxHash != message.TxHash {
+			t.Errorf("version %d: decoded message does not match", version)
+		}
+
+		if version == WithdrawalMessageV1 && (decoded.ChainID.Cmp(message.ChainID) != 0 || decoded.Contract != message.Contract) {
+			t.Error("decoded domain does not match")
+		}
+	}
+}
+
+func TestDecodeInvalidWithdrawalMessage(t *testing.T) {
+	for _, data := range [][]byte{nil, make([]byte, 103), make([]byte, v1WithdrawalMessageLength)} {
+		if _, err := DecodeWithdrawalMessage(data); err != ErrInvalidWithdrawalMessage {
+			t.Errorf("expected ErrInvalidWithdrawalMessage for %d bytes, got %v", len(data), err)
+		}
+	}
+}
+
+func TestWithdrawalMessageDomain(t *testing.T) {
+	message := newTestWithdrawalMessage(WithdrawalMessageV1)
+	message.ChainID = nil
+
+	if _, err := message.Encode(); err != ErrMissingWithdrawalDomain {
+		t.Errorf("expected ErrMissingWithdrawalDomain, got %v", err)
+	}
+
+	other := newTestWithdrawalMessage(WithdrawalMessageV1)
+	other.ChainID = big.NewInt(1)
+
+	hash, err := newTestWithdrawalMessage(WithdrawalMessageV1).TypedDataHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherHash, err := other.TypedDataHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if common.BytesToHash(hash) == common.BytesToHash(otherHash) {
+		t.Error("typed data hash does not bind chain ID")
+	}
+}
+
+func TestSignTypedWithdrawal(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signature, err := SignTypedWithdrawalWithPrivateKey(newTestWithdrawalMessage(WithdrawalMessageV1), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pubKey, err := crypto.SigToPub(signature.Hash, signature.Raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if crypto.PubkeyToAddress(*pubKey) != crypto.PubkeyToAddress(key.PublicKey) {
+		t.Error("typed signature does not recover to signer")
+	}
+}
+
+func TestVerifyWithdrawalSignature(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := crypto.PubkeyToAddress(key.PublicKey)
+
+	message := newTestWithdrawalMessage(WithdrawalMessageV1)
+	data, err := message.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	typed, err := SignTypedWithdrawalWithPrivateKey(message, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain, err := SignMessageWithPrivateKey(data, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, signature := range map[string]Signature{"typed": typed, "keccak256": plain} {
+		if err := VerifyWithdrawalSignature(data, signature.Raw, signer); err != nil {
+			t.Errorf("%s signature: %s", name, err)
+		}
+		if err := VerifyWithdrawalSignature(data, signature.Raw, common.Address{}); err != ErrSignerMismatch {
+			t.Errorf("%s signature: expected ErrSignerMismatch, got %v", name, err)
+		}
+	}
+}
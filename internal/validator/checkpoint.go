@@ -0,0 +1,63 @@
+package validator
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sync"
+)
+
+// checkpointFile keeps the last block of each network whose events the
+// validator has processed
+type checkpointFile struct {
+	mu   sync.Mutex
+	path string
+}
+
+func newCheckpointFile(path string) *checkpointFile {
+	return &checkpointFile{path: path}
+}
+
+// load returns blocks by network, none if the file does not exist yet
+func (f *checkpointFile) load() (map[string]uint64, error) {
+	blocks := make(map[string]uint64)
+
+	data, err := ioutil.ReadFile(f.path)
+	if os.IsNotExist(err) {
+		return blocks, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &blocks); err != nil {
+		return nil, err
+	}
+
+	return blocks, nil
+}
+
+// save records the block of the network. The file is replaced at once so
+// that it is never left half written.
+func (f *checkpointFile) save(network string, block uint64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	blocks, err := f.load()
+	if err != nil {
+		return err
+	}
+	blocks[network] = block
+
+	data, err := json.MarshalIndent(blocks, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmpPath := f.path + ".tmp"
+	if err := ioutil.WriteFile(tmpPath, data, 0600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, f.path)
+}
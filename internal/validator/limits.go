@@ -0,0 +1,278 @@
+package validator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"hameid.net/cdex/dex/internal/utils"
+	"hameid.net/cdex/dex/internal/wrappers"
+)
+
+const (
+	transferKindDeposit  = "deposit"
+	transferKindWithdraw = "withdraw"
+)
+
+// Rolling window of daily limits
+var limitWindow = 24 * time.Hour
+
+var errTransferNotParked = errors.New("No parked transfer found for the given transaction hash")
+
+// ParkedTransfer is a deposit or withdrawal held back for manual approval
+type ParkedTransfer struct {
+	Kind      string            `json:"kind"`
+	Recipient *wrappers.Address `json:"recipient"`
+	Token     *wrappers.Address `json:"token"`
+	Amount    *wrappers.BigInt  `json:"amount"`
+	TxHash    *wrappers.Hash    `json:"txHash"`
+	Timestamp int64             `json:"timestamp"`
+	Reason    string            `json:"reason,omitempty"`
+}
+
+type limiterState struct {
+	History []ParkedTransfer `json:"history"`
+	Parked  []ParkedTransfer `json:"parked"`
+}
+
+// recorded returns true if the transfer is already counted against the limits
+func (state *limiterState) recorded(transfer *ParkedTransfer) bool {
+	for _, t := range state.History {
+		if t.Kind == transfer.Kind && t.TxHash.Hash == transfer.TxHash.Hash {
+			return true
+		}
+	}
+	return false
+}
+
+// transferLimiter keeps track of transfers signed in the last 24 hours and
+// parks the ones that would go over the configured limits. State is kept in
+// a file so that it survives restarts and can be shared with `approve`.
+type transferLimiter struct {
+	mu            sync.Mutex
+	limits        *utils.LimitsInfo
+	stateFilePath string
+}
+
+func newTransferLimiter(limits *utils.LimitsInfo, stateFilePath string) *transferLimiter {
+	return &transferLimiter{
+		limits:        limits,
+		stateFilePath: stateFilePath,
+	}
+}
+
+// setLimits replaces the limits applied to transfers from now on
+func (l *transferLimiter) setLimits(limits *utils.LimitsInfo) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.limits = limits
+}
+
+// admit returns true if transfer is within limits. It only counts against
+// the limits once recorded after it was submitted. Transfers over the limits
+// are parked and false is returned.
+func (l *transferLimiter) admit(transfer *ParkedTransfer) (bool, error) {
+	admitted := false
+
+	err := l.update(func(state *limiterState) {
+		if state.recorded(transfer) {
+			// Already signed once, the contract rejects duplicates anyway
+			admitted = true
+			return
+		}
+
+		for _, t := range state.Parked {
+			if t.Kind == transfer.Kind && t.TxHash.Hash == transfer.TxHash.Hash {
+				return
+			}
+		}
+
+		if reason := l.exceededLimit(state, transfer); reason != "" {
+			transfer.Reason = reason
+			state.Parked = append(state.Parked, *transfer)
+			return
+		}
+
+		admitted = true
+	})
+
+	return admitted, err
+}
+
+// record counts a submitted transfer against the limits. Replayed transfers
+// are counted once, and not at all once they are older than the window.
+func (l *transferLimiter) record(transfer *ParkedTransfer) error {
+	return l.update(func(state *limiterState) {
+		if transfer.Timestamp <= time.Now().Add(-limitWindow).Unix() {
+			return
+		}
+
+		if state.recorded(transfer) {
+			return
+		}
+
+		state.History = append(state.History, *transfer)
+	})
+}
+
+// release submits a parked transfer, then removes it from parked transfers
+// and counts it against the limits. The transfer stays parked if submit
+// fails.
+func (l *transferLimiter) release(txHash common.Hash, submit func(transfer *ParkedTransfer) error) (*ParkedTransfer, error) {
+	var released *ParkedTransfer
+
+	err := l.update(func(state *limiterState) {
+		for _, t := range state.Parked {
+			if t.TxHash.Hash == txHash {
+				parkedTransfer := t
+				released = &parkedTransfer
+				break
+			}
+		}
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if released == nil {
+		return nil, errTransferNotParked
+	}
+
+	if err := submit(released); err != nil {
+		return nil, err
+	}
+
+	err = l.update(func(state *limiterState) {
+		for i, t := range state.Parked {
+			if t.TxHash.Hash == txHash {
+				state.Parked = append(state.Parked[:i], state.Parked[i+1:]...)
+				break
+			}
+		}
+
+		// The transfer may have been replayed and recorded meanwhile
+		if state.recorded(released) {
+			return
+		}
+
+		approved := *released
+		approved.Timestamp = time.Now().Unix()
+		approved.Reason = ""
+		state.History = append(state.History, approved)
+	})
+
+	return released, err
+}
+
+// parked returns transfers waiting for approval
+func (l *transferLimiter) parked() ([]ParkedTransfer, error) {
+	var parked []ParkedTransfer
+
+	err := l.update(func(state *limiterState) {
+		parked = state.Parked
+	})
+
+	return parked, err
+}
+
+func (l *transferLimiter) exceededLimit(state *limiterState, transfer *ParkedTransfer) string {
+	transferLimits := &l.limits.Withdraw
+	if transfer.Kind == transferKindDeposit {
+		transferLimits = &l.limits.Deposit
+	}
+
+	limits := transferLimits.ForToken(&transfer.Token.Address)
+	if limits == nil {
+		return ""
+	}
+
+	if limits.MaxPerTransaction != nil && transfer.Amount.Cmp(limits.MaxPerTransaction) > 0 {
+		return fmt.Sprintf("amount %s is over the per-transaction limit of %s", transfer.Amount.String(), limits.MaxPerTransaction.String())
+	}
+
+	dailyVolume := big.NewInt(0).Set(&transfer.Amount.Int)
+	recipientVolume := big.NewInt(0).Set(&transfer.Amount.Int)
+
+	for _, t := range state.History {
+		if t.Kind != transfer.Kind || t.Token.Address != transfer.Token.Address {
+			continue
+		}
+		dailyVolume.Add(dailyVolume, &t.Amount.Int)
+		if t.Recipient.Address == transfer.Recipient.Address {
+			recipientVolume.Add(recipientVolume, &t.Amount.Int)
+		}
+	}
+
+	if limits.MaxDailyVolume != nil && dailyVolume.Cmp(&limits.MaxDailyVolume.Int) > 0 {
+		return fmt.Sprintf("24h volume %s is over the limit of %s", dailyVolume.String(), limits.MaxDailyVolume.String())
+	}
+
+	if limits.MaxDailyPerRecipient != nil && recipientVolume.Cmp(&limits.MaxDailyPerRecipient.Int) > 0 {
+		return fmt.Sprintf("24h volume %s of recipient is over the limit of %s", recipientVolume.String(), limits.MaxDailyPerRecipient.String())
+	}
+
+	return ""
+}
+
+// update loads the state file under an exclusive lock, prunes expired
+// history, applies fn and writes the state back
+func (l *transferLimiter) update(fn func(state *limiterState)) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	file, err := os.OpenFile(l.stateFilePath, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX); err != nil {
+		return err
+	}
+	defer syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+
+	data, err := ioutil.ReadAll(file)
+	if err != nil {
+		return err
+	}
+
+	state := limiterState{}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &state); err != nil {
+			return err
+		}
+	}
+
+	cutoff := time.Now().Add(-limitWindow).Unix()
+	history := []ParkedTransfer{}
+	for _, t := range state.History {
+		if t.Timestamp > cutoff {
+			history = append(history, t)
+		}
+	}
+	state.History = history
+
+	fn(&state)
+
+	data, err = json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := file.Truncate(0); err != nil {
+		return err
+	}
+
+	_, err = file.WriteAt(data, 0)
+
+	return err
+}
@@ -0,0 +1,237 @@
+package validator
+
+import (
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"hameid.net/cdex/dex/internal/config"
+	"hameid.net/cdex/dex/internal/utils"
+	"hameid.net/cdex/dex/internal/wrappers"
+)
+
+func newTestLimiter(t *testing.T) (*transferLimiter, func()) {
+	dir, err := ioutil.TempDir("", "limits")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	limits := &utils.LimitsInfo{}
+	limits.Withdraw.Default = &utils.TokenLimits{
+		MaxPerTransaction:    wrappers.WrapBigInt(big.NewInt(100)),
+		MaxDailyVolume:       wrappers.WrapBigInt(big.NewInt(250)),
+		MaxDailyPerRecipient: wrappers.WrapBigInt(big.NewInt(150)),
+	}
+
+	return newTransferLimiter(limits, filepath.Join(dir, "state.json")), func() { os.RemoveAll(dir) }
+}
+
+func newTestTransfer(recipient byte, amount int64, txHash byte) *ParkedTransfer {
+	r := common.BytesToAddress([]byte{recipient})
+	token := common.Address{}
+	h := common.BytesToHash([]byte{txHash})
+
+	return &ParkedTransfer{
+		Kind:      transferKindWithdraw,
+		Recipient: wrappers.WrapAddress(&r),
+		Token:     wrappers.WrapAddress(&token),
+		Amount:    wrappers.WrapBigInt(big.NewInt(amount)),
+		TxHash:    wrappers.WrapHash(&h),
+		Timestamp: time.Now().Unix(),
+	}
+}
+
+func TestTransferLimiter(t *testing.T) {
+	limiter, cleanup := newTestLimiter(t)
+	defer cleanup()
+
+	cases := []struct {
+		transfer *ParkedTransfer
+		admitted bool
+	}{
+		{newTestTransfer(1, 101, 1), false}, // over per-transaction limit
+		{newTestTransfer(1, 100, 2), true},
+		{newTestTransfer(1, 60, 3), false}, // over per-recipient limit
+		{newTestTransfer(2, 100, 4), true},
+		{newTestTransfer(3, 60, 5), false}, // over daily volume
+		{newTestTransfer(1, 100, 2), true}, // already admitted
+	}
+
+	for i, c := range cases {
+		admitted, err := limiter.admit(c.transfer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if admitted != c.admitted {
+			t.Errorf("case %d: expected admitted=%t, got %t", i, c.admitted, admitted)
+		}
+		if admitted {
+			if err := limiter.record(c.transfer); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	parked, err := limiter.parked()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parked) != 3 {
+		t.Fatalf("expected 3 parked transfers, got %d", len(parked))
+	}
+
+	submit := func(transfer *ParkedTransfer) error { return nil }
+
+	released, err := limiter.release(parked[0].TxHash.Hash, submit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if released.Amount.Int64() != 101 {
+		t.Errorf("released wrong transfer: %s", released.Amount.String())
+	}
+
+	if _, err := limiter.release(parked[0].TxHash.Hash, submit); err != errTransferNotParked {
+		t.Errorf("expected errTransferNotParked, got %v", err)
+	}
+}
+
+func TestTransferLimiterCountsSubmittedTransfersOnly(t *testing.T) {
+	limiter, cleanup := newTestLimiter(t)
+	defer cleanup()
+
+	// Submission of the first transfer failed and it was never recorded
+	for _, transfer := range []*ParkedTransfer{newTestTransfer(1, 100, 1), newTestTransfer(1, 100, 2)} {
+		admitted, err := limiter.admit(transfer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !admitted {
+			t.Fatalf("transfer %s not admitted: %s", transfer.TxHash.Hash.Hex(), transfer.Reason)
+		}
+	}
+
+	if err := limiter.record(newTestTransfer(1, 100, 2)); err != nil {
+		t.Fatal(err)
+	}
+
+	admitted, err := limiter.admit(newTestTransfer(1, 100, 3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if admitted {
+		t.Error("recorded transfer not counted against the per-recipient limit")
+	}
+}
+
+func TestTransferLimiterCountsReplayedTransfersOnce(t *testing.T) {
+	limiter, cleanup := newTestLimiter(t)
+	defer cleanup()
+
+	// Replayed after a restart, the transfer is recorded again
+	for i := 0; i < 2; i++ {
+		if err := limiter.record(newTestTransfer(1, 100, 1)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Replayed from before the window
+	expired := newTestTransfer(1, 100, 2)
+	expired.Timestamp = time.Now().Add(-limitWindow - time.Hour).Unix()
+	if err := limiter.record(expired); err != nil {
+		t.Fatal(err)
+	}
+
+	// Parked, then replayed and recorded before approval
+	parkedTransfer := newTestTransfer(2, 101, 3)
+	if admitted, err := limiter.admit(parkedTransfer); err != nil || admitted {
+		t.Fatalf("expected transfer to be parked, got admitted=%t err=%v", admitted, err)
+	}
+	if err := limiter.record(parkedTransfer); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := limiter.release(parkedTransfer.TxHash.Hash, func(transfer *ParkedTransfer) error { return nil }); err != nil {
+		t.Fatal(err)
+	}
+
+	// 100 + 101 of the daily 250 are used once each
+	if admitted, err := limiter.admit(newTestTransfer(3, 49, 4)); err != nil || !admitted {
+		t.Errorf("expected transfer within limits to be admitted, got admitted=%t err=%v", admitted, err)
+	}
+	if admitted, err := limiter.admit(newTestTransfer(4, 50, 5)); err != nil || admitted {
+		t.Errorf("expected transfer over daily volume to be parked, got admitted=%t err=%v", admitted, err)
+	}
+}
+
+func TestTransferLimiterKeepsTransferParkedWhenSubmitFails(t *testing.T) {
+	limiter, cleanup := newTestLimiter(t)
+	defer cleanup()
+
+	transfer := newTestTransfer(1, 101, 1)
+	if admitted, err := limiter.admit(transfer); err != nil || admitted {
+		t.Fatalf("expected transfer to be parked, got admitted=%t err=%v", admitted, err)
+	}
+
+	errSubmit := errors.New("submit failed")
+	_, err := limiter.release(transfer.TxHash.Hash, func(transfer *ParkedTransfer) error { return errSubmit })
+	if err != errSubmit {
+		t.Fatalf("expected submit error, got %v", err)
+	}
+
+	parked, err := limiter.parked()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parked) != 1 || parked[0].TxHash.Hash != transfer.TxHash.Hash {
+		t.Fatalf("transfer not parked after failed submit: %v", parked)
+	}
+
+	// Failed approval must not use up the quota
+	if admitted, err := limiter.admit(newTestTransfer(1, 100, 2)); err != nil || !admitted {
+		t.Errorf("expected transfer within limits to be admitted, got admitted=%t err=%v", admitted, err)
+	}
+}
+
+func TestAdmitTransferReportsStateFileErrors(t *testing.T) {
+	limiter, cleanup := newTestLimiter(t)
+	defer cleanup()
+
+	limiter.stateFilePath = filepath.Join(limiter.stateFilePath, "missing", "state.json")
+	v := &Validator{limiter: limiter}
+
+	admitted, err := v.admitTransfer(newTestTransfer(1, 10, 1))
+	if err == nil {
+		t.Fatal("expected state file error")
+	}
+	if admitted {
+		t.Error("transfer admitted without checking limits")
+	}
+}
+
+func TestReloadAppliesNewLimits(t *testing.T) {
+	limiter, cleanup := newTestLimiter(t)
+	defer cleanup()
+
+	transfer := newTestTransfer(1, 200, 1)
+	if admitted, err := limiter.admit(transfer); err != nil || admitted {
+		t.Fatalf("expected transfer to be parked, got admitted=%t err=%v", admitted, err)
+	}
+
+	cfg := &config.Config{}
+	cfg.Validator.LimitsFile = filepath.Join(filepath.Dir(limiter.stateFilePath), "limits.json")
+	limits := `{"withdraw": {"default": {"maxPerTransaction": "500"}}}`
+	if err := ioutil.WriteFile(cfg.Validator.LimitsFile, []byte(limits), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	v := &Validator{limiter: limiter}
+	v.Reload(cfg)
+
+	if admitted, err := limiter.admit(newTestTransfer(1, 200, 2)); err != nil || !admitted {
+		t.Errorf("expected transfer within reloaded limits to be admitted, got admitted=%t err=%v", admitted, err)
+	}
+}
@@ -2,22 +2,29 @@ package validator
 
 import (
 	"context"
-	"crypto/ecdsa"
 	"fmt"
 	"log"
 	"math/big"
+	"net/http"
 	"strings"
+	"sync"
+	"time"
 
 	ethereum "github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
-	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"hameid.net/cdex/dex/_abi/DEXChain"
 	"hameid.net/cdex/dex/_abi/HomeBridge"
+	"hameid.net/cdex/dex/internal/config"
+	"hameid.net/cdex/dex/internal/health"
+	"hameid.net/cdex/dex/internal/lifecycle"
+	"hameid.net/cdex/dex/internal/metrics"
+	"hameid.net/cdex/dex/internal/signer"
+	"hameid.net/cdex/dex/internal/transactor"
 	"hameid.net/cdex/dex/internal/utils"
+	"hameid.net/cdex/dex/internal/wrappers"
 )
 
 type bridgeRef struct {
@@ -27,20 +34,31 @@ type bridgeRef struct {
 }
 
 type exchangeRef struct {
-	client   *ethclient.Client
-	instance *DEXChain.DEXChain
-	abi      *abi.ABI
+	client     *ethclient.Client
+	instance   *DEXChain.DEXChain
+	abi        *abi.ABI
+	transactor *transactor.Transactor
 }
 
 // Validator struct
 type Validator struct {
-	networks   *utils.NetworksInfo
-	contracts  *utils.ContractsInfo
-	privateKey *ecdsa.PrivateKey
-	publicKey  *ecdsa.PublicKey
-	address    *common.Address
-	bridge     *bridgeRef
-	exchange   *exchangeRef
+	networks  *utils.NetworksInfo
+	contracts *utils.ContractsInfo
+	signer    signer.Signer
+	address   *common.Address
+	bridge    *bridgeRef
+	exchange  *exchangeRef
+	limiter   *transferLimiter
+
+	messageVersion  uint8
+	typedSignatures bool
+	bridgeChainID   *big.Int
+
+	checkpoints *checkpointFile
+
+	// Event loops
+	wg           sync.WaitGroup
+	healthServer *http.Server
 }
 
 var channelSize = 10000
@@ -48,7 +66,7 @@ var channelSize = 10000
 // Initialize reads and decodes ABIs to be used for communicating with chain
 func (v *Validator) Initialize() {
 	fmt.Printf("\nConnecting to %s...\n", v.networks.Bridge.WebSocketProvider)
-	homeClient, err := ethclient.Dial(v.networks.Bridge.WebSocketProvider)
+	homeRPCClient, homeClient, err := transactor.Dial(v.networks.Bridge.WebSocketProvider)
 	if err != nil {
 		log.Panic(err)
 	}
@@ -65,8 +83,16 @@ func (v *Validator) Initialize() {
 	v.bridge.instance = bridge
 	v.bridge.abi = &bridgeABI
 
+	if v.messageVersion != utils.WithdrawalMessageLegacy || v.typedSignatures {
+		v.bridgeChainID, err = transactor.ChainID(context.Background(), homeRPCClient, &v.networks.Bridge)
+		if err != nil {
+			log.Panic(err)
+		}
+		fmt.Printf("Signing withdrawals for chain %s\n", v.bridgeChainID.String())
+	}
+
 	fmt.Printf("\nConnecting to %s...\n", v.networks.Exchange.WebSocketProvider)
-	exchangeClient, err := ethclient.Dial(v.networks.Exchange.WebSocketProvider)
+	exchangeRPCClient, exchangeClient, err := transactor.Dial(v.networks.Exchange.WebSocketProvider)
 	if err != nil {
 		log.Panic(err)
 	}
@@ -79,15 +105,22 @@ func (v *Validator) Initialize() {
 	if err != nil {
 		log.Fatal(err)
 	}
+	exchangeTransactor, err := transactor.NewTransactor(context.Background(), v.signer, exchangeRPCClient, &v.networks.Exchange.NetworkInfo)
+	if err != nil {
+		log.Panic(err)
+	}
+	fmt.Printf("Signing transactions for chain %s\n", exchangeTransactor.ChainID().String())
+
 	v.exchange.client = exchangeClient
 	v.exchange.instance = exchange
 	v.exchange.abi = &exchangeABI
+	v.exchange.transactor = exchangeTransactor
 
 	fmt.Printf("\n\nValidator initialization successful :)\n\n")
 }
 
-// RunOnBridgeNetwork runs validator on the bridge network
-func (v *Validator) RunOnBridgeNetwork() {
+// RunOnBridgeNetwork runs validator on the bridge network until ctx is done
+func (v *Validator) RunOnBridgeNetwork(ctx context.Context) {
 	fmt.Printf("Trying to listen events on Bridge contract %s...\n", v.contracts.Bridge.Address.Address.String())
 	query := ethereum.FilterQuery{
 		Addresses: []common.Address{v.contracts.Bridge.Address.Address},
@@ -95,219 +128,419 @@ func (v *Validator) RunOnBridgeNetwork() {
 	}
 
 	logs := make(chan types.Log, channelSize)
+	checkpoint := v.loadCheckpoint(utils.NetworkBridge)
 
-	sub, err := v.bridge.client.SubscribeFilterLogs(context.Background(), query, logs)
-	if err != nil {
-		log.Panic(err)
-	}
+	lag := metrics.WatchBlockLag(ctx, utils.NetworkBridge, v.bridge.client)
 
+	v.wg.Add(1)
 	go func() {
-	eventListenerLoop:
-		for {
-			select {
-			case err := <-sub.Err():
-				log.Fatal("Home Network Subcription Error:", err)
-
-			case vLog := <-logs:
-				fmt.Println("--------------------")
-				// Unpack deposit event
-				fmt.Println("Received `Deposit` event from Home Network")
-				depositEvent := struct {
-					Recipient common.Address
-					Token     common.Address
-					Value     *big.Int
-				}{}
-				err := v.bridge.abi.Unpack(&depositEvent, "Deposit", vLog.Data)
-				if err != nil {
-					log.Fatal("Unpack: ", err)
-					continue eventListenerLoop
-				}
-
-				// Forward event to Foreign bridge
-				nonce, err := v.exchange.client.PendingNonceAt(context.Background(), *v.address)
-				if err != nil {
-					log.Fatal(err)
-					continue eventListenerLoop
-				}
-
-				// gasPrice, err := v.exchange.client.SuggestGasPrice(context.Background())
-				// if err != nil {
-				// 	log.Fatal(err)
-				// 	continue eventListenerLoop
-				// }
-
-				auth := bind.NewKeyedTransactor(v.privateKey)
-
-				auth.Nonce = big.NewInt(int64(nonce))
-				auth.Value = big.NewInt(0)
-				auth.GasLimit = uint64(500000)
-				auth.GasPrice = big.NewInt(1) // gasPrice
-
-				tx, err := v.exchange.instance.Deposit(auth, depositEvent.Recipient, depositEvent.Token, depositEvent.Value, vLog.TxHash)
-				if err != nil {
-					fmt.Println("Failed to forward transaction:", err)
-					continue eventListenerLoop
-				}
-
-				// if receipt, err := v.exchange.client.TransactionReceipt(context.Background(), tx.Hash()); err != nil {
-				// 	fmt.Println("Failed to get receipt...", err)
-				// 	continue eventListenerLoop
-				// } else if receipt.Status == 0 {
-				// 	fmt.Println("Failed after submission...")
-				// 	continue eventListenerLoop
-				// }
-
-				fmt.Println("Transaction forwarded to foreign network:", tx.Hash().Hex())
-				fmt.Println("--------------------")
-			}
+		defer v.wg.Done()
+
+		err := lifecycle.ProcessLogs(ctx, v.bridge.client, query, logs, checkpoint, func(vLog types.Log) {
+			start := time.Now()
+			lag.Processed(vLog)
+			v.depositLogCallback(vLog)
+			metrics.ObserveEvent(utils.NetworkBridge, "Deposit", start)
+		}, func(block uint64) {
+			v.saveCheckpoint(utils.NetworkBridge, block)
+		})
+		if err != nil {
+			log.Fatal("Home Network Subcription Error:", err)
 		}
 	}()
 }
 
-// RunOnExchangeNetwork runs validator on the exchange network
-func (v *Validator) RunOnExchangeNetwork() {
+// depositLogCallback forwards a deposit on the home network to the exchange
+// network
+func (v *Validator) depositLogCallback(vLog types.Log) {
+	fmt.Println("--------------------")
+	// Unpack deposit event
+	fmt.Println("Received `Deposit` event from Home Network")
+	depositEvent := struct {
+		Recipient common.Address
+		Token     common.Address
+		Value     *big.Int
+	}{}
+	err := v.bridge.abi.Unpack(&depositEvent, "Deposit", vLog.Data)
+	if err != nil {
+		log.Fatal("Unpack: ", err)
+		return
+	}
+
+	transfer := newParkedTransfer(transferKindDeposit, &depositEvent.Recipient, &depositEvent.Token, depositEvent.Value, &vLog.TxHash, blockTime(v.bridge.client, vLog))
+	admitted, err := v.admitTransfer(transfer)
+	if err != nil {
+		// Do not sign anything we could not account for, nor drop it
+		log.Fatal("Cannot check transfer limits: ", err)
+	}
+	if !admitted {
+		fmt.Println("--------------------")
+		return
+	}
+
+	if err := v.forwardDeposit(&depositEvent.Recipient, &depositEvent.Token, depositEvent.Value, &vLog.TxHash); err != nil {
+		fmt.Println("Failed to forward transaction:", err)
+		return
+	}
+	v.recordTransfer(transfer)
+
+	fmt.Println("--------------------")
+}
+
+// RunOnExchangeNetwork runs validator on the exchange network until ctx is
+// done
+func (v *Validator) RunOnExchangeNetwork(ctx context.Context) {
 	fmt.Printf("Trying to listen events on Exchange contract %s...\n", v.contracts.Exchange.Address.Address.String())
 	query := ethereum.FilterQuery{
 		Addresses: []common.Address{v.contracts.Exchange.Address.Address},
 		Topics:    [][]common.Hash{{v.contracts.Exchange.Topics.Withdraw.Hash}},
 	}
 	logs := make(chan types.Log, channelSize)
-	sub, err := v.exchange.client.SubscribeFilterLogs(context.Background(), query, logs)
-	if err != nil {
-		log.Panic(err)
-	}
+	checkpoint := v.loadCheckpoint(utils.NetworkExchange)
 
+	lag := metrics.WatchBlockLag(ctx, utils.NetworkExchange, v.exchange.client)
+
+	v.wg.Add(1)
 	go func() {
-	eventListenerLoop:
-		for {
-			select {
-			case err := <-sub.Err():
-				log.Fatal("Foreign Network Subcription Error:", err)
-
-			case vLog := <-logs:
-				fmt.Println("--------------------")
-				fmt.Println("Received `Withdraw` event from Foreign Network")
-				// if vLog.Topics[0].Hex() != withdrawEventTopic.Hex() {
-				// 	fmt.Println(vLog.Topics[0].Hex())
-				// 	fmt.Println("Not a withdraw event")
-				// 	fmt.Println("--------------------")
-				// 	continue eventListenerLoop
-				// }
-				withdrawEvent := struct {
-					Recipient common.Address
-					Token     common.Address
-					Value     *big.Int
-				}{}
-				err := v.exchange.abi.Unpack(&withdrawEvent, "Withdraw", vLog.Data)
-				if err != nil {
-					log.Fatal("Unpack: ", err)
-					continue eventListenerLoop
-				}
-
-				serializedMessage, err := utils.SerializeWithdrawalMessage(&withdrawEvent.Recipient, &withdrawEvent.Token, withdrawEvent.Value, &vLog.TxHash)
-				if err != nil {
-					log.Fatal("Cannot serialize message: ", err)
-					continue eventListenerLoop
-				}
-
-				signature, err := utils.SignMessageWithPrivateKey(serializedMessage, v.privateKey)
-				if err != nil {
-					log.Fatal("Cannot sign message: ", err)
-					continue eventListenerLoop
-				}
-
-				// fmt.Println(common.Bytes2Hex(signature.R[:]), common.Bytes2Hex(signature.S[:]), signature.V)
-
-				fmt.Println("Message Hash", common.Bytes2Hex(signature.Hash))
-
-				// Forward event to Foreign bridge
-				nonce, err := v.exchange.client.PendingNonceAt(context.Background(), *v.address)
-				if err != nil {
-					log.Fatal(err)
-					continue eventListenerLoop
-				}
-
-				// gasPrice, err := v.exchange.client.SuggestGasPrice(context.Background())
-				// if err != nil {
-				// 	log.Fatal(err)
-				// 	continue eventListenerLoop
-				// }
-
-				auth := bind.NewKeyedTransactor(v.privateKey)
-
-				auth.Nonce = big.NewInt(int64(nonce))
-				auth.Value = big.NewInt(0)
-				auth.GasLimit = uint64(500000)
-				auth.GasPrice = big.NewInt(0)
-
-				tx, err := v.exchange.instance.SubmitSignature(auth, signature.Raw[:65], serializedMessage)
-				if err != nil {
-					fmt.Println("Failed to sign & forward transaction:", err)
-					continue eventListenerLoop
-				}
-
-				// if receipt, err := v.exchange.client.TransactionReceipt(context.Background(), tx.Hash()); err != nil {
-				// 	fmt.Println("Failed to get receipt...", err)
-				// 	continue eventListenerLoop
-				// } else if receipt.Status == 0 {
-				// 	fmt.Println("Failed after submission...")
-				// 	continue eventListenerLoop
-				// }
-				fmt.Println("Transaction signed and forwarded to foreign network:", tx.Hash().Hex())
-				fmt.Println("--------------------")
-			}
+		defer v.wg.Done()
+
+		err := lifecycle.ProcessLogs(ctx, v.exchange.client, query, logs, checkpoint, func(vLog types.Log) {
+			start := time.Now()
+			lag.Processed(vLog)
+			v.withdrawLogCallback(vLog)
+			metrics.ObserveEvent(utils.NetworkExchange, "Withdraw", start)
+		}, func(block uint64) {
+			v.saveCheckpoint(utils.NetworkExchange, block)
+		})
+		if err != nil {
+			log.Fatal("Foreign Network Subcription Error:", err)
 		}
 	}()
 }
 
-// Quit terminates validator instance
-func (v *Validator) Quit() {
-	fmt.Printf("\nCleaning up...\n")
-	fmt.Printf("\nBye bye...\n")
+// saveCheckpoint records the last block of the network whose events are all
+// processed, if a checkpoint file is configured
+func (v *Validator) saveCheckpoint(network string, block uint64) {
+	if block == 0 {
+		return
+	}
+
+	fmt.Printf("Processed events of %s network up to block %d\n", network, block)
+
+	if v.checkpoints == nil {
+		return
+	}
+	if err := v.checkpoints.save(network, block); err != nil {
+		fmt.Println("CHECKPOINT", err)
+	}
 }
 
-// NewValidator creates and populates a Vaidator struct object
-func NewValidator(contractsFilePath, networksFilePath, keystoreFilePath, passwordFilePath string) *Validator {
-	fmt.Printf("Starting validator...\n")
-	fmt.Printf("Reading config files...\n")
-	fmt.Printf("Reading %s...\n", contractsFilePath)
-	// Read config files
-	contractsInfo, err := utils.ReadContractsInfo(contractsFilePath)
+// loadCheckpoint returns the block the validator stopped at on its last run
+// on the network, zero if there is none or no checkpoint file is configured.
+// Events of later blocks are replayed.
+func (v *Validator) loadCheckpoint(network string) uint64 {
+	if v.checkpoints == nil {
+		return 0
+	}
+
+	blocks, err := v.checkpoints.load()
 	if err != nil {
 		log.Panic(err)
 	}
 
-	fmt.Printf("Reading %s...\n", networksFilePath)
-	nwInfo, err := utils.ReadNetworksInfo(networksFilePath)
+	block, ok := blocks[network]
+	if ok {
+		fmt.Printf("Resuming events of %s network after block %d\n", network, block)
+	}
+
+	return block
+}
+
+// withdrawLogCallback signs a withdrawal on the exchange network and submits
+// the signature
+func (v *Validator) withdrawLogCallback(vLog types.Log) {
+	fmt.Println("--------------------")
+	fmt.Println("Received `Withdraw` event from Foreign Network")
+	// if vLog.Topics[0].Hex() != withdrawEventTopic.Hex() {
+	// 	fmt.Println(vLog.Topics[0].Hex())
+	// 	fmt.Println("Not a withdraw event")
+	// 	fmt.Println("--------------------")
+	// 	return
+	// }
+	withdrawEvent := struct {
+		Recipient common.Address
+		Token     common.Address
+		Value     *big.Int
+	}{}
+	err := v.exchange.abi.Unpack(&withdrawEvent, "Withdraw", vLog.Data)
 	if err != nil {
-		log.Panic(err)
+		log.Fatal("Unpack: ", err)
+		return
 	}
 
-	fmt.Printf("Decrypting keystore %s...\n", keystoreFilePath)
-	// Decrypt keystore
-	accountKey, err := utils.DecryptPrivateKeyFromKeystoreWithPasswordFile(keystoreFilePath, passwordFilePath)
+	transfer := newParkedTransfer(transferKindWithdraw, &withdrawEvent.Recipient, &withdrawEvent.Token, withdrawEvent.Value, &vLog.TxHash, blockTime(v.exchange.client, vLog))
+	admitted, err := v.admitTransfer(transfer)
 	if err != nil {
-		log.Panic(err)
+		// Do not sign anything we could not account for, nor drop it
+		log.Fatal("Cannot check transfer limits: ", err)
+	}
+	if !admitted {
+		fmt.Println("--------------------")
+		return
+	}
+
+	if err := v.submitWithdrawSignature(&withdrawEvent.Recipient, &withdrawEvent.Token, withdrawEvent.Value, &vLog.TxHash); err != nil {
+		fmt.Println("Failed to sign & forward transaction:", err)
+		return
+	}
+	v.recordTransfer(transfer)
+
+	fmt.Println("--------------------")
+}
+
+// forwardDeposit confirms a home network deposit on the exchange network
+func (v *Validator) forwardDeposit(recipient *common.Address, token *common.Address, value *big.Int, txHash *common.Hash) error {
+	auth, err := v.exchange.transactor.Opts(
+		context.Background(),
+		v.contracts.Exchange.Address.Address,
+		v.exchange.abi,
+		"deposit",
+		*recipient, *token, value, *txHash,
+	)
+	if err != nil {
+		return err
+	}
+
+	tx, err := v.exchange.instance.Deposit(auth, *recipient, *token, value, *txHash)
+	if err != nil {
+		v.exchange.transactor.ResyncNonce()
+		return err
+	}
+
+	fmt.Println("Transaction forwarded to foreign network:", tx.Hash().Hex())
+	depositsForwarded.Inc()
+
+	return nil
+}
+
+// submitWithdrawSignature signs the withdrawal message and submits the
+// signature to the exchange network
+func (v *Validator) submitWithdrawSignature(recipient *common.Address, token *common.Address, value *big.Int, txHash *common.Hash) error {
+	message := &utils.WithdrawalMessage{
+		Version:   v.messageVersion,
+		ChainID:   v.bridgeChainID,
+		Contract:  v.contracts.Bridge.Address.Address,
+		Recipient: *recipient,
+		Token:     *token,
+		Amount:    value,
+		TxHash:    *txHash,
+	}
+
+	serializedMessage, err := message.Encode()
+	if err != nil {
+		return err
+	}
+
+	var signature utils.Signature
+	if v.typedSignatures {
+		signature, err = v.signer.SignTypedWithdrawal(message)
+	} else {
+		signature, err = v.signer.SignMessage(serializedMessage)
+	}
+	if err != nil {
+		return err
 	}
 
-	privateKey := accountKey.PrivateKey
-	publicKey := privateKey.Public()
+	fmt.Println("Message Hash", common.Bytes2Hex(signature.Hash))
+
+	auth, err := v.exchange.transactor.Opts(
+		context.Background(),
+		v.contracts.Exchange.Address.Address,
+		v.exchange.abi,
+		"submitSignature",
+		signature.Raw[:65], serializedMessage,
+	)
+	if err != nil {
+		return err
+	}
+
+	tx, err := v.exchange.instance.SubmitSignature(auth, signature.Raw[:65], serializedMessage)
+	if err != nil {
+		v.exchange.transactor.ResyncNonce()
+		return err
+	}
+
+	fmt.Println("Transaction signed and forwarded to foreign network:", tx.Hash().Hex())
+	signaturesSubmitted.Inc()
+
+	return nil
+}
+
+// UseWithdrawalMessageFormat selects the withdrawal message version and
+// whether messages are signed as EIP-712 typed data. Legacy messages signed
+// with keccak256 are the default, versioned messages need contracts that
+// accept them.
+func (v *Validator) UseWithdrawalMessageFormat(version uint8, typed bool) {
+	v.messageVersion = version
+	v.typedSignatures = typed
+}
+
+func newParkedTransfer(kind string, recipient *common.Address, token *common.Address, value *big.Int, txHash *common.Hash, timestamp int64) *ParkedTransfer {
+	return &ParkedTransfer{
+		Kind:      kind,
+		Recipient: wrappers.WrapAddress(recipient),
+		Token:     wrappers.WrapAddress(token),
+		Amount:    wrappers.WrapBigInt(value),
+		TxHash:    wrappers.WrapHash(txHash),
+		Timestamp: timestamp,
+	}
+}
+
+// blockTime returns timestamp of the block of the log. Transfers count
+// against the limits from the time they were made, so that replayed ones
+// fall out of the window like the originals. Current time is used if the
+// block cannot be read.
+func blockTime(client *ethclient.Client, vLog types.Log) int64 {
+	header, err := client.HeaderByNumber(context.Background(), new(big.Int).SetUint64(vLog.BlockNumber))
+	if err != nil {
+		fmt.Println("BLOCK_TIME", err)
+		return time.Now().Unix()
+	}
+
+	return header.Time.Int64()
+}
+
+// admitTransfer checks the transfer against the configured limits. Transfers
+// over the limits are parked and false is returned.
+func (v *Validator) admitTransfer(transfer *ParkedTransfer) (bool, error) {
+	if v.limiter == nil {
+		return true, nil
+	}
+
+	admitted, err := v.limiter.admit(transfer)
+	if err != nil {
+		return false, err
+	}
+
+	if !admitted {
+		fmt.Printf("Parked %s %s for manual approval: %s\n", transfer.Kind, transfer.TxHash.Hex(), transfer.Reason)
+	}
+
+	return admitted, nil
+}
+
+// recordTransfer counts a submitted transfer against the configured limits
+func (v *Validator) recordTransfer(transfer *ParkedTransfer) {
+	if v.limiter == nil {
+		return
+	}
+
+	if err := v.limiter.record(transfer); err != nil {
+		// Admission of the next transfer fails on the same state file
+		fmt.Println("Cannot record transfer against limits:", err)
+	}
+}
+
+// Reload reads the limits file again and applies its limits to transfers
+// from now on. Paths of the limits and state files need a restart.
+func (v *Validator) Reload(cfg *config.Config) {
+	if v.limiter == nil {
+		return
+	}
+
+	limits, err := utils.ReadLimitsInfo(cfg.Validator.LimitsFile)
+	if err != nil {
+		fmt.Println("Cannot reload transfer limits:", err)
+		return
+	}
+	v.limiter.setLimits(limits)
+
+	fmt.Printf("Transfer limits reloaded from %s\n", cfg.Validator.LimitsFile)
+}
+
+// ParkedTransfers returns the transfers waiting for manual approval
+func (v *Validator) ParkedTransfers() ([]ParkedTransfer, error) {
+	if v.limiter == nil {
+		return []ParkedTransfer{}, nil
+	}
+
+	return v.limiter.parked()
+}
+
+// ApproveParkedTransfer signs or forwards a parked transfer
+func (v *Validator) ApproveParkedTransfer(txHash common.Hash) error {
+	if v.limiter == nil {
+		return errTransferNotParked
+	}
+
+	_, err := v.limiter.release(txHash, func(transfer *ParkedTransfer) error {
+		if transfer.Kind == transferKindDeposit {
+			return v.forwardDeposit(&transfer.Recipient.Address, &transfer.Token.Address, &transfer.Amount.Int, &transfer.TxHash.Hash)
+		}
+
+		return v.submitWithdrawSignature(&transfer.Recipient.Address, &transfer.Token.Address, &transfer.Amount.Int, &transfer.TxHash.Hash)
+	})
+
+	return err
+}
+
+// ServeHealth serves health checks of both chains and metrics on the port.
+// Validator must be initialized.
+func (v *Validator) ServeHealth(port uint64, maxBlockAge time.Duration) {
+	validatorHealth := health.NewHealth(config.ServiceValidator)
+	validatorHealth.AddCheck("bridge", health.Chain(v.bridge.client, maxBlockAge))
+	validatorHealth.AddCheck("exchange", health.Chain(v.exchange.client, maxBlockAge))
+	v.healthServer = validatorHealth.Serve(port, metrics.Wrap(http.NotFoundHandler()))
+}
+
+// Quit waits for event loops to stop, then terminates validator instance.
+// Their context must be done.
+func (v *Validator) Quit() {
+	ctx, cancel := lifecycle.ShutdownContext()
+	defer cancel()
+
+	fmt.Printf("\nWaiting for event handlers to finish...\n")
+	if err := lifecycle.Wait(ctx, &v.wg); err != nil {
+		fmt.Println("Event handlers did not finish:", err)
+	}
+
+	fmt.Printf("\nCleaning up...\n")
+	if v.healthServer != nil {
+		v.healthServer.Shutdown(ctx)
+	}
+	v.bridge.client.Close()
+	v.exchange.client.Close()
+	fmt.Printf("\nBye bye...\n")
+}
+
+// NewValidator creates and populates a Vaidator struct object
+func NewValidator(cfg *config.Config, accountSigner signer.Signer) *Validator {
+	fmt.Printf("Starting validator...\n")
+
+	var limiter *transferLimiter
+	if cfg.Validator.LimitsFile != "" {
+		fmt.Printf("Reading %s...\n", cfg.Validator.LimitsFile)
+		limits, err := utils.ReadLimitsInfo(cfg.Validator.LimitsFile)
+		if err != nil {
+			log.Panic(err)
+		}
+		limiter = newTransferLimiter(limits, cfg.Validator.LimitsStateFile)
+	}
 
-	publicKeyECDSA, ok := publicKey.(*ecdsa.PublicKey)
-	if !ok {
-		log.Fatal("error casting public key to ECDSA")
+	var checkpoints *checkpointFile
+	if cfg.Validator.CheckpointFile != "" {
+		checkpoints = newCheckpointFile(cfg.Validator.CheckpointFile)
 	}
 
-	fromAddress := crypto.PubkeyToAddress(*publicKeyECDSA)
+	fromAddress := accountSigner.Address()
 
 	fmt.Printf("Validator account address: %s\n\n", fromAddress.String())
 
 	return &Validator{
-		networks:   nwInfo,
-		contracts:  contractsInfo,
-		privateKey: privateKey,
-		publicKey:  publicKeyECDSA,
-		address:    &fromAddress,
+		networks:  cfg.Networks,
+		contracts: cfg.Contracts,
+		signer:    accountSigner,
+		address:   &fromAddress,
 		bridge: &bridgeRef{
 			client:   nil,
 			instance: nil,
@@ -318,5 +551,7 @@ func NewValidator(contractsFilePath, networksFilePath, keystoreFilePath, passwor
 			instance: nil,
 			abi:      nil,
 		},
+		limiter:     limiter,
+		checkpoints: checkpoints,
 	}
 }
@@ -0,0 +1,26 @@
+package validator
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"hameid.net/cdex/dex/internal/metrics"
+)
+
+var (
+	depositsForwarded = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "validator",
+		Name:      "deposits_forwarded_total",
+		Help:      "Deposits of the home network forwarded to the exchange network.",
+	})
+
+	signaturesSubmitted = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "validator",
+		Name:      "signatures_submitted_total",
+		Help:      "Withdrawal signatures submitted to the exchange network.",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(depositsForwarded, signaturesSubmitted)
+}
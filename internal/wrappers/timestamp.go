@@ -3,6 +3,7 @@ package wrappers
 import (
 	"database/sql/driver"
 	"fmt"
+	"strconv"
 	"time"
 )
 
@@ -31,11 +32,26 @@ func (timestamp *Timestamp) Value() (driver.Value, error) {
 	return int64(timestamp.t), nil
 }
 
+// Uint64 returns seconds since Unix epoch
+func (timestamp *Timestamp) Uint64() uint64 {
+	return timestamp.t
+}
+
 // MarshalJSON marshals data
 func (timestamp *Timestamp) MarshalJSON() ([]byte, error) {
 	return []byte(fmt.Sprintf(`%d`, timestamp.t)), nil
 }
 
+// UnmarshalJSON unmarshals seconds since Unix epoch
+func (timestamp *Timestamp) UnmarshalJSON(data []byte) error {
+	t, err := strconv.ParseUint(string(data), 10, 64)
+	if err != nil {
+		return err
+	}
+	timestamp.t = t
+	return nil
+}
+
 // WrapTimestamp wraps uint64
 func WrapTimestamp(timestamp uint64) *Timestamp {
 	return &Timestamp{t: timestamp}
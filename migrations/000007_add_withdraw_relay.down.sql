@@ -0,0 +1,4 @@
+ALTER TABLE public.withdraw_meta
+    DROP COLUMN IF EXISTS relay_tx_hash,
+    DROP COLUMN IF EXISTS relay_attempts,
+    DROP COLUMN IF EXISTS relayed_at;
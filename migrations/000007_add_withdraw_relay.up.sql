@@ -0,0 +1,4 @@
+ALTER TABLE public.withdraw_meta
+    ADD COLUMN relay_tx_hash character varying(66),
+    ADD COLUMN relay_attempts int NOT NULL DEFAULT 0,
+    ADD COLUMN relayed_at TIMESTAMP without time zone;
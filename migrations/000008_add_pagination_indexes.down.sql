@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS public.orders_created_at_order_hash_idx;
+DROP INDEX IF EXISTS public.trades_pair_traded_at_tx_hash_idx;
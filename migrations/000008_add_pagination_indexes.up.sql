@@ -0,0 +1,3 @@
+-- Keyset pagination of orders and trades
+CREATE INDEX orders_created_at_order_hash_idx ON public.orders (created_at DESC, order_hash DESC);
+CREATE INDEX trades_pair_traded_at_tx_hash_idx ON public.trades (token, base, traded_at DESC, tx_hash DESC);
@@ -0,0 +1,7 @@
+DROP MATERIALIZED VIEW IF EXISTS public.candles_1w;
+DROP MATERIALIZED VIEW IF EXISTS public.candles_1d;
+DROP MATERIALIZED VIEW IF EXISTS public.candles_4h;
+DROP MATERIALIZED VIEW IF EXISTS public.candles_1h;
+DROP MATERIALIZED VIEW IF EXISTS public.candles_15m;
+DROP MATERIALIZED VIEW IF EXISTS public.candles_5m;
+DROP MATERIALIZED VIEW IF EXISTS public.candles_1m;
@@ -0,0 +1,144 @@
+-- Candles of the resolutions served by /trades/ohlc, aggregated continuously
+-- from trades. Created without data so the migration can run in a
+-- transaction; backfill history afterwards with `app refresh-candles`.
+-- Buckets not yet materialized are aggregated from trades at query time.
+
+CREATE MATERIALIZED VIEW public.candles_1m
+WITH (timescaledb.continuous, timescaledb.materialized_only = false) AS
+SELECT token,
+	base,
+	time_bucket(INTERVAL '1 minute', traded_at) AS bucket,
+	first(price, traded_at) AS open,
+	max(price) AS high,
+	min(price) AS low,
+	last(price, traded_at) AS close,
+	sum(volume) AS volume,
+	count(*) AS trades
+  FROM public.trades
+  GROUP BY token, base, bucket
+WITH NO DATA;
+
+SELECT add_continuous_aggregate_policy('public.candles_1m',
+	start_offset => INTERVAL '1 hour',
+	end_offset => INTERVAL '1 minute',
+	schedule_interval => INTERVAL '1 minute');
+
+CREATE MATERIALIZED VIEW public.candles_5m
+WITH (timescaledb.continuous, timescaledb.materialized_only = false) AS
+SELECT token,
+	base,
+	time_bucket(INTERVAL '5 minutes', traded_at) AS bucket,
+	first(price, traded_at) AS open,
+	max(price) AS high,
+	min(price) AS low,
+	last(price, traded_at) AS close,
+	sum(volume) AS volume,
+	count(*) AS trades
+  FROM public.trades
+  GROUP BY token, base, bucket
+WITH NO DATA;
+
+SELECT add_continuous_aggregate_policy('public.candles_5m',
+	start_offset => INTERVAL '3 hours',
+	end_offset => INTERVAL '5 minutes',
+	schedule_interval => INTERVAL '5 minutes');
+
+CREATE MATERIALIZED VIEW public.candles_15m
+WITH (timescaledb.continuous, timescaledb.materialized_only = false) AS
+SELECT token,
+	base,
+	time_bucket(INTERVAL '15 minutes', traded_at) AS bucket,
+	first(price, traded_at) AS open,
+	max(price) AS high,
+	min(price) AS low,
+	last(price, traded_at) AS close,
+	sum(volume) AS volume,
+	count(*) AS trades
+  FROM public.trades
+  GROUP BY token, base, bucket
+WITH NO DATA;
+
+SELECT add_continuous_aggregate_policy('public.candles_15m',
+	start_offset => INTERVAL '6 hours',
+	end_offset => INTERVAL '15 minutes',
+	schedule_interval => INTERVAL '15 minutes');
+
+CREATE MATERIALIZED VIEW public.candles_1h
+WITH (timescaledb.continuous, timescaledb.materialized_only = false) AS
+SELECT token,
+	base,
+	time_bucket(INTERVAL '1 hour', traded_at) AS bucket,
+	first(price, traded_at) AS open,
+	max(price) AS high,
+	min(price) AS low,
+	last(price, traded_at) AS close,
+	sum(volume) AS volume,
+	count(*) AS trades
+  FROM public.trades
+  GROUP BY token, base, bucket
+WITH NO DATA;
+
+SELECT add_continuous_aggregate_policy('public.candles_1h',
+	start_offset => INTERVAL '1 day',
+	end_offset => INTERVAL '1 hour',
+	schedule_interval => INTERVAL '1 hour');
+
+CREATE MATERIALIZED VIEW public.candles_4h
+WITH (timescaledb.continuous, timescaledb.materialized_only = false) AS
+SELECT token,
+	base,
+	time_bucket(INTERVAL '4 hours', traded_at) AS bucket,
+	first(price, traded_at) AS open,
+	max(price) AS high,
+	min(price) AS low,
+	last(price, traded_at) AS close,
+	sum(volume) AS volume,
+	count(*) AS trades
+  FROM public.trades
+  GROUP BY token, base, bucket
+WITH NO DATA;
+
+SELECT add_continuous_aggregate_policy('public.candles_4h',
+	start_offset => INTERVAL '3 days',
+	end_offset => INTERVAL '4 hours',
+	schedule_interval => INTERVAL '1 hour');
+
+CREATE MATERIALIZED VIEW public.candles_1d
+WITH (timescaledb.continuous, timescaledb.materialized_only = false) AS
+SELECT token,
+	base,
+	time_bucket(INTERVAL '1 day', traded_at) AS bucket,
+	first(price, traded_at) AS open,
+	max(price) AS high,
+	min(price) AS low,
+	last(price, traded_at) AS close,
+	sum(volume) AS volume,
+	count(*) AS trades
+  FROM public.trades
+  GROUP BY token, base, bucket
+WITH NO DATA;
+
+SELECT add_continuous_aggregate_policy('public.candles_1d',
+	start_offset => INTERVAL '7 days',
+	end_offset => INTERVAL '1 day',
+	schedule_interval => INTERVAL '1 hour');
+
+CREATE MATERIALIZED VIEW public.candles_1w
+WITH (timescaledb.continuous, timescaledb.materialized_only = false) AS
+SELECT token,
+	base,
+	time_bucket(INTERVAL '1 week', traded_at) AS bucket,
+	first(price, traded_at) AS open,
+	max(price) AS high,
+	min(price) AS low,
+	last(price, traded_at) AS close,
+	sum(volume) AS volume,
+	count(*) AS trades
+  FROM public.trades
+  GROUP BY token, base, bucket
+WITH NO DATA;
+
+SELECT add_continuous_aggregate_policy('public.candles_1w',
+	start_offset => INTERVAL '1 month',
+	end_offset => INTERVAL '1 week',
+	schedule_interval => INTERVAL '1 day');
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS public.trades_sell_order_hash_idx;
+DROP INDEX IF EXISTS public.trades_buy_order_hash_idx;
@@ -0,0 +1,3 @@
+-- Fills of an order
+CREATE INDEX trades_buy_order_hash_idx ON public.trades USING hash (buy_order_hash);
+CREATE INDEX trades_sell_order_hash_idx ON public.trades USING hash (sell_order_hash);
@@ -0,0 +1,12 @@
+ALTER TABLE public.orders
+    DROP COLUMN IF EXISTS cancel_fee,
+    DROP COLUMN IF EXISTS cancelled_at;
+
+ALTER TABLE public.trades
+    DROP COLUMN IF EXISTS make_fee,
+    DROP COLUMN IF EXISTS take_fee;
+
+ALTER TABLE public.withdraw_meta
+    DROP COLUMN IF EXISTS requested_at;
+
+DROP TABLE IF EXISTS public.deposits;
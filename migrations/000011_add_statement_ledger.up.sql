@@ -0,0 +1,27 @@
+-- Deposits credited on the exchange network
+CREATE TABLE public.deposits
+(
+    tx_hash character varying(66) NOT NULL, -- deposit transaction on bridge network
+    recipient character varying(42) NOT NULL,
+    token character varying(42) NOT NULL,
+    amount numeric NOT NULL CHECK (amount > 0),
+    deposited_at TIMESTAMP without time zone NOT NULL
+);
+
+CREATE INDEX ON public.deposits USING hash (recipient);
+
+SELECT create_hypertable('public.deposits', 'deposited_at');
+
+-- Block times of withdraw requests. Requests recorded earlier have none.
+ALTER TABLE public.withdraw_meta
+    ADD COLUMN requested_at TIMESTAMP without time zone;
+
+-- Fees paid by taker in token and by maker in base. Trades recorded
+-- earlier have none and are charged at the current rates in statements.
+ALTER TABLE public.trades
+    ADD COLUMN take_fee numeric,
+    ADD COLUMN make_fee numeric;
+
+ALTER TABLE public.orders
+    ADD COLUMN cancelled_at TIMESTAMP without time zone,
+    ADD COLUMN cancel_fee numeric NOT NULL DEFAULT 0;
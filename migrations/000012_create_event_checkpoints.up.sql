@@ -0,0 +1,7 @@
+-- Last block of each network whose events the relayer has processed
+CREATE TABLE public.event_checkpoints
+(
+    network character varying(16) NOT NULL PRIMARY KEY,
+    block_number bigint NOT NULL,
+    updated_at TIMESTAMP without time zone NOT NULL DEFAULT now()
+);
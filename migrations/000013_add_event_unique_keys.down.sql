@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS public.withdraw_meta_tx_hash_key;
+DROP INDEX IF EXISTS public.deposits_tx_hash_key;
+DROP INDEX IF EXISTS public.trades_tx_hash_log_index_key;
+DROP INDEX IF EXISTS public.orders_order_hash_key;
+
+ALTER TABLE public.trades
+    DROP COLUMN IF EXISTS log_index;
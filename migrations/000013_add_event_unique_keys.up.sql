@@ -0,0 +1,26 @@
+-- Rows recorded twice by replayed events
+DELETE FROM public.orders a USING public.orders b
+    WHERE a.order_hash = b.order_hash AND a.created_at = b.created_at AND a.ctid > b.ctid;
+
+DELETE FROM public.trades a USING public.trades b
+    WHERE a.tx_hash = b.tx_hash AND a.traded_at = b.traded_at
+        AND a.buy_order_hash = b.buy_order_hash AND a.sell_order_hash = b.sell_order_hash
+        AND a.volume = b.volume AND a.ctid > b.ctid;
+
+DELETE FROM public.deposits a USING public.deposits b
+    WHERE a.tx_hash = b.tx_hash AND a.deposited_at = b.deposited_at AND a.ctid > b.ctid;
+
+DELETE FROM public.withdraw_meta a USING public.withdraw_meta b
+    WHERE a.tx_hash = b.tx_hash AND a.ctid > b.ctid;
+
+-- Position of the Trade event in its transaction. Trades recorded earlier
+-- have none.
+ALTER TABLE public.trades
+    ADD COLUMN log_index integer;
+
+-- Events are identified by order hash or transaction and log index. Unique
+-- indexes of hypertables include their time column.
+CREATE UNIQUE INDEX orders_order_hash_key ON public.orders (order_hash, created_at);
+CREATE UNIQUE INDEX trades_tx_hash_log_index_key ON public.trades (tx_hash, log_index, traded_at);
+CREATE UNIQUE INDEX deposits_tx_hash_key ON public.deposits (tx_hash, deposited_at);
+CREATE UNIQUE INDEX withdraw_meta_tx_hash_key ON public.withdraw_meta (tx_hash);
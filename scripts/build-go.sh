@@ -2,4 +2,5 @@
 go install hameid.net/cdex/dex/cmd/validator
 go install hameid.net/cdex/dex/cmd/relayer
 go install hameid.net/cdex/dex/cmd/app
-go install hameid.net/cdex/dex/cmd/socketserver
\ No newline at end of file
+go install hameid.net/cdex/dex/cmd/socketserver
+go install hameid.net/cdex/dex/cmd/rpcserver
\ No newline at end of file
@@ -1,4 +1,13 @@
 import CONFIG from './config'
+import store from '@/store'
+
+interface ISession {
+	token: string
+	expires_at: number
+}
+
+// Sessions by lowercase wallet address
+const sessions: { [address: string]: ISession } = {}
 
 const APIService = {
 	getOHLCData(token: string, base: string) {
@@ -14,6 +23,7 @@ const APIService = {
 		url.searchParams.set('base', base)
 		return fetch(url.toJSON())
 			.then(resp => resp.json())
+			.then(page => page.items)
 	},
 	getUserTradeHistory(token: string, base: string, user: string) {
 		let url = getAbsoluteEndpoint('trades/history')
@@ -22,6 +32,7 @@ const APIService = {
 		url.searchParams.set('user', user)
 		return fetch(url.toJSON())
 			.then(resp => resp.json())
+			.then(page => page.items)
 	},
 	getOrderbook(token: string, base: string) {
 		let url = getAbsoluteEndpoint('orderbook')
@@ -38,6 +49,7 @@ const APIService = {
 		url.searchParams.set('status', '0')
 		return fetch(url.toJSON())
 			.then(resp => resp.json())
+			.then(page => page.items)
 	},
 	getAllOpenOrders(user: string) {
 		let url = getAbsoluteEndpoint('orders')
@@ -45,22 +57,72 @@ const APIService = {
 		url.searchParams.set('status', '0')
 		return fetch(url.toJSON())
 			.then(resp => resp.json())
+			.then(page => page.items)
 	},
 	getWalletBalances(user: string) {
 		let url = getAbsoluteEndpoint(`wallets/${user}`)
-		return fetch(url.toJSON())
+		return fetchWithSession(url, user)
 			.then(resp => resp.json())
 	},
 	getWithdrawRequests(user: string) {
 		let url = getAbsoluteEndpoint(`wallets/${user}/withdraw_requests`)
-		return fetch(url.toJSON())
+		return fetchWithSession(url, user)
 			.then(resp => resp.json())
 	},
-	getWithdrawSigns(tx_hash: string) {
+	getWithdrawSigns(tx_hash: string, user: string) {
 		let url = getAbsoluteEndpoint(`withdraw_requests/${tx_hash}/signs`)
-		return fetch(url.toJSON())
+		return fetchWithSession(url, user)
 			.then(resp => resp.json())
 	},
+	getSessionToken,
+}
+
+// getSessionToken returns a session of the wallet, asking the connected
+// wallet to sign a login nonce if there is none
+async function getSessionToken(user: string): Promise<string> {
+	const address = user.toLowerCase()
+	const session = sessions[address]
+	if (session && session.expires_at * 1000 > Date.now()) {
+		return session.token
+	}
+
+	const { isConnected, current: wallet } = store.getters.wallet
+	if (!isConnected || wallet.address.toLowerCase() !== address) {
+		throw new Error('Connect the wallet to sign in and try again.')
+	}
+
+	const { message } = await postJSON('auth/nonce', { address })
+	const signature = await wallet.sign(message)
+	sessions[address] = await postJSON('auth/login', { address, signature })
+
+	return sessions[address].token
+}
+
+async function fetchWithSession(url: URL, user: string): Promise<Response> {
+	const token = await getSessionToken(user)
+	const resp = await fetch(url.toJSON(), {
+		headers: { 'Authorization': `Bearer ${token}` }
+	})
+
+	if (resp.status === 401) {
+		// Session expired on server, sign in again on next request
+		delete sessions[user.toLowerCase()]
+	}
+
+	return resp
+}
+
+function postJSON(endpoint: string, body: any) {
+	return fetch(getAbsoluteEndpoint(endpoint).toJSON(), {
+		method: 'POST',
+		headers: { 'Content-Type': 'application/json' },
+		body: JSON.stringify(body),
+	}).then(resp => {
+		if (!resp.ok) {
+			return resp.json().then(err => { throw new Error(err.error) })
+		}
+		return resp.json()
+	})
 }
 
 function getAbsoluteEndpoint(endpoint: string): URL {
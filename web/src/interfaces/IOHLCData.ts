@@ -1,5 +1,6 @@
 interface IOHLCData {
-	date: string
+	// Seconds since Unix epoch, converted to Date for charts
+	date: number | Date
 	open: string
 	high: string
 	low: string
@@ -23,6 +23,7 @@ const actions = {
         let decimal = base.decimal
         
         for (let i = 0, len = data.length; i < len; i++) {
+          data[i].date = new Date(data[i].date * 1000)
           data[i].open = TOKENS.convertBigIntToFixed(data[i].open, decimal)
           data[i].high = TOKENS.convertBigIntToFixed(data[i].high, decimal)
           data[i].low = TOKENS.convertBigIntToFixed(data[i].low, decimal)
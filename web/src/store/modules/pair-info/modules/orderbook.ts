@@ -5,8 +5,8 @@ import APIService from '@/core/api-service'
 
 interface IOrderbookEntry {
   price: string
+  // Volume left to fill
   volume: string
-  volume_filled: string
 }
 
 interface IOrderbookState {
@@ -38,14 +38,12 @@ const actions = {
         for (let i = 0, len = asks.length; i < len; i++) {
           asks[i].price = TOKENS.convertBigIntToFixed(asks[i].price, decimal)
           asks[i].volume = TOKENS.convertBigIntToFixed(asks[i].volume, decimal)
-          asks[i].volume_filled = TOKENS.convertBigIntToFixed(asks[i].volume_filled, decimal)
         }
         data.asks = asks
 
         for (let i = 0, bids = data.bids, len = bids.length; i < len; i++) {
           bids[i].price = TOKENS.convertBigIntToFixed(bids[i].price, decimal)
           bids[i].volume = TOKENS.convertBigIntToFixed(bids[i].volume, decimal)
-          bids[i].volume_filled = TOKENS.convertBigIntToFixed(bids[i].volume_filled, decimal)
         }
 
         commit(COMMIT_PAIR_ORDERBOOK, data)
@@ -80,8 +78,7 @@ const actions = {
     if (!priceMatchFound) {
       orders.push({
         price,
-        volume,
-        volume_filled: 0
+        volume
       })
 
       orders = orders.sort((o1, o2) => {
@@ -117,10 +114,8 @@ const actions = {
     for (let i = 0; i < orders.length; i++) {
       if (orders[i].price == price) {
         let updatedVolume = parseFloat(orders[i].volume) - volumeUnfilled
-        let volumeFilled = parseFloat(orders[i].volume_filled)
-        // let updatedVolumeFilled = parseFloat(orders[i].volume_filled) - parseFloat(volumeFilled)
 
-        if (updatedVolume <= 0 || updatedVolume == volumeFilled) {
+        if (updatedVolume <= 0) {
           removeOrderAtIndex = i
           break
         }
@@ -140,44 +135,13 @@ const actions = {
     }
   },
 
-  [FILL_ORDERBOOK_ORDER] ({ state, commit, rootGetters }, { order }) {
-	  let base = TOKENS.getBySymbol(rootGetters.pairInfo.base)
-    let decimal = base.decimal
-
-    let orders
-
-    let price = TOKENS.convertBigIntToFixed(order.price, decimal)
-    let volumeFilled = TOKENS.convertBigIntToFixed(order.volume_filled || '0', decimal)
-    
-    if (order.is_bid) {
-      orders = JSON.parse(JSON.stringify(state.bids))
-    } else {
-      orders = JSON.parse(JSON.stringify(state.asks))
-    }
-
-    let removeOrderAtIndex = -1
-    for (let i = 0; i < orders.length; i++) {
-      if (orders[i].price == price) {
-        let volume = parseFloat(orders[i].volume)
-        let updatedVolumeFilled = parseFloat(orders[i].volume_filled) + parseFloat(volumeFilled)
-        if (volume <= updatedVolumeFilled) {
-          removeOrderAtIndex = i
-          break
-        }
-        orders[i].volume_filled = parseFloat(updatedVolumeFilled.toFixed(8)).toString()
-        break
-      }
-    }
-
-    if (removeOrderAtIndex >= 0) {
-      orders.splice(removeOrderAtIndex, 1)
-    }
-
-    if (order.is_bid) {
-      commit(COMMIT_ORDERBOOK_BIDS, orders)
-    } else {
-      commit(COMMIT_ORDERBOOK_ASKS, orders)
-    }
+  // Fill updates carry total filled volume of the order rather than the
+  // change, so remaining volume of the level is fetched again
+  [FILL_ORDERBOOK_ORDER] ({ dispatch, rootGetters }) {
+    dispatch(PAIR_ORDERBOOK_GETTER, {
+      token: rootGetters.pairInfo.token,
+      base: rootGetters.pairInfo.base
+    })
   },
 
   // [FILL_ORDERBOOK_ORDER] ({ state, commit, rootGetters }, { trade }) {
@@ -59,7 +59,7 @@ async function withdraw(tx_hash, gasPrice) {
 
   const web3 = wallet.web3()
 
-  let signatures = await APIService.getWithdrawSigns(tx_hash)
+  let signatures = await APIService.getWithdrawSigns(tx_hash, wallet.address)
 
   let vs: any[] = []
   let rs: any[] = []
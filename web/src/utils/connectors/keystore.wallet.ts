@@ -61,7 +61,7 @@ async function unlockWithKeystoreFile(keystoreFile: File, passphrase: string): P
   }
 
   let sign = (data: string): Promise<string> => {
-    return account.sign(data)
+    return Promise.resolve(account.sign(data).signature)
   }
 
   return {
@@ -39,7 +39,7 @@ async function createNewKeystore(passphrase: string): Promise<IWallet> {
   }
 
   let sign = (data: string): Promise<string> => {
-    return account.sign(data)
+    return Promise.resolve(account.sign(data).signature)
   }
 
   return {
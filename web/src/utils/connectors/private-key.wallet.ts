@@ -38,7 +38,7 @@ async function unlockWithPrivateKey(privateKey: string): Promise<IWallet> {
   }
 
   let sign = (data: string): Promise<string> => {
-    return account.sign(data)
+    return Promise.resolve(account.sign(data).signature)
   }
 
   return {